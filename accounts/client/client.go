@@ -0,0 +1,555 @@
+package client
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Ошибки сервера возвращаются как *errs.Error; сравнивать их удобно через errors.Is(err, client.ErrNotFound).
+var (
+	ErrInvalidArgument    = errs.ErrInvalidArgument
+	ErrNotFound           = errs.ErrNotFound
+	ErrAlreadyExists      = errs.ErrAlreadyExists
+	ErrUnavailable        = errs.ErrUnavailable
+	ErrAborted            = errs.ErrAborted
+	ErrFailedPrecondition = errs.ErrFailedPrecondition
+	ErrInternal           = errs.ErrInternal
+)
+
+// Account — аккаунт в том виде, в каком его возвращает API.
+type Account = dto.GetAccountResponse
+
+// Transition — запись истории статусов аккаунта.
+type Transition = dto.TransitionResponse
+
+// Rename — запись истории переименований аккаунта.
+type Rename = dto.RenameResponse
+
+// Hold — резерв части баланса аккаунта.
+type Hold = dto.HoldResponse
+
+// Schedule — расписание перевода.
+type Schedule = dto.ScheduleResponse
+
+// ScheduleRun — исполнение расписания.
+type ScheduleRun = dto.ScheduleRunResponse
+
+// Accrual — пакет начисления процентов.
+type Accrual = dto.AccrualResponse
+
+// LedgerEntry — проводка по счёту.
+type LedgerEntry = dto.LedgerEntryResponse
+
+// Webhook — подписка на события аккаунтов.
+type Webhook = dto.WebhookResponse
+
+// Delivery — доставка события подписке.
+type Delivery = dto.DeliveryResponse
+
+// RequestHook вызывается перед каждой попыткой запроса, например чтобы добавить заголовки.
+type RequestHook func(req *http.Request)
+
+// ResponseHook вызывается после каждой попытки; resp равен nil, если запрос не дошёл до сервера.
+type ResponseHook func(req *http.Request, resp *http.Response, err error)
+
+// Client — типизированный клиент API /v1/accounts.
+// Методы принимают в name ID аккаунта или его имя.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryPolicy
+	onRequest  []RequestHook
+	onResponse []ResponseHook
+}
+
+type Option func(c *Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+func WithRequestHook(hook RequestHook) Option {
+	return func(c *Client) {
+		c.onRequest = append(c.onRequest, hook)
+	}
+}
+
+func WithResponseHook(hook ResponseHook) Option {
+	return func(c *Client) {
+		c.onResponse = append(c.onResponse, hook)
+	}
+}
+
+// WithActor представляет клиента серверу заголовком X-Actor; имя записывается, например, как автор удаления.
+func WithActor(actor string) Option {
+	return WithRequestHook(func(req *http.Request) {
+		req.Header.Set(dto.ActorHeader, actor)
+	})
+}
+
+// ReadOption настраивает чтение аккаунтов.
+type ReadOption func(query url.Values)
+
+// IncludeDeleted показывает удалённые, но ещё не очищенные аккаунты.
+func IncludeDeleted() ReadOption {
+	return func(query url.Values) {
+		query.Set("include_deleted", "true")
+	}
+}
+
+// LabelSelector оставляет аккаунты, метки которых удовлетворяют селектору, например "tier=gold,!legacy".
+func LabelSelector(selector string) ReadOption {
+	return func(query url.Values) {
+		query.Set("label_selector", selector)
+	}
+}
+
+func withQuery(path string, opts []ReadOption) string {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+	if len(query) == 0 {
+		return path
+	}
+
+	return path + "?" + query.Encode()
+}
+
+// New создаёт клиент для сервера по адресу baseURL, например http://localhost:7777.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q: scheme and host are required", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+func (c *Client) List(ctx context.Context, opts ...ReadOption) ([]Account, error) {
+	var response dto.ListAccountsResponse
+	if err := c.do(ctx, http.MethodGet, withQuery("/v1/accounts", opts), nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Accounts, nil
+}
+
+func (c *Client) Get(ctx context.Context, name string, opts ...ReadOption) (Account, error) {
+	var account Account
+	err := c.do(ctx, http.MethodGet, withQuery(accountPath(name), opts), nil, true, &account)
+
+	return account, err
+}
+
+// Create не повторяется автоматически: повтор после таймаута мог бы вернуть already_exists для своего же аккаунта.
+func (c *Client) Create(ctx context.Context, name string, amount int) (Account, error) {
+	var account Account
+	request := dto.CreateAccountRequest{Name: name, Amount: amount}
+	err := c.do(ctx, http.MethodPost, "/v1/accounts", request, false, &account)
+
+	return account, err
+}
+
+// SetAmount устанавливает баланс; запрос идемпотентен и повторяется при сбоях.
+func (c *Client) SetAmount(ctx context.Context, name string, amount int) (Account, error) {
+	var account Account
+	request := dto.UpdateAccountRequest{Amount: &amount}
+	err := c.do(ctx, http.MethodPatch, accountPath(name), request, true, &account)
+
+	return account, err
+}
+
+func (c *Client) Rename(ctx context.Context, name, newName string) (Account, error) {
+	var account Account
+	request := dto.UpdateAccountRequest{Name: &newName}
+	err := c.do(ctx, http.MethodPatch, accountPath(name), request, false, &account)
+
+	return account, err
+}
+
+// Update применяет произвольный PATCH; повторяется, только если имя не меняется.
+// mask — пути update_mask; без них сервер меняет переданные поля.
+func (c *Client) Update(ctx context.Context, name string, request dto.UpdateAccountRequest, mask ...string) (Account, error) {
+	var account Account
+	path := accountPath(name)
+	if len(mask) > 0 {
+		path += "?" + url.Values{"update_mask": {strings.Join(mask, ",")}}.Encode()
+	}
+	idempotent := request.Name == nil
+	err := c.do(ctx, http.MethodPatch, path, request, idempotent, &account)
+
+	return account, err
+}
+
+// Delete удаляет аккаунт мягко: до очистки его можно вернуть через Restore.
+func (c *Client) Delete(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, accountPath(name), nil, true, nil)
+}
+
+// ChangeStatus выполняет смену статуса change — activate, freeze, unfreeze или close.
+// Не повторяется автоматически: повтор после успеха вернул бы failed_precondition.
+func (c *Client) ChangeStatus(ctx context.Context, name, change, reason string) (Account, error) {
+	var account Account
+	err := c.do(ctx, http.MethodPost, accountPath(name)+"/"+change, dto.ChangeStatusRequest{Reason: reason}, false, &account)
+
+	return account, err
+}
+
+// StatusHistory возвращает переходы статусов аккаунта от старых к новым.
+func (c *Client) StatusHistory(ctx context.Context, name string) ([]Transition, error) {
+	var response dto.StatusHistoryResponse
+	if err := c.do(ctx, http.MethodGet, accountPath(name)+"/transitions", nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Transitions, nil
+}
+
+// RenameHistory возвращает переименования аккаунта от старых к новым.
+func (c *Client) RenameHistory(ctx context.Context, name string) ([]Rename, error) {
+	var response dto.RenameHistoryResponse
+	if err := c.do(ctx, http.MethodGet, accountPath(name)+"/renames", nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Renames, nil
+}
+
+// Restore не повторяется автоматически: повтор после успеха вернул бы not_found.
+func (c *Client) Restore(ctx context.Context, name string) (Account, error) {
+	var account Account
+	err := c.do(ctx, http.MethodPost, accountPath(name)+"/restore", nil, false, &account)
+
+	return account, err
+}
+
+// Authorize резервирует amount на время ttl; ttl 0 — срок сервера по умолчанию.
+// Не повторяется автоматически: повтор после таймаута создал бы второй холд.
+func (c *Client) Authorize(ctx context.Context, name string, amount int, ttl time.Duration) (Hold, error) {
+	var hold Hold
+	request := dto.AuthorizeRequest{Amount: amount, TTLSeconds: int(ttl / time.Second)}
+	err := c.do(ctx, http.MethodPost, accountPath(name)+"/holds", request, false, &hold)
+
+	return hold, err
+}
+
+// Capture списывает холд; amount 0 списывает его целиком.
+// Не повторяется автоматически: повтор после успеха вернул бы failed_precondition.
+func (c *Client) Capture(ctx context.Context, holdID string, amount int) (Hold, Account, error) {
+	var response dto.CaptureResponse
+	err := c.do(ctx, http.MethodPost, holdPath(holdID)+"/capture", dto.CaptureRequest{Amount: amount}, false, &response)
+
+	return response.Hold, response.Account, err
+}
+
+// Release снимает холд, не меняя баланс; не повторяется по той же причине, что Capture.
+func (c *Client) Release(ctx context.Context, holdID string) (Hold, error) {
+	var hold Hold
+	err := c.do(ctx, http.MethodPost, holdPath(holdID)+"/release", nil, false, &hold)
+
+	return hold, err
+}
+
+func (c *Client) Hold(ctx context.Context, holdID string) (Hold, error) {
+	var hold Hold
+	err := c.do(ctx, http.MethodGet, holdPath(holdID), nil, true, &hold)
+
+	return hold, err
+}
+
+// Holds возвращает холды аккаунта, включая закрытые, от старых к новым.
+func (c *Client) Holds(ctx context.Context, name string) ([]Hold, error) {
+	var response dto.ListHoldsResponse
+	if err := c.do(ctx, http.MethodGet, accountPath(name)+"/holds", nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Holds, nil
+}
+
+// CreateSchedule не повторяется автоматически: повтор после таймаута завёл бы второе расписание.
+func (c *Client) CreateSchedule(ctx context.Context, request dto.CreateScheduleRequest) (Schedule, error) {
+	var schedule Schedule
+	err := c.do(ctx, http.MethodPost, "/v1/schedules", request, false, &schedule)
+
+	return schedule, err
+}
+
+func (c *Client) Schedule(ctx context.Context, id string) (Schedule, error) {
+	var schedule Schedule
+	err := c.do(ctx, http.MethodGet, schedulePath(id), nil, true, &schedule)
+
+	return schedule, err
+}
+
+// Schedules возвращает расписания аккаунта account, где он источник или получатель; пустой account — все.
+func (c *Client) Schedules(ctx context.Context, account string) ([]Schedule, error) {
+	path := "/v1/schedules"
+	if account != "" {
+		path += "?" + url.Values{"account": {account}}.Encode()
+	}
+	var response dto.ListSchedulesResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Schedules, nil
+}
+
+// ChangeSchedule выполняет действие action — pause, resume или cancel.
+// Не повторяется автоматически: повтор после успеха вернул бы failed_precondition.
+func (c *Client) ChangeSchedule(ctx context.Context, id, action string) (Schedule, error) {
+	var schedule Schedule
+	err := c.do(ctx, http.MethodPost, schedulePath(id)+"/"+action, nil, false, &schedule)
+
+	return schedule, err
+}
+
+// ScheduleRuns возвращает исполнения расписания от старых к новым.
+func (c *Client) ScheduleRuns(ctx context.Context, id string) ([]ScheduleRun, error) {
+	var response dto.ScheduleRunsResponse
+	if err := c.do(ctx, http.MethodGet, schedulePath(id)+"/runs", nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Runs, nil
+}
+
+// AccrueInterest начисляет проценты за период вида daily-2026-10-18 или monthly-2026-10;
+// с dryRun возвращает расчётные проводки, ничего не начисляя. Повтор безопасен: пакет за период один.
+func (c *Client) AccrueInterest(ctx context.Context, period string, dryRun bool) (Accrual, error) {
+	var accrual Accrual
+	err := c.do(ctx, http.MethodPost, "/v1/interest/accruals", dto.AccrueInterestRequest{Period: period, DryRun: dryRun}, true, &accrual)
+
+	return accrual, err
+}
+
+func (c *Client) Accrual(ctx context.Context, id string) (Accrual, error) {
+	var accrual Accrual
+	err := c.do(ctx, http.MethodGet, "/v1/interest/accruals/"+url.PathEscape(id), nil, true, &accrual)
+
+	return accrual, err
+}
+
+// Accruals возвращает пакеты начисления от старых периодов к новым.
+func (c *Client) Accruals(ctx context.Context) ([]Accrual, error) {
+	var response dto.ListAccrualsResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/interest/accruals", nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Accruals, nil
+}
+
+// Ledger возвращает проводки аккаунта от старых к новым.
+func (c *Client) Ledger(ctx context.Context, name string) ([]LedgerEntry, error) {
+	var response dto.LedgerResponse
+	if err := c.do(ctx, http.MethodGet, accountPath(name)+"/ledger", nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Entries, nil
+}
+
+// CreateWebhook не повторяется автоматически: повтор после таймаута завёл бы вторую подписку.
+func (c *Client) CreateWebhook(ctx context.Context, request dto.CreateWebhookRequest) (Webhook, error) {
+	var webhook Webhook
+	err := c.do(ctx, http.MethodPost, "/v1/webhooks", request, false, &webhook)
+
+	return webhook, err
+}
+
+func (c *Client) Webhook(ctx context.Context, id string) (Webhook, error) {
+	var webhook Webhook
+	err := c.do(ctx, http.MethodGet, webhookPath(id), nil, true, &webhook)
+
+	return webhook, err
+}
+
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	var response dto.ListWebhooksResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/webhooks", nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Webhooks, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, webhookPath(id), nil, true, nil)
+}
+
+// Deliveries возвращает доставки подписки от старых к новым; status — pending, delivered, dead или пусто для всех.
+func (c *Client) Deliveries(ctx context.Context, id, status string) ([]Delivery, error) {
+	path := webhookPath(id) + "/deliveries"
+	if status != "" {
+		path += "?" + url.Values{"status": {status}}.Encode()
+	}
+	var response dto.ListDeliveriesResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, true, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Deliveries, nil
+}
+
+// ReplayDelivery ставит доставку заново в очередь; повтор безопасен.
+func (c *Client) ReplayDelivery(ctx context.Context, id, deliveryID string) (Delivery, error) {
+	var delivery Delivery
+	err := c.do(ctx, http.MethodPost, webhookPath(id)+"/deliveries/"+url.PathEscape(deliveryID)+"/replay", nil, true, &delivery)
+
+	return delivery, err
+}
+
+func webhookPath(id string) string {
+	return "/v1/webhooks/" + url.PathEscape(id)
+}
+
+func schedulePath(id string) string {
+	return "/v1/schedules/" + url.PathEscape(id)
+}
+
+func holdPath(id string) string {
+	return "/v1/holds/" + url.PathEscape(id)
+}
+
+func accountPath(name string) string {
+	return "/v1/accounts/" + url.PathEscape(name)
+}
+
+// do выполняет запрос с повторами для идемпотентных вызовов и декодирует ответ в response.
+func (c *Client) do(ctx context.Context, method, path string, request any, idempotent bool, response any) error {
+	var body []byte
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("json marshal failed: %w", err)
+		}
+		body = data
+	}
+
+	attempts := 1
+	if idempotent && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if waitErr := sleep(ctx, c.retry.backoff(attempt)); waitErr != nil {
+				return err
+			}
+		}
+
+		var retryable bool
+		retryable, err = c.attempt(ctx, method, path, body, response)
+		if err == nil || !retryable {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (c *Client) attempt(ctx context.Context, method, path string, body []byte, response any) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, reader)
+	if err != nil {
+		return false, fmt.Errorf("build request failed: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, hook := range c.onRequest {
+		hook(req)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	for _, hook := range c.onResponse {
+		hook(req, resp, err)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		return true, errs.New(errs.Unavailable, fmt.Sprintf("%s %s failed: %v", method, path, err), nil)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return true, fmt.Errorf("read body failed: %w", err)
+		}
+
+		return retryableStatus(resp.StatusCode), errs.FromHTTP(resp.StatusCode, data)
+	}
+
+	if response != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+			return false, fmt.Errorf("json decode failed: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
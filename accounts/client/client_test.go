@@ -0,0 +1,254 @@
+package client_test
+
+import (
+	"awesomeProject/accounts"
+	"awesomeProject/accounts/client"
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/storage"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// fastRetry повторяет без заметных задержек, чтобы тесты повторов шли быстро.
+var fastRetry = client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+// newServer поднимает настоящий сервер аккаунтов в памяти и возвращает клиент к нему.
+func newServer(t *testing.T, opts ...client.Option) *client.Client {
+	t.Helper()
+	e := echo.New()
+	e.HTTPErrorHandler = accounts.ErrorHandler
+	accounts.New(storage.NewMemory()).Register(e)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return newClient(t, server.URL, opts...)
+}
+
+func newClient(t *testing.T, url string, opts ...client.Option) *client.Client {
+	t.Helper()
+	c, err := client.New(url, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// stub отвечает на все запросы ответами responses по очереди, повторяя последний, и считает запросы.
+func stub(t *testing.T, responses ...func(w http.ResponseWriter)) (string, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(calls.Add(1)) - 1
+		responses[min(i, len(responses)-1)](w)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, &calls
+}
+
+func reply(status int, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, url := range []string{"", "localhost:7777", "/v1", "://bad"} {
+		if _, err := client.New(url); err == nil {
+			t.Errorf("New(%q) succeeded, want an error", url)
+		}
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	ctx := context.Background()
+	c := newServer(t, client.WithActor("auditor"))
+
+	created, err := c.Create(ctx, "alice", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.Name != "alice" || created.Amount != 100 || created.Status != "active" {
+		t.Fatalf("created = %+v", created)
+	}
+	if _, err := c.Create(ctx, "bob", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "alice" {
+		t.Fatalf("get by ID = %+v", got)
+	}
+	if account, err := c.SetAmount(ctx, "alice", 150); err != nil || account.Amount != 150 {
+		t.Fatalf("set amount = %+v, %v", account, err)
+	}
+	if account, err := c.Rename(ctx, "alice", "carol"); err != nil || account.Name != "carol" || account.ID != created.ID {
+		t.Fatalf("rename = %+v, %v", account, err)
+	}
+
+	// Маска меняет только перечисленные поля: amount из тела игнорируется.
+	amount := 1
+	description := "savings"
+	updated, err := c.Update(ctx, "carol", dto.UpdateAccountRequest{Amount: &amount, Description: &description, Labels: map[string]string{"tier": "gold"}},
+		"description", "labels.tier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Amount != 150 || updated.Description != "savings" || !reflect.DeepEqual(updated.Labels, map[string]string{"tier": "gold"}) {
+		t.Fatalf("update = %+v", updated)
+	}
+
+	list, err := c.List(ctx, client.LabelSelector("tier=gold"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("list tier=gold = %+v, want carol", list)
+	}
+
+	if err := c.Delete(ctx, "carol"); err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := c.Get(ctx, "carol", client.IncludeDeleted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.DeletedBy != "auditor" || deleted.DeletedAt == nil {
+		t.Fatalf("deleted = %+v, want deleted by the actor", deleted)
+	}
+	if restored, err := c.Restore(ctx, "carol"); err != nil || restored.DeletedAt != nil {
+		t.Fatalf("restore = %+v, %v", restored, err)
+	}
+}
+
+func TestHolds(t *testing.T) {
+	ctx := context.Background()
+	c := newServer(t)
+	if _, err := c.Create(ctx, "alice", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	hold, err := c.Authorize(ctx, "alice", 60, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hold.Amount != 60 || hold.Status != "active" {
+		t.Fatalf("hold = %+v", hold)
+	}
+	captured, account, err := c.Capture(ctx, hold.ID, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if captured.Status != "captured" || account.Amount != 75 || account.Held != 0 {
+		t.Fatalf("capture = %+v, %+v", captured, account)
+	}
+	if holds, err := c.Holds(ctx, "alice"); err != nil || len(holds) != 1 || holds[0].ID != hold.ID {
+		t.Fatalf("holds = %+v, %v", holds, err)
+	}
+}
+
+// TestServerErrors: ответы сервера с ошибкой приходят как *errs.Error с кодом, деталями и нарушениями.
+func TestServerErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newServer(t)
+
+	_, err := c.Get(ctx, "nobody")
+	var e *errs.Error
+	if !errors.Is(err, client.ErrNotFound) || !errors.As(err, &e) || e.Details["name"] != "nobody" {
+		t.Fatalf("get of a missing account = %#v, want not_found for nobody", err)
+	}
+
+	_, err = c.Create(ctx, "", -1)
+	if !errors.As(err, &e) || e.Code != errs.InvalidArgument || len(e.Violations) != 2 {
+		t.Fatalf("create with bad fields = %#v, want invalid_argument with two violations", err)
+	}
+
+	if _, err := c.Create(ctx, "alice", 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Create(ctx, "alice", 10); !errors.Is(err, client.ErrAlreadyExists) {
+		t.Fatalf("second create = %v, want already_exists", err)
+	}
+	if _, err := c.Authorize(ctx, "alice", 11, 0); !errors.Is(err, client.ErrFailedPrecondition) {
+		t.Fatalf("authorize over the balance = %v, want failed_precondition", err)
+	}
+}
+
+func TestErrorDecoding(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		response func(w http.ResponseWriter)
+		want     *errs.Error
+	}{
+		{
+			name:     "problem",
+			response: reply(http.StatusUnprocessableEntity, `{"code":"failed_precondition","message":"account \"alice\" is frozen","details":{"name":"alice"}}`),
+			want:     &errs.Error{Code: errs.FailedPrecondition, Message: `account "alice" is frozen`, Details: map[string]string{"name": "alice"}},
+		},
+		{
+			name:     "violations",
+			response: reply(http.StatusBadRequest, `{"code":"invalid_argument","message":"name: must not be empty","violations":[{"field":"name","rule":"required","description":"must not be empty"}]}`),
+			want: &errs.Error{Code: errs.InvalidArgument, Message: "name: must not be empty",
+				Violations: []errs.FieldViolation{{Field: "name", Rule: "required", Description: "must not be empty"}}},
+		},
+		{
+			name:     "not a problem",
+			response: reply(http.StatusNotFound, "404 page not found\n"),
+			want:     &errs.Error{Code: errs.NotFound, Message: "404 page not found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, _ := stub(t, tt.response)
+			_, err := newClient(t, url).Get(ctx, "alice")
+			if !reflect.DeepEqual(err, tt.want) {
+				t.Fatalf("Get() = %#v, want %#v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	unavailable := reply(http.StatusServiceUnavailable, `{"code":"unavailable","message":"try later"}`)
+	ok := reply(http.StatusOK, `{"id":"1","name":"alice","amount":10}`)
+
+	url, calls := stub(t, unavailable, unavailable, ok)
+	account, err := newClient(t, url, client.WithRetryPolicy(fastRetry)).Get(ctx, "alice")
+	if err != nil || account.Name != "alice" || calls.Load() != 3 {
+		t.Fatalf("get = %+v, %v after %d calls; want alice after 3", account, err, calls.Load())
+	}
+
+	url, calls = stub(t, unavailable)
+	if _, err := newClient(t, url, client.WithRetryPolicy(fastRetry)).Get(ctx, "alice"); !errors.Is(err, client.ErrUnavailable) || calls.Load() != 3 {
+		t.Fatalf("get = %v after %d calls; want unavailable after 3", err, calls.Load())
+	}
+
+	// Create не идемпотентен и не повторяется.
+	url, calls = stub(t, unavailable, ok)
+	if _, err := newClient(t, url, client.WithRetryPolicy(fastRetry)).Create(ctx, "alice", 10); !errors.Is(err, client.ErrUnavailable) || calls.Load() != 1 {
+		t.Fatalf("create = %v after %d calls; want unavailable after 1", err, calls.Load())
+	}
+
+	// Ошибки клиента не повторяются.
+	url, calls = stub(t, reply(http.StatusNotFound, `{"code":"not_found","message":"no"}`), ok)
+	if _, err := newClient(t, url, client.WithRetryPolicy(fastRetry)).Get(ctx, "alice"); !errors.Is(err, client.ErrNotFound) || calls.Load() != 1 {
+		t.Fatalf("get = %v after %d calls; want not_found after 1", err, calls.Load())
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy задаёт повторы идемпотентных запросов при сетевых ошибках и ответах 429/502/503/504.
+type RetryPolicy struct {
+	// MaxAttempts — общее число попыток, включая первую; 1 отключает повторы.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// NoRetry отключает повторы.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// backoff — экспоненциальная задержка перед попыткой attempt (начиная с 1) с полным джиттером.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff << (attempt - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
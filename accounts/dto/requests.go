@@ -1,8 +1,31 @@
 package dto
 
+import "time"
+
+// ActorHeader — заголовок с именем того, кто выполняет запрос; записывается, например, как автор удаления.
+const ActorHeader = "X-Actor"
+
 type CreateAccountRequest struct {
 	Name   string `json:"name"`
 	Amount int    `json:"amount"`
+	// Status — pending или active; по умолчанию active.
+	Status      string            `json:"status,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// ChangeStatusRequest — тело запросов activate, freeze, unfreeze и close.
+type ChangeStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+// UpdateAccountRequest — тело PATCH /v1/accounts/{name}. Меняются поля из параметра update_mask,
+// а без него — переданные поля; переданные метки тогда заменяют все прежние.
+type UpdateAccountRequest struct {
+	Name        *string           `json:"name,omitempty"`
+	Amount      *int              `json:"amount,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
 }
 
 type PatchAccountRequest struct {
@@ -18,3 +41,42 @@ type ChangeAccountRequest struct {
 type DeleteAccountRequest struct {
 	Name string `json:"name"`
 }
+
+// AuthorizeRequest — тело POST /v1/accounts/{name}/holds.
+type AuthorizeRequest struct {
+	Amount int `json:"amount"`
+	// TTLSeconds — через сколько секунд холд истечёт; по умолчанию 15 минут.
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+}
+
+// CaptureRequest — тело POST /v1/holds/{hold_id}/capture; без amount списывается весь холд.
+type CaptureRequest struct {
+	Amount int `json:"amount,omitempty"`
+}
+
+// CreateScheduleRequest — тело POST /v1/schedules. Без cron перевод разовый и выполняется в run_at;
+// с cron run_at — начало расписания, по умолчанию сейчас.
+type CreateScheduleRequest struct {
+	From        string     `json:"from"`
+	To          string     `json:"to"`
+	Amount      int        `json:"amount"`
+	Cron        string     `json:"cron,omitempty"`
+	RunAt       *time.Time `json:"run_at,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+// AccrueInterestRequest — тело POST /v1/interest/accruals; period — daily-YYYY-MM-DD или monthly-YYYY-MM.
+// С dry_run ответ показывает расчётные проводки по текущим балансам и ничего не начисляет.
+type AccrueInterestRequest struct {
+	Period string `json:"period"`
+	DryRun bool   `json:"dry_run,omitempty"`
+}
+
+// CreateWebhookRequest — тело POST /v1/webhooks. Пустые event_types и accounts означают все события
+// и все аккаунты; без secret сервер сгенерирует его сам.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types,omitempty"`
+	Accounts   []string `json:"accounts,omitempty"`
+	Secret     string   `json:"secret,omitempty"`
+}
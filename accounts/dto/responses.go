@@ -1,6 +1,200 @@
 package dto
 
+import "time"
+
 type GetAccountResponse struct {
+	// ID выдаётся при создании и не меняется при переименовании.
+	ID     string `json:"id"`
 	Name   string `json:"name"`
 	Amount int    `json:"amount"`
+	// Held — сумма активных холдов, Available — баланс, доступный для списаний.
+	Held      int    `json:"held"`
+	Available int    `json:"available"`
+	Status    string `json:"status"`
+	// DeletedAt и DeletedBy есть только у удалённых аккаунтов, запрошенных с include_deleted.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+	// RedirectedFrom — старое имя из запроса, если аккаунт найден по нему после переименования.
+	RedirectedFrom string            `json:"redirected_from,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+type ListAccountsResponse struct {
+	Accounts []GetAccountResponse `json:"accounts"`
+}
+
+// TransitionResponse — запись истории статусов.
+type TransitionResponse struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Reason string    `json:"reason"`
+	Actor  string    `json:"actor"`
+	At     time.Time `json:"at"`
+}
+
+type StatusHistoryResponse struct {
+	Transitions []TransitionResponse `json:"transitions"`
+}
+
+// RenameResponse — запись истории переименований.
+type RenameResponse struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+type RenameHistoryResponse struct {
+	Renames []RenameResponse `json:"renames"`
+}
+
+// HoldResponse — холд: резерв части баланса аккаунта.
+type HoldResponse struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+	Amount    int    `json:"amount"`
+	// Status — active, captured, released или expired.
+	Status    string    `json:"status"`
+	Captured  int       `json:"captured"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// ClosedAt есть только у закрытого холда.
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+}
+
+type ListHoldsResponse struct {
+	Holds []HoldResponse `json:"holds"`
+}
+
+// CaptureResponse — списанный холд и аккаунт после списания.
+type CaptureResponse struct {
+	Hold    HoldResponse       `json:"hold"`
+	Account GetAccountResponse `json:"account"`
+}
+
+// ScheduleResponse — расписание перевода; from и to — ID аккаунтов.
+type ScheduleResponse struct {
+	ID          string `json:"id"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Amount      int    `json:"amount"`
+	Cron        string `json:"cron,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Status — active, paused, cancelled или completed.
+	Status string `json:"status"`
+	// NextRunAt нет у отменённого и завершённого расписания.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type ListSchedulesResponse struct {
+	Schedules []ScheduleResponse `json:"schedules"`
+}
+
+// ScheduleRunResponse — исполнение расписания за срок due_at.
+type ScheduleRunResponse struct {
+	DueAt time.Time `json:"due_at"`
+	// Status — succeeded или failed.
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+	RanAt  time.Time `json:"ran_at"`
+}
+
+type ScheduleRunsResponse struct {
+	Runs []ScheduleRunResponse `json:"runs"`
+}
+
+// AccrualResponse — пакет начисления процентов за период [period_start, period_end).
+type AccrualResponse struct {
+	ID          string    `json:"id"`
+	Compounding string    `json:"compounding"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// Status — running или completed; у расчёта dry_run его нет.
+	Status string `json:"status,omitempty"`
+	// Cursor — ID последнего обработанного аккаунта.
+	Cursor     string     `json:"cursor,omitempty"`
+	Postings   int        `json:"postings"`
+	Total      int        `json:"total"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DryRun     bool       `json:"dry_run,omitempty"`
+	// Projected — расчётные проводки dry_run по всем аккаунтам пакета, включая нулевые.
+	Projected []PostingResponse `json:"projected,omitempty"`
+}
+
+// PostingResponse — расчёт процентов по аккаунту; rate_bps — годовая ставка в базисных пунктах.
+type PostingResponse struct {
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Balance   int    `json:"balance"`
+	RateBPS   int    `json:"rate_bps"`
+	Amount    int    `json:"amount"`
+}
+
+type ListAccrualsResponse struct {
+	Accruals []AccrualResponse `json:"accruals"`
+}
+
+// LedgerEntryResponse — проводка по счёту; balance — баланс после неё, reference — её источник.
+type LedgerEntryResponse struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Amount    int       `json:"amount"`
+	Balance   int       `json:"balance"`
+	Reference string    `json:"reference"`
+	PostedAt  time.Time `json:"posted_at"`
+}
+
+type LedgerResponse struct {
+	Entries []LedgerEntryResponse `json:"entries"`
+}
+
+// WebhookResponse — подписка на события; accounts — ID аккаунтов.
+type WebhookResponse struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Accounts   []string `json:"accounts"`
+	// Secret возвращается только при создании подписки.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// DeliveryResponse — доставка события подписке.
+type DeliveryResponse struct {
+	ID        string `json:"id"`
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	AccountID string `json:"account_id"`
+	// Status — pending, delivered или dead.
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	// LastError и LastStatusCode — итог последней неудачной попытки.
+	LastError      string     `json:"last_error,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+type ListDeliveriesResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+}
+
+// EventResponse — тело запроса вебхука: событие и аккаунт после изменения.
+type EventResponse struct {
+	ID         string             `json:"id"`
+	Sequence   int64              `json:"sequence"`
+	Type       string             `json:"type"`
+	OccurredAt time.Time          `json:"occurred_at"`
+	Account    GetAccountResponse `json:"account"`
 }
@@ -0,0 +1,40 @@
+package accounts
+
+import (
+	"awesomeProject/accounts/errs"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+func writeError(c echo.Context, err error) error {
+	status, problem := errs.ToProblem(err)
+	if problem.Code == errs.Internal {
+		c.Logger().Error(err)
+	}
+
+	return c.JSON(status, problem)
+}
+
+// ErrorHandler отвечает в формате errs.Problem и на ошибки самого echo (неизвестный маршрут, неверный метод).
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		err = errs.FromHTTP(httpErr.Code, []byte(fmt.Sprint(httpErr.Message)))
+	}
+
+	if c.Request().Method == http.MethodHead {
+		status, _ := errs.ToProblem(err)
+		err = c.NoContent(status)
+	} else {
+		err = writeError(c, err)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
@@ -0,0 +1,202 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Code — стабильный код ошибки, одинаковый для HTTP и gRPC.
+type Code string
+
+const (
+	InvalidArgument Code = "invalid_argument"
+	NotFound        Code = "not_found"
+	AlreadyExists   Code = "already_exists"
+	Unavailable     Code = "unavailable"
+	// Aborted — операция не прошла из-за конфликта с параллельными изменениями; её можно повторить.
+	Aborted Code = "aborted"
+	// FailedPrecondition — операция запрещена в текущем состоянии аккаунта, например он заморожен.
+	FailedPrecondition Code = "failed_precondition"
+	Internal           Code = "internal"
+)
+
+// Codes — все коды каталога; новые коды добавляются и сюда.
+var Codes = []Code{InvalidArgument, NotFound, AlreadyExists, Unavailable, Aborted, FailedPrecondition, Internal}
+
+// Error — доменная ошибка с кодом из каталога и дополнительными деталями.
+type Error struct {
+	Code       Code
+	Message    string
+	Details    map[string]string
+	Violations []FieldViolation
+}
+
+// FieldViolation — нарушенное правило валидации конкретного поля запроса.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is сравнивает ошибки по коду, так что errors.Is(err, errs.ErrNotFound) работает для любой not_found ошибки.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+
+	return ok && t.Code == e.Code
+}
+
+var (
+	ErrInvalidArgument    = &Error{Code: InvalidArgument, Message: "invalid argument"}
+	ErrNotFound           = &Error{Code: NotFound, Message: "not found"}
+	ErrAlreadyExists      = &Error{Code: AlreadyExists, Message: "already exists"}
+	ErrUnavailable        = &Error{Code: Unavailable, Message: "service unavailable"}
+	ErrAborted            = &Error{Code: Aborted, Message: "aborted due to a concurrent change"}
+	ErrFailedPrecondition = &Error{Code: FailedPrecondition, Message: "not allowed in the current state"}
+	ErrInternal           = &Error{Code: Internal, Message: "internal error"}
+)
+
+func New(code Code, message string, details map[string]string) *Error {
+	return &Error{Code: code, Message: message, Details: details}
+}
+
+func AccountNotFound(name string) *Error {
+	return New(NotFound, fmt.Sprintf("account %q not found", name), map[string]string{"name": name})
+}
+
+func AccountAlreadyExists(name string) *Error {
+	return New(AlreadyExists, fmt.Sprintf("account %q already exists", name), map[string]string{"name": name})
+}
+
+// AccountDeleted — имя занято удалённым аккаунтом, который ещё не очищен.
+func AccountDeleted(name string) *Error {
+	return New(AlreadyExists, fmt.Sprintf("account %q is deleted; restore it or wait until it is purged", name), map[string]string{"name": name})
+}
+
+// DeletedAccountNotFound — нет удалённого аккаунта, который можно восстановить.
+func DeletedAccountNotFound(name string) *Error {
+	return New(NotFound, fmt.Sprintf("deleted account %q not found", name), map[string]string{"name": name})
+}
+
+// InsufficientFunds — на счёте меньше, чем нужно списать. Запрос сам по себе верен,
+// мешает состояние счёта, поэтому код — FailedPrecondition.
+func InsufficientFunds(name string, balance, amount int) *Error {
+	return New(FailedPrecondition, fmt.Sprintf("account %q has %d, cannot debit %d", name, balance, amount), map[string]string{
+		"name":    name,
+		"balance": fmt.Sprint(balance),
+		"amount":  fmt.Sprint(amount),
+	})
+}
+
+func HoldNotFound(id string) *Error {
+	return New(NotFound, fmt.Sprintf("hold %q not found", id), map[string]string{"hold_id": id})
+}
+
+// HoldClosed — холд уже списан, снят или истёк.
+func HoldClosed(id, status string) *Error {
+	return New(FailedPrecondition, fmt.Sprintf("hold %q is %s", id, status), map[string]string{
+		"hold_id": id,
+		"status":  status,
+	})
+}
+
+func ScheduleNotFound(id string) *Error {
+	return New(NotFound, fmt.Sprintf("schedule %q not found", id), map[string]string{"schedule_id": id})
+}
+
+// ScheduleStatus — расписание в статусе status нельзя изменить действием action, например возобновить отменённое.
+func ScheduleStatus(id, status, action string) *Error {
+	return New(FailedPrecondition, fmt.Sprintf("schedule %q is %s, cannot %s", id, status, action), map[string]string{
+		"schedule_id": id,
+		"status":      status,
+		"action":      action,
+	})
+}
+
+func AccrualNotFound(id string) *Error {
+	return New(NotFound, fmt.Sprintf("accrual %q not found", id), map[string]string{"accrual_id": id})
+}
+
+// AccrualCompleted — в завершённый пакет начисления нельзя добавить проводки.
+func AccrualCompleted(id string) *Error {
+	return New(FailedPrecondition, fmt.Sprintf("accrual %q is completed", id), map[string]string{"accrual_id": id})
+}
+
+// InterestDisabled — сервер запущен без таблиц процентных ставок.
+func InterestDisabled() *Error {
+	return New(FailedPrecondition, "interest accrual is not configured", nil)
+}
+
+func SubscriptionNotFound(id string) *Error {
+	return New(NotFound, fmt.Sprintf("webhook %q not found", id), map[string]string{"webhook_id": id})
+}
+
+func DeliveryNotFound(id string) *Error {
+	return New(NotFound, fmt.Sprintf("delivery %q not found", id), map[string]string{"delivery_id": id})
+}
+
+// AccountStatus — статус аккаунта запрещает операцию action, например «debit» у замороженного.
+func AccountStatus(name, status, action string) *Error {
+	return New(FailedPrecondition, fmt.Sprintf("account %q is %s, cannot %s", name, status, action), map[string]string{
+		"name":   name,
+		"status": status,
+		"action": action,
+	})
+}
+
+// AccountHasHolds — аккаунт с активными холдами нельзя закрыть или удалить действием action:
+// холды нужно сначала списать или снять.
+func AccountHasHolds(name string, held int, action string) *Error {
+	return New(FailedPrecondition, fmt.Sprintf("account %q has %d on hold, capture or release the holds before %s", name, held, action), map[string]string{
+		"name":   name,
+		"held":   fmt.Sprint(held),
+		"action": action,
+	})
+}
+
+func InvalidField(field, message string) *Error {
+	return Invalid([]FieldViolation{{Field: field, Rule: "required", Description: message}})
+}
+
+// Invalid собирает все нарушения валидации в одну ошибку invalid_argument.
+func Invalid(violations []FieldViolation) *Error {
+	message := violations[0].Field + ": " + violations[0].Description
+	if len(violations) > 1 {
+		message = fmt.Sprintf("%s (and %d more violations)", message, len(violations)-1)
+	}
+
+	return &Error{Code: InvalidArgument, Message: message, Violations: violations}
+}
+
+// From достаёт доменную ошибку из цепочки; всё, что не из каталога, становится internal.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return ErrInternal
+}
+
+// Format возвращает текст ошибки вместе со списком нарушений — для вывода в CLI.
+func Format(err error) string {
+	var b strings.Builder
+	b.WriteString(err.Error())
+
+	var e *Error
+	if errors.As(err, &e) {
+		for _, v := range e.Violations {
+			fmt.Fprintf(&b, "\n  - %s: %s (%s)", v.Field, v.Description, v.Rule)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// catalog — ожидаемое отображение каждого кода каталога в HTTP и gRPC.
+var catalog = []struct {
+	code Code
+	http int
+	grpc codes.Code
+}{
+	{code: InvalidArgument, http: http.StatusBadRequest, grpc: codes.InvalidArgument},
+	{code: NotFound, http: http.StatusNotFound, grpc: codes.NotFound},
+	{code: AlreadyExists, http: http.StatusConflict, grpc: codes.AlreadyExists},
+	{code: Unavailable, http: http.StatusServiceUnavailable, grpc: codes.Unavailable},
+	{code: Aborted, http: http.StatusConflict, grpc: codes.Aborted},
+	{code: FailedPrecondition, http: http.StatusUnprocessableEntity, grpc: codes.FailedPrecondition},
+	{code: Internal, http: http.StatusInternalServerError, grpc: codes.Internal},
+}
+
+// sample — ошибка кода code со всеми частями, которые должны пережить передачу.
+func sample(code Code) *Error {
+	return &Error{
+		Code:       code,
+		Message:    fmt.Sprintf("%s happened", code),
+		Details:    map[string]string{"name": "alice"},
+		Violations: []FieldViolation{{Field: "amount", Rule: "min", Description: "must be at least 0"}},
+	}
+}
+
+func TestCatalogCoversCodes(t *testing.T) {
+	if len(catalog) != len(Codes) {
+		t.Fatalf("catalog test covers %d codes, errs.Codes has %d", len(catalog), len(Codes))
+	}
+	for i, c := range catalog {
+		if Codes[i] != c.code {
+			t.Fatalf("code %d is %s, want %s", i, Codes[i], c.code)
+		}
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	for _, c := range catalog {
+		t.Run(string(c.code), func(t *testing.T) {
+			if got := HTTPStatus(c.code); got != c.http {
+				t.Fatalf("HTTPStatus() = %d, want %d", got, c.http)
+			}
+
+			want := sample(c.code)
+			code, problem := ToProblem(fmt.Errorf("handler: %w", want))
+			if code != c.http {
+				t.Fatalf("ToProblem() status = %d, want %d", code, c.http)
+			}
+			body, err := json.Marshal(problem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := FromHTTP(code, body); !reflect.DeepEqual(got, want) {
+				t.Fatalf("FromHTTP(ToProblem()) = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	for _, c := range catalog {
+		t.Run(string(c.code), func(t *testing.T) {
+			if got := GRPCCode(c.code); got != c.grpc {
+				t.Fatalf("GRPCCode() = %s, want %s", got, c.grpc)
+			}
+
+			want := sample(c.code)
+			st := ToStatus(fmt.Errorf("handler: %w", want))
+			if got := status.Code(st); got != c.grpc {
+				t.Fatalf("ToStatus() code = %s, want %s", got, c.grpc)
+			}
+			if got := FromStatus(st); !reflect.DeepEqual(got, want) {
+				t.Fatalf("FromStatus(ToStatus()) = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+// TestForeignErrors: ошибки не из каталога отдаются как internal, а ответы без Problem
+// или без ErrorInfo получают код по статусу.
+func TestForeignErrors(t *testing.T) {
+	code, problem := ToProblem(errors.New("disk is full"))
+	if code != http.StatusInternalServerError || problem.Code != Internal || problem.Message != ErrInternal.Message {
+		t.Fatalf("ToProblem() = %d, %+v; want internal without the cause", code, problem)
+	}
+	if got := FromStatus(ToStatus(errors.New("disk is full"))); !errors.Is(got, ErrInternal) {
+		t.Fatalf("FromStatus(ToStatus()) = %v, want internal", got)
+	}
+
+	tests := []struct {
+		status int
+		want   Code
+	}{
+		{status: http.StatusBadRequest, want: InvalidArgument},
+		{status: http.StatusUnauthorized, want: InvalidArgument},
+		{status: http.StatusNotFound, want: NotFound},
+		{status: http.StatusConflict, want: AlreadyExists},
+		{status: http.StatusUnprocessableEntity, want: FailedPrecondition},
+		{status: http.StatusBadGateway, want: Unavailable},
+		{status: http.StatusServiceUnavailable, want: Unavailable},
+		{status: http.StatusGatewayTimeout, want: Unavailable},
+		{status: http.StatusInternalServerError, want: Internal},
+	}
+	for _, tt := range tests {
+		got := From(FromHTTP(tt.status, []byte("<html>proxy error</html>")))
+		if got.Code != tt.want || got.Message != "<html>proxy error</html>" {
+			t.Errorf("FromHTTP(%d) = %+v, want %s", tt.status, got, tt.want)
+		}
+	}
+	if got := From(FromHTTP(http.StatusNotFound, nil)); got.Message != http.StatusText(http.StatusNotFound) {
+		t.Errorf("FromHTTP() without a body = %+v, want the status text", got)
+	}
+
+	grpcTests := []struct {
+		code codes.Code
+		want Code
+	}{
+		{code: codes.OutOfRange, want: InvalidArgument},
+		{code: codes.DeadlineExceeded, want: Unavailable},
+		{code: codes.PermissionDenied, want: Internal},
+	}
+	for _, tt := range grpcTests {
+		if got := From(FromStatus(status.Error(tt.code, "plain"))); got.Code != tt.want {
+			t.Errorf("FromStatus(%s) = %+v, want %s", tt.code, got, tt.want)
+		}
+	}
+}
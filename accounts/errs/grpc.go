@@ -0,0 +1,140 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const domain = "accounts"
+
+func GRPCCode(code Code) codes.Code {
+	switch code {
+	case InvalidArgument:
+		return codes.InvalidArgument
+	case NotFound:
+		return codes.NotFound
+	case AlreadyExists:
+		return codes.AlreadyExists
+	case Unavailable:
+		return codes.Unavailable
+	case Aborted:
+		return codes.Aborted
+	case FailedPrecondition:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+func codeFromGRPC(code codes.Code) Code {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return InvalidArgument
+	case codes.NotFound:
+		return NotFound
+	case codes.AlreadyExists:
+		return AlreadyExists
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return Unavailable
+	case codes.Aborted:
+		return Aborted
+	case codes.FailedPrecondition:
+		return FailedPrecondition
+	default:
+		return Internal
+	}
+}
+
+// ToStatus превращает ошибку в gRPC статус с errdetails.ErrorInfo, где Reason — код из каталога.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		e = ErrInternal
+	}
+
+	st := status.New(GRPCCode(e.Code), e.Message)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   strings.ToUpper(string(e.Code)),
+		Domain:   domain,
+		Metadata: e.Details,
+	}}
+	if len(e.Violations) > 0 {
+		// В BadRequest нет поля для имени правила, поэтому оно передаётся префиксом "rule: description".
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range e.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Rule + ": " + v.Description,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// FromStatus восстанавливает доменную ошибку из ответа gRPC сервера.
+func FromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	e := New(codeFromGRPC(st.Code()), st.Message(), nil)
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() == domain {
+				e.Code = Code(strings.ToLower(detail.GetReason()))
+				e.Details = detail.GetMetadata()
+			}
+		case *errdetails.BadRequest:
+			for _, v := range detail.GetFieldViolations() {
+				rule, description, ok := strings.Cut(v.GetDescription(), ": ")
+				if !ok {
+					rule, description = "", v.GetDescription()
+				}
+				e.Violations = append(e.Violations, FieldViolation{Field: v.GetField(), Rule: rule, Description: description})
+			}
+		}
+	}
+
+	return e
+}
+
+// UnaryServerInterceptor переводит доменные ошибки обработчиков в gRPC статусы.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		if From(err).Code == Internal {
+			log.Printf("%s failed: %v", info.FullMethod, err)
+		}
+
+		return nil, ToStatus(err)
+	}
+
+	return resp, nil
+}
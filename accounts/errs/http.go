@@ -0,0 +1,76 @@
+package errs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Problem — JSON тело ответа с ошибкой.
+type Problem struct {
+	Code       Code              `json:"code"`
+	Message    string            `json:"message"`
+	Details    map[string]string `json:"details,omitempty"`
+	Violations []FieldViolation  `json:"violations,omitempty"`
+}
+
+func HTTPStatus(code Code) int {
+	switch code {
+	case InvalidArgument:
+		return http.StatusBadRequest
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists, Aborted:
+		return http.StatusConflict
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case FailedPrecondition:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func codeFromHTTPStatus(status int) Code {
+	switch {
+	case status == http.StatusNotFound:
+		return NotFound
+	case status == http.StatusConflict:
+		return AlreadyExists
+	case status == http.StatusUnprocessableEntity:
+		return FailedPrecondition
+	case status == http.StatusServiceUnavailable || status == http.StatusBadGateway || status == http.StatusGatewayTimeout:
+		return Unavailable
+	case status >= 400 && status < 500:
+		return InvalidArgument
+	default:
+		return Internal
+	}
+}
+
+// ToProblem возвращает HTTP статус и тело ответа для ошибки.
+func ToProblem(err error) (int, Problem) {
+	e := From(err)
+
+	return HTTPStatus(e.Code), Problem{
+		Code:       e.Code,
+		Message:    e.Message,
+		Details:    e.Details,
+		Violations: e.Violations,
+	}
+}
+
+// FromHTTP разбирает ответ сервера с ошибкой; если тело не Problem, код выводится из статуса.
+func FromHTTP(status int, body []byte) error {
+	var problem Problem
+	if err := json.Unmarshal(body, &problem); err == nil && problem.Code != "" {
+		return &Error{Code: problem.Code, Message: problem.Message, Details: problem.Details, Violations: problem.Violations}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	return New(codeFromHTTPStatus(status), message, nil)
+}
@@ -0,0 +1,59 @@
+// Package events доставляет события об изменениях аккаунтов.
+//
+// Хранилище пишет событие (models.Event) в outbox вместе с самим изменением, а Relay забирает
+// недоставленные события по порядку и передаёт их Publisher: шине в процессе (Bus), файлу (File)
+// или диспетчеру вебхуков (webhook.Dispatcher). Доставка — хотя бы один раз: событие, переданное
+// перед сбоем, но не отмеченное доставленным, передаётся снова, так что получатели отсеивают повторы по ID.
+package events
+
+import (
+	"awesomeProject/accounts/models"
+	"context"
+	"errors"
+	"sync"
+)
+
+// Publisher принимает события; Publish не должен надолго задерживать ретрансляцию.
+type Publisher interface {
+	Publish(ctx context.Context, event models.Event) error
+}
+
+// PublisherFunc позволяет подписать на Bus обычную функцию.
+type PublisherFunc func(ctx context.Context, event models.Event) error
+
+func (f PublisherFunc) Publish(ctx context.Context, event models.Event) error {
+	return f(ctx, event)
+}
+
+// Bus — шина в процессе: передаёт каждое событие всем подписчикам в порядке подписки.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []Publisher
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(subscriber Publisher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, subscriber)
+}
+
+// Publish передаёт событие всем подписчикам, даже если кто-то из них отказал, и возвращает отказы вместе.
+// После отказа Relay передаст событие снова, и его получат повторно и те, кто принял его в первый раз.
+func (b *Bus) Publish(ctx context.Context, event models.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var failed []error
+	for _, subscriber := range b.subscribers {
+		if err := subscriber.Publish(ctx, event); err != nil {
+			failed = append(failed, err)
+		}
+	}
+
+	return errors.Join(failed...)
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"awesomeProject/accounts"
+	"awesomeProject/accounts/models"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// File дописывает события в файл, по строке JSON на событие в формате тела вебхука (accounts.EventPayload).
+type File struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFile открывает файл на дозапись, создавая его при необходимости.
+func OpenFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+
+	return &File{file: file}, nil
+}
+
+// Publish возвращается только после сброса строки на диск: отмеченное доставленным событие не теряется.
+func (f *File) Publish(_ context.Context, event models.Event) error {
+	payload, err := accounts.EventPayload(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync event log: %w", err)
+	}
+
+	return nil
+}
+
+func (f *File) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,116 @@
+package events
+
+import (
+	"awesomeProject/accounts/storage"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	DefaultInterval  = time.Second
+	DefaultBatchSize = 100
+	// DefaultRetention — сколько доставленные события хранятся в outbox до очистки.
+	DefaultRetention = 24 * time.Hour
+)
+
+// Relay передаёт события из outbox хранилища в Publisher. На базу рассчитан один Relay:
+// два ретранслятора передадут одни и те же события дважды и могут перемешать их порядок.
+type Relay struct {
+	store     storage.Storage
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+	retention time.Duration
+}
+
+type Option func(r *Relay)
+
+// WithInterval задаёт период проходов Run.
+func WithInterval(interval time.Duration) Option {
+	return func(r *Relay) {
+		r.interval = interval
+	}
+}
+
+// WithBatchSize задаёт, сколько событий читается из outbox за раз.
+func WithBatchSize(n int) Option {
+	return func(r *Relay) {
+		r.batchSize = n
+	}
+}
+
+// WithRetention задаёт срок хранения доставленных событий; 0 отключает их очистку.
+func WithRetention(retention time.Duration) Option {
+	return func(r *Relay) {
+		r.retention = retention
+	}
+}
+
+func NewRelay(store storage.Storage, publisher Publisher, opts ...Option) *Relay {
+	r := &Relay{
+		store:     store,
+		publisher: publisher,
+		interval:  DefaultInterval,
+		batchSize: DefaultBatchSize,
+		retention: DefaultRetention,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run раз в интервал передаёт накопившиеся события и удаляет доставленные по сроку хранения, пока не отменён ctx.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("relay events failed: %v", err)
+		}
+		if r.retention > 0 {
+			if _, err := r.store.PurgeEvents(ctx, time.Now().Add(-r.retention)); err != nil && ctx.Err() == nil {
+				log.Printf("purge events failed: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending передаёт недоставленные события по порядку, пока outbox не опустеет, и возвращает число переданных.
+// Отказ Publisher останавливает проход: следующие события ждут, чтобы не обогнать отказавшее.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	relayed := 0
+	for {
+		pending, err := r.store.PendingEvents(ctx, r.batchSize)
+		if err != nil {
+			return relayed, err
+		}
+
+		delivered := make([]string, 0, len(pending))
+		var failed error
+		for _, event := range pending {
+			if err := r.publisher.Publish(ctx, event); err != nil {
+				failed = fmt.Errorf("failed to publish event %s: %w", event.ID, err)
+				break
+			}
+			delivered = append(delivered, event.ID)
+		}
+		if err := r.store.MarkDelivered(ctx, delivered, time.Now()); err != nil {
+			return relayed, err
+		}
+		relayed += len(delivered)
+		if failed != nil || len(pending) < r.batchSize {
+			return relayed, failed
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package events
+
+import (
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder запоминает принятые события; fail решает, отказать ли в событии.
+type recorder struct {
+	mu     sync.Mutex
+	events []models.Event
+	fail   func(event models.Event) bool
+}
+
+func (r *recorder) Publish(_ context.Context, event models.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fail != nil && r.fail(event) {
+		return errors.New("sink is down")
+	}
+	r.events = append(r.events, event)
+
+	return nil
+}
+
+func (r *recorder) sequences() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sequences := make([]int64, 0, len(r.events))
+	for _, event := range r.events {
+		sequences = append(sequences, event.Sequence)
+	}
+
+	return sequences
+}
+
+// changeAccounts делает пять изменений двух аккаунтов, по событию на каждое, и возвращает типы событий по порядку.
+func changeAccounts(t *testing.T, store storage.Storage) []models.EventType {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := store.Create(ctx, models.Account{Name: "alice", Amount: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Create(ctx, models.Account{Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Transfer(ctx, "alice", "bob", 30); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ChangeName(ctx, "bob", "carol"); err != nil {
+		t.Fatal(err)
+	}
+
+	return []models.EventType{
+		models.EventAccountCreated, models.EventAccountCreated,
+		models.EventBalanceChanged, models.EventBalanceChanged,
+		models.EventAccountRenamed,
+	}
+}
+
+func checkSequences(t *testing.T, got []int64, want ...int64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("sequences = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("sequences = %v, want %v", got, want)
+		}
+	}
+}
+
+func pending(t *testing.T, store storage.Storage) int {
+	t.Helper()
+	events, err := store.PendingEvents(context.Background(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return len(events)
+}
+
+func TestRelayInOrder(t *testing.T) {
+	store := storage.NewMemory()
+	types := changeAccounts(t, store)
+	sink := &recorder{}
+
+	relayed, err := NewRelay(store, sink, WithBatchSize(2)).RelayPending(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relayed != len(types) {
+		t.Fatalf("relayed %d events, want %d", relayed, len(types))
+	}
+	checkSequences(t, sink.sequences(), 1, 2, 3, 4, 5)
+	for i, event := range sink.events {
+		if event.Type != types[i] {
+			t.Fatalf("event %d is %s, want %s", i, event.Type, types[i])
+		}
+	}
+	if last := sink.events[len(sink.events)-1]; last.Account.Name != "carol" || last.Account.Amount != 30 {
+		t.Fatalf("last event account = %+v, want carol with 30", last.Account)
+	}
+	if n := pending(t, store); n != 0 {
+		t.Fatalf("%d events still pending after relay", n)
+	}
+}
+
+// TestRelayRetriesAfterFailure: отказ одного подписчика шины останавливает проход на этом событии,
+// следующий проход передаёт его снова, и подписчик, принявший его в первый раз, получает его повторно.
+func TestRelayRetriesAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	changeAccounts(t, store)
+
+	var once sync.Once
+	healthy, flaky := &recorder{}, &recorder{fail: func(event models.Event) bool {
+		failed := false
+		if event.Sequence == 3 {
+			once.Do(func() { failed = true })
+		}
+		return failed
+	}}
+	bus := NewBus()
+	bus.Subscribe(healthy)
+	bus.Subscribe(flaky)
+	relay := NewRelay(store, bus)
+
+	relayed, err := relay.RelayPending(ctx)
+	if err == nil || relayed != 2 {
+		t.Fatalf("first pass = %d, %v; want 2 relayed and an error", relayed, err)
+	}
+	if n := pending(t, store); n != 3 {
+		t.Fatalf("%d events pending after the failure, want 3", n)
+	}
+
+	if relayed, err = relay.RelayPending(ctx); err != nil || relayed != 3 {
+		t.Fatalf("second pass = %d, %v; want 3 relayed", relayed, err)
+	}
+	checkSequences(t, healthy.sequences(), 1, 2, 3, 3, 4, 5)
+	checkSequences(t, flaky.sequences(), 1, 2, 3, 4, 5)
+}
+
+func TestMarkDeliveredAndPurge(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	changeAccounts(t, store)
+
+	events, err := store.PendingEvents(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliveredAt := time.Now()
+	if err := store.MarkDelivered(ctx, []string{events[0].ID, events[1].ID, "unknown"}, deliveredAt); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := store.PendingEvents(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 3 || rest[0].Sequence != 3 {
+		t.Fatalf("pending after MarkDelivered = %+v, want 3 events from sequence 3", rest)
+	}
+
+	// Доставленные события удаляются только по сроку; недоставленные не удаляются вовсе.
+	if n, err := store.PurgeEvents(ctx, deliveredAt.Add(-time.Minute)); err != nil || n != 0 {
+		t.Fatalf("purge before delivery = %d, %v; want 0", n, err)
+	}
+	if n, err := store.PurgeEvents(ctx, deliveredAt.Add(time.Minute)); err != nil || n != 2 {
+		t.Fatalf("purge after delivery = %d, %v; want 2", n, err)
+	}
+	if n := pending(t, store); n != 3 {
+		t.Fatalf("%d events pending after purge, want 3", n)
+	}
+}
+
+func TestRunPurgesDelivered(t *testing.T) {
+	store := storage.NewMemory()
+	changeAccounts(t, store)
+	sink := &recorder{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewRelay(store, sink, WithInterval(time.Millisecond), WithRetention(time.Nanosecond)).Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sink.sequences()) < 5 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+
+	checkSequences(t, sink.sequences(), 1, 2, 3, 4, 5)
+	if n, err := store.PurgeEvents(context.Background(), time.Now().Add(time.Hour)); err != nil || n != 0 {
+		t.Fatalf("purge after Run = %d, %v; want Run to have purged everything", n, err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	store := storage.NewMemory()
+	changeAccounts(t, store)
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	file, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRelay(store, file).RelayPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var sequences []int64
+	for lines := bufio.NewScanner(f); lines.Scan(); {
+		var event struct {
+			Sequence int64 `json:"sequence"`
+		}
+		if err := json.Unmarshal(lines.Bytes(), &event); err != nil {
+			t.Fatalf("line %q: %v", lines.Text(), err)
+		}
+		sequences = append(sequences, event.Sequence)
+	}
+	checkSequences(t, sequences, 1, 2, 3, 4, 5)
+}
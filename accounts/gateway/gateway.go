@@ -0,0 +1,304 @@
+package gateway
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/rpc"
+	"awesomeProject/proto"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Register добавляет REST/JSON отображение RPC сервиса Account в стиле grpc-gateway:
+// тело запроса и ответ — protojson, параметры пути заполняют одноимённые поля сообщения.
+func Register(e *echo.Echo, server proto.AccountServer) {
+	g := e.Group("/gateway")
+
+	g.GET("/account", func(c echo.Context) error {
+		return serve(c, &proto.ListAccountsRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.List(ctx, req.(*proto.ListAccountsRequest))
+		})
+	})
+	g.GET("/account/:name", func(c echo.Context) error {
+		return serve(c, &proto.GetAccountRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Get(ctx, req.(*proto.GetAccountRequest))
+		})
+	})
+	g.POST("/account", func(c echo.Context) error {
+		return serve(c, &proto.CreateAccountRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Create(ctx, req.(*proto.CreateAccountRequest))
+		})
+	})
+	g.PUT("/account/:name/amount", func(c echo.Context) error {
+		return serve(c, &proto.PatchAccountRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ChangeAmount(ctx, req.(*proto.PatchAccountRequest))
+		})
+	})
+	g.PUT("/account/:name/name", func(c echo.Context) error {
+		return serve(c, &proto.ChangeAccountRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ChangeName(ctx, req.(*proto.ChangeAccountRequest))
+		})
+	})
+	g.PATCH("/account/:name", func(c echo.Context) error {
+		return serve(c, &proto.UpdateAccountRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Update(ctx, req.(*proto.UpdateAccountRequest))
+		})
+	})
+	g.DELETE("/account/:name", func(c echo.Context) error {
+		return serve(c, &proto.DeleteAccountRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Delete(ctx, req.(*proto.DeleteAccountRequest))
+		})
+	})
+	g.POST("/account/:name/restore", func(c echo.Context) error {
+		return serve(c, &proto.RestoreAccountRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Restore(ctx, req.(*proto.RestoreAccountRequest))
+		})
+	})
+	g.POST("/account/:name/activate", func(c echo.Context) error {
+		return serve(c, &proto.ChangeStatusRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Activate(ctx, req.(*proto.ChangeStatusRequest))
+		})
+	})
+	g.POST("/account/:name/freeze", func(c echo.Context) error {
+		return serve(c, &proto.ChangeStatusRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Freeze(ctx, req.(*proto.ChangeStatusRequest))
+		})
+	})
+	g.POST("/account/:name/unfreeze", func(c echo.Context) error {
+		return serve(c, &proto.ChangeStatusRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Unfreeze(ctx, req.(*proto.ChangeStatusRequest))
+		})
+	})
+	g.POST("/account/:name/close", func(c echo.Context) error {
+		return serve(c, &proto.ChangeStatusRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Close(ctx, req.(*proto.ChangeStatusRequest))
+		})
+	})
+	g.GET("/account/:name/transitions", func(c echo.Context) error {
+		return serve(c, &proto.StatusHistoryRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.StatusHistory(ctx, req.(*proto.StatusHistoryRequest))
+		})
+	})
+	g.GET("/account/:name/renames", func(c echo.Context) error {
+		return serve(c, &proto.RenameHistoryRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.RenameHistory(ctx, req.(*proto.RenameHistoryRequest))
+		})
+	})
+	g.POST("/account/:name/holds", func(c echo.Context) error {
+		return serve(c, &proto.AuthorizeRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Authorize(ctx, req.(*proto.AuthorizeRequest))
+		})
+	})
+	g.GET("/account/:name/holds", func(c echo.Context) error {
+		return serve(c, &proto.ListHoldsRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ListHolds(ctx, req.(*proto.ListHoldsRequest))
+		})
+	})
+	g.GET("/hold/:hold_id", func(c echo.Context) error {
+		return serve(c, &proto.GetHoldRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.GetHold(ctx, req.(*proto.GetHoldRequest))
+		})
+	})
+	g.POST("/hold/:hold_id/capture", func(c echo.Context) error {
+		return serve(c, &proto.CaptureRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Capture(ctx, req.(*proto.CaptureRequest))
+		})
+	})
+	g.POST("/hold/:hold_id/release", func(c echo.Context) error {
+		return serve(c, &proto.ReleaseRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Release(ctx, req.(*proto.ReleaseRequest))
+		})
+	})
+	g.POST("/schedule", func(c echo.Context) error {
+		return serve(c, &proto.CreateScheduleRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.CreateSchedule(ctx, req.(*proto.CreateScheduleRequest))
+		})
+	})
+	g.GET("/schedule", func(c echo.Context) error {
+		return serve(c, &proto.ListSchedulesRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ListSchedules(ctx, req.(*proto.ListSchedulesRequest))
+		})
+	})
+	g.GET("/schedule/:schedule_id", func(c echo.Context) error {
+		return serve(c, &proto.ScheduleRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.GetSchedule(ctx, req.(*proto.ScheduleRequest))
+		})
+	})
+	g.POST("/schedule/:schedule_id/pause", func(c echo.Context) error {
+		return serve(c, &proto.ScheduleRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.PauseSchedule(ctx, req.(*proto.ScheduleRequest))
+		})
+	})
+	g.POST("/schedule/:schedule_id/resume", func(c echo.Context) error {
+		return serve(c, &proto.ScheduleRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ResumeSchedule(ctx, req.(*proto.ScheduleRequest))
+		})
+	})
+	g.POST("/schedule/:schedule_id/cancel", func(c echo.Context) error {
+		return serve(c, &proto.ScheduleRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.CancelSchedule(ctx, req.(*proto.ScheduleRequest))
+		})
+	})
+	g.GET("/schedule/:schedule_id/runs", func(c echo.Context) error {
+		return serve(c, &proto.ScheduleRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ScheduleRuns(ctx, req.(*proto.ScheduleRequest))
+		})
+	})
+	g.GET("/account/:name/ledger", func(c echo.Context) error {
+		return serve(c, &proto.LedgerRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.Ledger(ctx, req.(*proto.LedgerRequest))
+		})
+	})
+	g.POST("/accrual", func(c echo.Context) error {
+		return serve(c, &proto.AccrueInterestRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.AccrueInterest(ctx, req.(*proto.AccrueInterestRequest))
+		})
+	})
+	g.GET("/accrual", func(c echo.Context) error {
+		return serve(c, &proto.Empty{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ListAccruals(ctx, req.(*proto.Empty))
+		})
+	})
+	g.GET("/accrual/:accrual_id", func(c echo.Context) error {
+		return serve(c, &proto.GetAccrualRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.GetAccrual(ctx, req.(*proto.GetAccrualRequest))
+		})
+	})
+	g.POST("/webhook", func(c echo.Context) error {
+		return serve(c, &proto.CreateWebhookRequest{}, true, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.CreateWebhook(ctx, req.(*proto.CreateWebhookRequest))
+		})
+	})
+	g.GET("/webhook", func(c echo.Context) error {
+		return serve(c, &proto.Empty{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ListWebhooks(ctx, req.(*proto.Empty))
+		})
+	})
+	g.GET("/webhook/:webhook_id", func(c echo.Context) error {
+		return serve(c, &proto.WebhookRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.GetWebhook(ctx, req.(*proto.WebhookRequest))
+		})
+	})
+	g.DELETE("/webhook/:webhook_id", func(c echo.Context) error {
+		return serve(c, &proto.WebhookRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.DeleteWebhook(ctx, req.(*proto.WebhookRequest))
+		})
+	})
+	g.GET("/webhook/:webhook_id/delivery", func(c echo.Context) error {
+		return serve(c, &proto.ListDeliveriesRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ListDeliveries(ctx, req.(*proto.ListDeliveriesRequest))
+		})
+	})
+	g.POST("/webhook/:webhook_id/delivery/:delivery_id/replay", func(c echo.Context) error {
+		return serve(c, &proto.ReplayDeliveryRequest{}, false, func(ctx context.Context, req protobuf.Message) (protobuf.Message, error) {
+			return server.ReplayDelivery(ctx, req.(*proto.ReplayDeliveryRequest))
+		})
+	})
+}
+
+type call func(ctx context.Context, req protobuf.Message) (protobuf.Message, error)
+
+func serve(c echo.Context, req protobuf.Message, body bool, invoke call) error {
+	if body {
+		data, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return writeError(c, errs.New(errs.InvalidArgument, fmt.Sprintf("read body failed: %v", err), nil))
+		}
+		if len(data) > 0 {
+			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, req); err != nil {
+				return writeError(c, errs.New(errs.InvalidArgument, fmt.Sprintf("invalid request: %v", err), nil))
+			}
+		}
+	}
+
+	fields := req.ProtoReflect().Descriptor().Fields()
+	for _, name := range c.ParamNames() {
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil || field.Kind() != protoreflect.StringKind {
+			continue
+		}
+		req.ProtoReflect().Set(field, protoreflect.ValueOfString(c.Param(name)))
+	}
+	// Параметры запроса, как в grpc-gateway, заполняют одноимённые скалярные поля.
+	for name, values := range c.QueryParams() {
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil || len(values) == 0 {
+			continue
+		}
+		value, err := queryValue(field, values[0])
+		if err != nil {
+			return writeError(c, errs.InvalidField(name, err.Error()))
+		}
+		req.ProtoReflect().Set(field, value)
+	}
+
+	ctx := c.Request().Context()
+	// Автор запроса передаётся в RPC так же, как его передал бы gRPC клиент.
+	if actor := c.Request().Header.Get(dto.ActorHeader); actor != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(rpc.ActorMetadata, actor))
+	}
+
+	resp, err := invoke(ctx, req)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		return writeError(c, fmt.Errorf("marshal response failed: %w", err))
+	}
+
+	return c.JSONBlob(http.StatusOK, data)
+}
+
+func queryValue(field protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	if field.IsList() {
+		return protoreflect.Value{}, fmt.Errorf("query parameter is not supported for this field")
+	}
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid boolean %q", value)
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.MessageKind:
+		// Маска полей передаётся путями через запятую: update_mask=amount,labels.tier.
+		if field.Message().FullName() == "google.protobuf.FieldMask" {
+			return protoreflect.ValueOfMessage((&fieldmaskpb.FieldMask{Paths: strings.Split(value, ",")}).ProtoReflect()), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("query parameter is not supported for this field")
+	default:
+		return protoreflect.Value{}, fmt.Errorf("query parameter is not supported for this field")
+	}
+}
+
+// writeError отвечает тем же Problem телом, что и accounts.Handler.
+// Статусы gRPC (например, Unimplemented) сначала переводятся в коды каталога.
+func writeError(c echo.Context, err error) error {
+	var domainErr *errs.Error
+	if !errors.As(err, &domainErr) {
+		err = errs.FromStatus(err)
+	}
+
+	status, problem := errs.ToProblem(err)
+	if problem.Code == errs.Internal {
+		c.Logger().Error(err)
+	}
+
+	return c.JSON(status, problem)
+}
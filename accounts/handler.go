@@ -2,162 +2,403 @@ package accounts
 
 import (
 	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/interest"
 	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"awesomeProject/accounts/validation"
+	"encoding/json"
 	"github.com/labstack/echo/v4"
 	"net/http"
-	"sync"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func New() *Handler {
-	return &Handler{
-		accounts: make(map[string]*models.Account),
-		guard:    &sync.RWMutex{},
+func New(storage storage.Storage, opts ...Option) *Handler {
+	h := &Handler{
+		storage: storage,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 type Handler struct {
-	accounts map[string]*models.Account
-	guard    *sync.RWMutex
+	storage storage.Storage
+	// renameAliases — сколько GET находит аккаунт по старому имени; 0 — не находит.
+	renameAliases time.Duration
+	// interest начисляет проценты; nil — начисление не настроено.
+	interest *interest.Engine
 }
 
-// Создать аккаунт
-func (h *Handler) CreateAccount(c echo.Context) error {
-	var request dto.CreateAccountRequest // {"name": "alice", "amount": 50}
-	if err := c.Bind(&request); err != nil {
-		c.Logger().Error(err)
+type Option func(h *Handler)
+
+// WithRenameAliases разрешает GET /v1/accounts/{name} находить аккаунт по имени, которое он носил
+// до переименования не раньше ttl назад; ответ сообщает об этом в redirected_from и Content-Location.
+func WithRenameAliases(ttl time.Duration) Option {
+	return func(h *Handler) {
+		h.renameAliases = ttl
+	}
+}
+
+// WithInterest включает POST /v1/interest/accruals.
+func WithInterest(engine *interest.Engine) Option {
+	return func(h *Handler) {
+		h.interest = engine
+	}
+}
 
-		return c.String(http.StatusBadRequest, "invalid request")
+// Список аккаунтов
+func (h *Handler) ListAccounts(c echo.Context) error {
+	opts, err := readOptions(c)
+	if err != nil {
+		return writeError(c, err)
 	}
 
-	if len(request.Name) == 0 {
-		return c.String(http.StatusBadRequest, "empty name")
+	accounts, err := h.storage.List(c.Request().Context(), opts...)
+	if err != nil {
+		return writeError(c, err)
 	}
 
-	h.guard.Lock()
+	response := dto.ListAccountsResponse{
+		Accounts: make([]dto.GetAccountResponse, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, accountResponse(account))
+	}
 
-	if _, ok := h.accounts[request.Name]; ok {
-		h.guard.Unlock()
+	return c.JSON(http.StatusOK, response)
+}
 
-		return c.String(http.StatusForbidden, "account already exists")
+// Выгрузка всех аккаунтов на один момент времени, по одному JSON-объекту на строку
+func (h *Handler) ExportAccounts(c echo.Context) error {
+	opts, err := readOptions(c)
+	if err != nil {
+		return writeError(c, err)
 	}
 
-	h.accounts[request.Name] = &models.Account{
-		Name:   request.Name,
-		Amount: request.Amount,
+	response := c.Response()
+	encoder := json.NewEncoder(response)
+	start := func() {
+		if !response.Committed {
+			response.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			response.WriteHeader(http.StatusOK)
+		}
 	}
 
-	h.guard.Unlock()
+	err = h.storage.Export(c.Request().Context(), func(account models.Account) error {
+		start()
+		return encoder.Encode(accountResponse(account))
+	}, opts...)
+	switch {
+	case err != nil && response.Committed:
+		// Статус уже отправлен: обрываем ответ, чтобы клиент не принял неполную выгрузку за полную.
+		c.Logger().Error(err)
+		panic(http.ErrAbortHandler)
+	case err != nil:
+		return writeError(c, err)
+	}
+	start()
 
-	return c.NoContent(http.StatusCreated)
+	return nil
 }
 
-// Находит аккаунт
-func (h *Handler) GetAccount(c echo.Context) error {
+// Создать аккаунт
+func (h *Handler) CreateAccount(c echo.Context) error {
+	var request dto.CreateAccountRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	v := validation.New()
+	account := models.Account{
+		Name:        v.Name("name", request.Name),
+		Amount:      request.Amount,
+		Status:      v.InitialStatus("status", request.Status),
+		Description: v.Description("description", request.Description),
+		Labels:      v.Labels("labels", request.Labels),
+	}
+	v.Amount("amount", request.Amount)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+	account, err := h.storage.Create(c.Request().Context(), account)
+	if err != nil {
+		return writeError(c, err)
+	}
 
-	name := c.QueryParams().Get("name")
+	c.Response().Header().Set(echo.HeaderLocation, accountLocation(account.ID))
 
-	h.guard.RLock()
+	return c.JSON(http.StatusCreated, accountResponse(account))
+}
 
-	account, ok := h.accounts[name]
+// Находит аккаунт
+func (h *Handler) GetAccount(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
 
-	h.guard.RUnlock()
+	opts, err := readOptions(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+	if h.renameAliases > 0 {
+		opts = append(opts, storage.FollowRenames(h.renameAliases))
+	}
 
-	if !ok {
-		return c.String(http.StatusNotFound, "account not found")
+	account, err := h.storage.Get(c.Request().Context(), name, opts...)
+	if err != nil {
+		return writeError(c, err)
 	}
 
-	response := dto.GetAccountResponse{
-		Name:   account.Name,
-		Amount: account.Amount,
+	response := accountResponse(account)
+	if !account.RefersTo(name) {
+		response.RedirectedFrom = name
+		c.Response().Header().Set("Content-Location", accountLocation(account.ID))
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
-// Удаляет аккаунт
-func (h *Handler) DeleteAccount(c echo.Context) error {
-	var request dto.DeleteAccountRequest
+// Меняет поля аккаунта из update_mask или из тела запроса
+func (h *Handler) UpdateAccount(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	var request dto.UpdateAccountRequest
 	if err := c.Bind(&request); err != nil {
 		c.Logger().Error(err)
-		return c.String(http.StatusBadRequest, "invalid request")
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	fields := validation.UpdateFields{
+		Name:        request.Name,
+		Amount:      request.Amount,
+		Description: request.Description,
+		Labels:      request.Labels,
+	}
+	mask := fields.PresentPaths()
+	if value := c.QueryParam("update_mask"); value != "" {
+		mask = strings.Split(value, ",")
+	}
+
+	v := validation.New()
+	update := v.Update("update_mask", mask, fields)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	account, err := storage.Update(c.Request().Context(), h.storage, name, update)
+	if err != nil {
+		return writeError(c, err)
 	}
-	if len(request.Name) == 0 {
-		return c.String(http.StatusBadRequest, "empty name")
+
+	return c.JSON(http.StatusOK, accountResponse(account))
+}
+
+// Удаляет аккаунт
+func (h *Handler) DeleteAccount(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
 	}
 
-	h.guard.RLock()
+	if err := h.storage.Delete(c.Request().Context(), name, actor(c)); err != nil {
+		return writeError(c, err)
+	}
 
-	_, ok := h.accounts[request.Name]
+	return c.NoContent(http.StatusNoContent)
+}
 
-	h.guard.RUnlock()
+// Восстанавливает удалённый аккаунт
+func (h *Handler) RestoreAccount(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
 
-	if !ok {
-		return c.String(http.StatusNotFound, "account not found")
+	account, err := h.storage.Restore(c.Request().Context(), name)
+	if err != nil {
+		return writeError(c, err)
 	}
 
-	h.guard.Lock()
-	delete(h.accounts, request.Name)
-	h.guard.Unlock()
+	return c.JSON(http.StatusOK, accountResponse(account))
+}
+
+// Активирует аккаунт, созданный в статусе pending
+func (h *Handler) ActivateAccount(c echo.Context) error {
+	return h.changeStatus(c, models.Activate)
+}
+
+// Замораживает аккаунт: остаются доступны только чтение и зачисления
+func (h *Handler) FreezeAccount(c echo.Context) error {
+	return h.changeStatus(c, models.Freeze)
+}
+
+// Размораживает аккаунт
+func (h *Handler) UnfreezeAccount(c echo.Context) error {
+	return h.changeStatus(c, models.Unfreeze)
+}
 
-	return c.NoContent(http.StatusOK)
+// Закрывает аккаунт навсегда: остаётся доступно только чтение
+func (h *Handler) CloseAccount(c echo.Context) error {
+	return h.changeStatus(c, models.Close)
 }
 
-// Меняет баланс
-func (h *Handler) PatchAccount(c echo.Context) error {
-	var request dto.PatchAccountRequest
+func (h *Handler) changeStatus(c echo.Context, change models.Change) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	var request dto.ChangeStatusRequest
 	if err := c.Bind(&request); err != nil {
 		c.Logger().Error(err)
-		return c.String(http.StatusBadRequest, "invalid request")
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
 	}
-	if len(request.Name) == 0 {
-		return c.String(http.StatusBadRequest, "empty name")
+
+	v := validation.New()
+	reason := v.Reason("reason", request.Reason)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
 	}
 
-	h.guard.RLock()
+	account, err := h.storage.SetStatus(c.Request().Context(), name, change, reason, actor(c))
+	if err != nil {
+		return writeError(c, err)
+	}
 
-	account, ok := h.accounts[request.Name]
+	return c.JSON(http.StatusOK, accountResponse(account))
+}
 
-	h.guard.RUnlock()
+// История статусов аккаунта
+func (h *Handler) StatusHistory(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
 
-	if !ok {
-		return c.String(http.StatusNotFound, "account not found")
+	history, err := h.storage.StatusHistory(c.Request().Context(), name)
+	if err != nil {
+		return writeError(c, err)
 	}
 
-	h.guard.Lock()
-	account.Amount = request.Amount
-	h.guard.Unlock()
+	response := dto.StatusHistoryResponse{
+		Transitions: make([]dto.TransitionResponse, 0, len(history)),
+	}
+	for _, t := range history {
+		response.Transitions = append(response.Transitions, dto.TransitionResponse{
+			From:   string(t.From),
+			To:     string(t.To),
+			Reason: t.Reason,
+			Actor:  t.Actor,
+			At:     t.At,
+		})
+	}
 
-	return c.NoContent(http.StatusOK)
+	return c.JSON(http.StatusOK, response)
 }
 
-// Меняет имя
-func (h *Handler) ChangeAccount(c echo.Context) error {
-	var request dto.ChangeAccountRequest
-	if err := c.Bind(&request); err != nil {
-		c.Logger().Error(err)
-		return c.String(http.StatusBadRequest, "invalid request")
+// История переименований аккаунта
+func (h *Handler) RenameHistory(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	history, err := h.storage.RenameHistory(c.Request().Context(), name)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.RenameHistoryResponse{
+		Renames: make([]dto.RenameResponse, 0, len(history)),
 	}
-	if len(request.Name) == 0 {
-		return c.String(http.StatusBadRequest, "empty name")
+	for _, r := range history {
+		response.Renames = append(response.Renames, dto.RenameResponse{
+			From: r.From,
+			To:   r.To,
+			At:   r.At,
+		})
 	}
-	if len(request.NewName) == 0 {
-		return c.String(http.StatusBadRequest, "empty new name")
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// readOptions читает параметры include_deleted и label_selector.
+func readOptions(c echo.Context) ([]storage.ReadOption, error) {
+	var opts []storage.ReadOption
+	if value := c.QueryParam("include_deleted"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errs.InvalidField("include_deleted", "must be true or false")
+		}
+		if include {
+			opts = append(opts, storage.IncludeDeleted())
+		}
 	}
-	h.guard.RLock()
 
-	account, ok := h.accounts[request.Name]
+	if value := c.QueryParam("label_selector"); value != "" {
+		v := validation.New()
+		selector := v.Selector("label_selector", value)
+		if err := v.Err(); err != nil {
+			return nil, err
+		}
+		opts = append(opts, storage.MatchLabels(selector))
+	}
 
-	h.guard.RUnlock()
+	return opts, nil
+}
 
-	if !ok {
-		return c.String(http.StatusNotFound, "account not found")
+func actor(c echo.Context) string {
+	if actor := c.Request().Header.Get(dto.ActorHeader); actor != "" {
+		return actor
 	}
 
-	h.guard.Lock()
-	delete(h.accounts, account.Name)
-	account.Name = request.NewName
-	h.accounts[request.NewName] = account
-	h.guard.Unlock()
+	return models.UnknownActor
+}
+
+// nameParam читает ссылку на аккаунт из пути: его ID или имя.
+func nameParam(c echo.Context) (string, error) {
+	name, err := url.PathUnescape(c.Param("name"))
+	if err != nil || len(name) == 0 {
+		return "", errs.InvalidField("name", "invalid account ID or name in path")
+	}
+
+	return validation.NormalizeName(name), nil
+}
+
+// accountLocation строит URL аккаунта по ID, поэтому он не меняется при переименовании.
+func accountLocation(id string) string {
+	return "/v1/accounts/" + url.PathEscape(id)
+}
+
+func accountResponse(account models.Account) dto.GetAccountResponse {
+	response := dto.GetAccountResponse{
+		ID:          account.ID,
+		Name:        account.Name,
+		Amount:      account.Amount,
+		Held:        account.Held,
+		Available:   account.Available(),
+		Status:      string(account.Status),
+		Description: account.Description,
+		Labels:      account.Labels,
+		CreatedAt:   account.CreatedAt,
+		UpdatedAt:   account.UpdatedAt,
+	}
+	if account.Deleted() {
+		deletedAt := account.DeletedAt
+		response.DeletedAt = &deletedAt
+		response.DeletedBy = account.DeletedBy
+	}
 
-	return c.NoContent(http.StatusOK)
+	return response
 }
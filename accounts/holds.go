@@ -0,0 +1,151 @@
+package accounts
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/validation"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/url"
+)
+
+// Резервирует часть доступного баланса аккаунта
+func (h *Handler) Authorize(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	var request dto.AuthorizeRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	v := validation.New()
+	validation.Check(v, "amount", request.Amount, validation.HoldAmount...)
+	ttl := v.HoldTTL("ttl_seconds", request.TTLSeconds)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	hold, err := h.storage.Authorize(c.Request().Context(), name, request.Amount, ttl)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, holdLocation(hold.ID))
+
+	return c.JSON(http.StatusCreated, holdResponse(hold))
+}
+
+// Холды аккаунта, включая закрытые
+func (h *Handler) ListHolds(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	holds, err := h.storage.Holds(c.Request().Context(), name)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.ListHoldsResponse{Holds: make([]dto.HoldResponse, 0, len(holds))}
+	for _, hold := range holds {
+		response.Holds = append(response.Holds, holdResponse(hold))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Находит холд
+func (h *Handler) GetHold(c echo.Context) error {
+	id, err := holdParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	hold, err := h.storage.Hold(c.Request().Context(), id)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, holdResponse(hold))
+}
+
+// Списывает холд целиком или частично
+func (h *Handler) CaptureHold(c echo.Context) error {
+	id, err := holdParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	var request dto.CaptureRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	v := validation.New()
+	if request.Amount != 0 {
+		validation.Check(v, "amount", request.Amount, validation.HoldAmount...)
+	}
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	hold, account, err := h.storage.Capture(c.Request().Context(), id, request.Amount)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.CaptureResponse{Hold: holdResponse(hold), Account: accountResponse(account)})
+}
+
+// Снимает холд, не меняя баланс
+func (h *Handler) ReleaseHold(c echo.Context) error {
+	id, err := holdParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	hold, err := h.storage.Release(c.Request().Context(), id)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, holdResponse(hold))
+}
+
+func holdParam(c echo.Context) (string, error) {
+	id, err := url.PathUnescape(c.Param("hold_id"))
+	if err != nil || len(id) == 0 {
+		return "", errs.InvalidField("hold_id", "invalid hold ID in path")
+	}
+
+	return id, nil
+}
+
+func holdLocation(id string) string {
+	return "/v1/holds/" + url.PathEscape(id)
+}
+
+func holdResponse(hold models.Hold) dto.HoldResponse {
+	response := dto.HoldResponse{
+		ID:        hold.ID,
+		AccountID: hold.AccountID,
+		Amount:    hold.Amount,
+		Status:    string(hold.Status),
+		Captured:  hold.Captured,
+		CreatedAt: hold.CreatedAt,
+		ExpiresAt: hold.ExpiresAt,
+	}
+	if !hold.ClosedAt.IsZero() {
+		closedAt := hold.ClosedAt
+		response.ClosedAt = &closedAt
+	}
+
+	return response
+}
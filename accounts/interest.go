@@ -0,0 +1,144 @@
+package accounts
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/validation"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Начисляет проценты за закончившийся период или, с dry_run, показывает расчётные проводки
+func (h *Handler) AccrueInterest(c echo.Context) error {
+	if h.interest == nil {
+		return writeError(c, errs.InterestDisabled())
+	}
+
+	var request dto.AccrueInterestRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	v := validation.New()
+	period := v.Period("period", request.Period)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if request.DryRun {
+		accrual, postings, err := h.interest.Project(ctx, period)
+		if err != nil {
+			return writeError(c, err)
+		}
+		return c.JSON(http.StatusOK, projectionResponse(accrual, postings))
+	}
+
+	accrual, err := h.interest.Accrue(ctx, period, time.Now())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, accrualResponse(accrual))
+}
+
+// Пакеты начисления процентов от старых периодов к новым
+func (h *Handler) ListAccruals(c echo.Context) error {
+	accruals, err := h.storage.Accruals(c.Request().Context())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.ListAccrualsResponse{Accruals: make([]dto.AccrualResponse, 0, len(accruals))}
+	for _, accrual := range accruals {
+		response.Accruals = append(response.Accruals, accrualResponse(accrual))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetAccrual(c echo.Context) error {
+	id, err := url.PathUnescape(c.Param("accrual_id"))
+	if err != nil || len(id) == 0 {
+		return writeError(c, errs.InvalidField("accrual_id", "invalid accrual ID in path"))
+	}
+
+	accrual, err := h.storage.Accrual(c.Request().Context(), id)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, accrualResponse(accrual))
+}
+
+// Проводки по счёту от старых к новым
+func (h *Handler) Ledger(c echo.Context) error {
+	name, err := nameParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	entries, err := h.storage.Ledger(c.Request().Context(), name)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.LedgerResponse{Entries: make([]dto.LedgerEntryResponse, 0, len(entries))}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, dto.LedgerEntryResponse{
+			ID:        entry.ID,
+			Kind:      string(entry.Kind),
+			Amount:    entry.Amount,
+			Balance:   entry.Balance,
+			Reference: entry.Reference,
+			PostedAt:  entry.PostedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func accrualResponse(accrual models.Accrual) dto.AccrualResponse {
+	response := dto.AccrualResponse{
+		ID:          accrual.ID,
+		Compounding: string(accrual.Period.Compounding),
+		PeriodStart: accrual.Period.Start,
+		PeriodEnd:   accrual.Period.End(),
+		Status:      string(accrual.Status),
+		Cursor:      accrual.Cursor,
+		Postings:    accrual.Postings,
+		Total:       accrual.Total,
+	}
+	if !accrual.StartedAt.IsZero() {
+		startedAt := accrual.StartedAt
+		response.StartedAt = &startedAt
+	}
+	if !accrual.FinishedAt.IsZero() {
+		finishedAt := accrual.FinishedAt
+		response.FinishedAt = &finishedAt
+	}
+
+	return response
+}
+
+func projectionResponse(accrual models.Accrual, postings []models.Posting) dto.AccrualResponse {
+	response := accrualResponse(accrual)
+	response.DryRun = true
+	response.Projected = make([]dto.PostingResponse, 0, len(postings))
+	for _, p := range postings {
+		response.Projected = append(response.Projected, dto.PostingResponse{
+			AccountID: p.AccountID,
+			Name:      p.Name,
+			Type:      p.Type,
+			Balance:   p.Balance,
+			RateBPS:   p.Rate,
+			Amount:    p.Amount,
+		})
+	}
+
+	return response
+}
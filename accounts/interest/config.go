@@ -0,0 +1,170 @@
+package interest
+
+import (
+	"awesomeProject/accounts/models"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// DefaultTypeLabel — метка аккаунта, значение которой выбирает таблицу ставок.
+const DefaultTypeLabel = "type"
+
+// MaxRate — предел годовой ставки в базисных пунктах, 1000%.
+const MaxRate = 100_000
+
+// daysInYear — база расчёта: год всегда считается за 365 дней.
+const daysInYear = 365
+
+// Rounding — правило округления процентов до целого.
+type Rounding string
+
+const (
+	// RoundHalfEven округляет половину к чётному (банковское округление); правило по умолчанию.
+	RoundHalfEven Rounding = "half_even"
+	RoundHalfUp   Rounding = "half_up"
+	// RoundDown отбрасывает дробную часть.
+	RoundDown Rounding = "down"
+)
+
+// Tier — ступень таблицы: ставка Rate в базисных пунктах годовых действует на весь баланс
+// не меньше MinBalance, пока не начнётся следующая ступень.
+type Tier struct {
+	MinBalance int `json:"min_balance"`
+	Rate       int `json:"rate_bps"`
+}
+
+// Table — таблица ставок одного типа аккаунтов.
+type Table struct {
+	Compounding models.Compounding `json:"compounding"`
+	Rounding    Rounding           `json:"rounding,omitempty"`
+	// Tiers — ступени по возрастанию MinBalance; баланс ниже первой ступени процентов не получает.
+	Tiers []Tier `json:"tiers"`
+}
+
+// Config — таблицы ставок по типам аккаунтов.
+//
+//	{"type_label": "type", "tables": {"savings": {"compounding": "daily", "rounding": "half_even",
+//	  "tiers": [{"min_balance": 0, "rate_bps": 250}, {"min_balance": 100000, "rate_bps": 300}]}}}
+type Config struct {
+	// TypeLabel — метка с типом аккаунта; по умолчанию DefaultTypeLabel.
+	TypeLabel string           `json:"type_label,omitempty"`
+	Tables    map[string]Table `json:"tables"`
+}
+
+// LoadConfig читает таблицы ставок из JSON файла и проверяет их.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read interest config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to decode interest config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid interest config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Validate проверяет таблицы и подставляет значения по умолчанию.
+func (c *Config) Validate() error {
+	if c.TypeLabel == "" {
+		c.TypeLabel = DefaultTypeLabel
+	}
+	if len(c.Tables) == 0 {
+		return fmt.Errorf("no rate tables")
+	}
+
+	for name, table := range c.Tables {
+		switch table.Compounding {
+		case models.CompoundDaily, models.CompoundMonthly:
+		default:
+			return fmt.Errorf("table %q: compounding must be %s or %s, got %q", name, models.CompoundDaily, models.CompoundMonthly, table.Compounding)
+		}
+		switch table.Rounding {
+		case "":
+			table.Rounding = RoundHalfEven
+		case RoundHalfEven, RoundHalfUp, RoundDown:
+		default:
+			return fmt.Errorf("table %q: rounding must be %s, %s or %s, got %q", name, RoundHalfEven, RoundHalfUp, RoundDown, table.Rounding)
+		}
+		if len(table.Tiers) == 0 {
+			return fmt.Errorf("table %q: no tiers", name)
+		}
+		for i, tier := range table.Tiers {
+			if tier.MinBalance < 0 || (i > 0 && tier.MinBalance <= table.Tiers[i-1].MinBalance) {
+				return fmt.Errorf("table %q: tier min_balance must be non-negative and increasing, got %d", name, tier.MinBalance)
+			}
+			if tier.Rate < 0 || tier.Rate > MaxRate {
+				return fmt.Errorf("table %q: tier rate_bps must be within 0-%d, got %d", name, MaxRate, tier.Rate)
+			}
+		}
+		c.Tables[name] = table
+	}
+
+	return nil
+}
+
+// Compoundings — периоды капитализации, которые встречаются в таблицах.
+func (c Config) Compoundings() []models.Compounding {
+	var result []models.Compounding
+	for _, table := range c.Tables {
+		if !slices.Contains(result, table.Compounding) {
+			result = append(result, table.Compounding)
+		}
+	}
+	slices.Sort(result)
+
+	return result
+}
+
+// table возвращает таблицу для аккаунта и его тип; ok ложно, если процентов аккаунту не положено.
+func (c Config) table(account models.Account) (Table, string, bool) {
+	kind, ok := account.Labels[c.TypeLabel]
+	if !ok {
+		return Table{}, "", false
+	}
+	table, ok := c.Tables[kind]
+
+	return table, kind, ok
+}
+
+// Rate — годовая ставка для баланса в базисных пунктах.
+func (t Table) Rate(balance int) int {
+	rate := 0
+	for _, tier := range t.Tiers {
+		if balance < tier.MinBalance {
+			break
+		}
+		rate = tier.Rate
+	}
+
+	return rate
+}
+
+// Interest — проценты на баланс за days дней: balance × rate / 10000 × days / 365 с округлением таблицы.
+func (t Table) Interest(balance, days int) int {
+	if balance <= 0 {
+		return 0
+	}
+
+	return int(round(int64(balance)*int64(t.Rate(balance))*int64(days), 10_000*daysInYear, t.Rounding))
+}
+
+// round делит неотрицательное num на den по правилу mode.
+func round(num, den int64, mode Rounding) int64 {
+	q, r := num/den, num%den
+	switch {
+	case mode == RoundDown || r == 0:
+		return q
+	case 2*r > den, 2*r == den && (mode == RoundHalfUp || q%2 == 1):
+		return q + 1
+	default:
+		return q
+	}
+}
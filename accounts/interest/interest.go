@@ -0,0 +1,213 @@
+// Package interest начисляет проценты на остатки счетов по таблицам ставок.
+//
+// Тип аккаунта — значение метки Config.TypeLabel; по нему выбирается таблица ставок.
+// Проценты за период — баланс × годовая ставка × дней в периоде / 365, округлённые по правилу таблицы,
+// и зачисляются проводкой на баланс, так что следующий период считает проценты уже с них.
+// Баланс берётся на момент проводки, то есть вскоре после окончания периода.
+//
+// Начисление за период идёт одним пакетом по аккаунтам в порядке ID. Хранилище сохраняет
+// каждую проводку вместе с курсором пакета, поэтому пакет, прерванный сбоем, продолжается
+// с места остановки, а повторный запуск за тот же период ничего не начисляет дважды.
+package interest
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultInterval — период, с которым Run проверяет, не закончился ли очередной период начисления.
+const DefaultInterval = time.Hour
+
+type Engine struct {
+	store    storage.Storage
+	config   Config
+	interval time.Duration
+}
+
+type Option func(e *Engine)
+
+// WithInterval задаёт период проходов Run.
+func WithInterval(interval time.Duration) Option {
+	return func(e *Engine) {
+		e.interval = interval
+	}
+}
+
+// New создаёт движок; config должен быть проверен Config.Validate.
+func New(store storage.Storage, config Config, opts ...Option) *Engine {
+	e := &Engine{
+		store:    store,
+		config:   config,
+		interval: DefaultInterval,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Run начисляет проценты за закончившиеся периоды раз в интервал, пока не отменён ctx.
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.AccrueDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("accrue interest failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AccrueDue доводит до конца незавершённые пакеты и начисляет проценты за периоды, закончившиеся к now.
+// Пропущенные периоды начисляются по одному, начиная с периода после последнего пакета;
+// без пакетов начинается с последнего закончившегося периода.
+func (e *Engine) AccrueDue(ctx context.Context, now time.Time) ([]models.Accrual, error) {
+	existing, err := e.store.Accruals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var accruals []models.Accrual
+	for _, compounding := range e.config.Compoundings() {
+		current := models.PeriodOf(compounding, now)
+		period := models.PeriodOf(compounding, current.Start.Add(-time.Nanosecond))
+		// Accruals упорядочены по периодам, так что последний найденный пакет — самый новый.
+		for _, accrual := range existing {
+			if accrual.Period.Compounding != compounding {
+				continue
+			}
+			period = accrual.Period
+			if accrual.Status == models.AccrualCompleted {
+				period = period.Next()
+			}
+		}
+
+		for ; period.Closed(now); period = period.Next() {
+			accrual, err := e.Accrue(ctx, period, now)
+			if err != nil {
+				return accruals, err
+			}
+			accruals = append(accruals, accrual)
+		}
+	}
+
+	return accruals, nil
+}
+
+// Accrue начисляет проценты за закончившийся период или продолжает прерванный пакет за него.
+// Аккаунт, которому нельзя зачислить проценты (например, замороженный), пропускается.
+func (e *Engine) Accrue(ctx context.Context, period models.Period, now time.Time) (models.Accrual, error) {
+	if err := e.check(period); err != nil {
+		return models.Accrual{}, err
+	}
+	if !period.Closed(now) {
+		return models.Accrual{}, errs.InvalidField("period", fmt.Sprintf("period %s has not ended yet", period.ID()))
+	}
+
+	accrual, err := e.store.BeginAccrual(ctx, period, now)
+	if err != nil {
+		return models.Accrual{}, err
+	}
+	if accrual.Status == models.AccrualCompleted {
+		return accrual, nil
+	}
+
+	accounts, err := e.eligible(ctx, period.Compounding, accrual.Cursor)
+	if err != nil {
+		return models.Accrual{}, err
+	}
+	for _, account := range accounts {
+		_, err := e.store.PostInterest(ctx, accrual.ID, account.ID, func(locked models.Account) int {
+			return e.posting(locked, period).Amount
+		}, now)
+		switch {
+		case errors.Is(err, errs.ErrInvalidArgument), errors.Is(err, errs.ErrNotFound), errors.Is(err, errs.ErrFailedPrecondition):
+			log.Printf("accrual %s skipped account %s: %v", accrual.ID, account.ID, err)
+		case err != nil:
+			return models.Accrual{}, fmt.Errorf("accrual %s stopped at account %s: %w", accrual.ID, account.ID, err)
+		}
+	}
+
+	return e.store.FinishAccrual(ctx, accrual.ID, now)
+}
+
+// Project считает, сколько начислит пакет за период по текущим балансам, ничего не записывая.
+// Период может быть и текущим, ещё не закончившимся.
+func (e *Engine) Project(ctx context.Context, period models.Period) (models.Accrual, []models.Posting, error) {
+	if err := e.check(period); err != nil {
+		return models.Accrual{}, nil, err
+	}
+	accounts, err := e.eligible(ctx, period.Compounding, "")
+	if err != nil {
+		return models.Accrual{}, nil, err
+	}
+
+	accrual := models.Accrual{ID: period.ID(), Period: period}
+	postings := make([]models.Posting, 0, len(accounts))
+	for _, account := range accounts {
+		posting := e.posting(account, period)
+		if posting.Amount > 0 {
+			accrual.Postings++
+			accrual.Total += posting.Amount
+		}
+		postings = append(postings, posting)
+	}
+
+	return accrual, postings, nil
+}
+
+// check отклоняет период, который не капитализирует ни одна таблица.
+func (e *Engine) check(period models.Period) error {
+	if !slices.Contains(e.config.Compoundings(), period.Compounding) {
+		return errs.InvalidField("period", fmt.Sprintf("no rate table compounds %s", period.Compounding))
+	}
+
+	return nil
+}
+
+// eligible возвращает живые аккаунты с таблицей ставок данной капитализации и ID больше after, по возрастанию ID.
+func (e *Engine) eligible(ctx context.Context, compounding models.Compounding, after string) ([]models.Account, error) {
+	typed, err := e.store.List(ctx, storage.MatchLabels(models.Selector{{Key: e.config.TypeLabel, Op: models.OpExists}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []models.Account
+	for _, account := range typed {
+		if table, _, ok := e.config.table(account); ok && table.Compounding == compounding && account.ID > after {
+			accounts = append(accounts, account)
+		}
+	}
+	slices.SortFunc(accounts, func(a, b models.Account) int { return strings.Compare(a.ID, b.ID) })
+
+	return accounts, nil
+}
+
+// posting считает проценты аккаунта за период; аккаунт без подходящей таблицы получает ноль.
+func (e *Engine) posting(account models.Account, period models.Period) models.Posting {
+	posting := models.Posting{AccountID: account.ID, Name: account.Name, Balance: account.Amount}
+	table, kind, ok := e.config.table(account)
+	if !ok || table.Compounding != period.Compounding {
+		return posting
+	}
+	posting.Type = kind
+	posting.Rate = table.Rate(account.Amount)
+	posting.Amount = table.Interest(account.Amount, period.Days())
+
+	return posting
+}
@@ -0,0 +1,218 @@
+package interest
+
+import (
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"context"
+	"testing"
+	"time"
+)
+
+// day — первый день, за который в тестах начисляются проценты.
+var day = time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+// percentADay — 365% годовых, то есть ровно 1% в день: проценты легко считать в уме.
+const percentADay = 36_500
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		num, den int64
+		mode     Rounding
+		want     int64
+	}{
+		{num: 6, den: 2, mode: RoundHalfEven, want: 3},
+		{num: 5, den: 2, mode: RoundHalfEven, want: 2},
+		{num: 7, den: 2, mode: RoundHalfEven, want: 4},
+		{num: 5, den: 2, mode: RoundHalfUp, want: 3},
+		{num: 7, den: 2, mode: RoundHalfUp, want: 4},
+		{num: 7, den: 2, mode: RoundDown, want: 3},
+		{num: 11, den: 4, mode: RoundHalfEven, want: 3},
+		{num: 11, den: 4, mode: RoundDown, want: 2},
+		{num: 9, den: 4, mode: RoundHalfUp, want: 2},
+	}
+	for _, tt := range tests {
+		if got := round(tt.num, tt.den, tt.mode); got != tt.want {
+			t.Errorf("round(%d, %d, %s) = %d, want %d", tt.num, tt.den, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestTableInterest(t *testing.T) {
+	tiered := Table{Rounding: RoundHalfEven, Tiers: []Tier{{MinBalance: 100, Rate: 1_000}, {MinBalance: 365_000, Rate: 2_000}}}
+	tests := []struct {
+		name    string
+		table   Table
+		balance int
+		days    int
+		want    int
+	}{
+		{name: "below the first tier", table: tiered, balance: 99, days: 365, want: 0},
+		{name: "first tier for a year", table: tiered, balance: 1_000, days: 365, want: 100},
+		{name: "second tier for february", table: tiered, balance: 365_000, days: 28, want: 5_600},
+		{name: "negative balance", table: tiered, balance: -1_000, days: 365, want: 0},
+		{name: "half to even down", table: Table{Rounding: RoundHalfEven, Tiers: []Tier{{Rate: percentADay}}}, balance: 250, days: 1, want: 2},
+		{name: "half to even up", table: Table{Rounding: RoundHalfEven, Tiers: []Tier{{Rate: percentADay}}}, balance: 350, days: 1, want: 4},
+		{name: "half up", table: Table{Rounding: RoundHalfUp, Tiers: []Tier{{Rate: percentADay}}}, balance: 250, days: 1, want: 3},
+		{name: "down", table: Table{Rounding: RoundDown, Tiers: []Tier{{Rate: percentADay}}}, balance: 399, days: 1, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.table.Interest(tt.balance, tt.days); got != tt.want {
+				t.Fatalf("Interest(%d, %d) = %d, want %d", tt.balance, tt.days, got, tt.want)
+			}
+		})
+	}
+}
+
+// setup заводит хранилище со сберегательным аккаунтом saver и аккаунтом other без типа.
+func setup(t *testing.T, rounding Rounding, amount int) (*storage.Memory, *Engine) {
+	t.Helper()
+	store := storage.NewMemory()
+	accounts := []models.Account{
+		{Name: "saver", Amount: amount, Labels: map[string]string{DefaultTypeLabel: "savings"}},
+		{Name: "other", Amount: amount},
+	}
+	for _, account := range accounts {
+		if _, err := store.Create(context.Background(), account); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := Config{Tables: map[string]Table{
+		"savings": {Compounding: models.CompoundDaily, Rounding: rounding, Tiers: []Tier{{Rate: percentADay}}},
+	}}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	return store, New(store, config)
+}
+
+func daily(start time.Time) models.Period {
+	return models.PeriodOf(models.CompoundDaily, start)
+}
+
+func checkBalance(t *testing.T, store storage.Storage, name string, want int) {
+	t.Helper()
+	account, err := store.Get(context.Background(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Amount != want {
+		t.Fatalf("%s has %d, want %d", name, account.Amount, want)
+	}
+}
+
+func ledger(t *testing.T, store storage.Storage, name string) []models.LedgerEntry {
+	t.Helper()
+	entries, err := store.Ledger(context.Background(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return entries
+}
+
+func TestAccrueRoundsOnePeriod(t *testing.T) {
+	store, engine := setup(t, RoundHalfUp, 250)
+	period := daily(day)
+
+	accrual, err := engine.Accrue(context.Background(), period, period.End())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1% от 250 — 2,5; половина округляется вверх.
+	if accrual.Status != models.AccrualCompleted || accrual.Postings != 1 || accrual.Total != 3 {
+		t.Fatalf("accrual = %+v, want one completed posting of 3", accrual)
+	}
+	checkBalance(t, store, "saver", 253)
+	checkBalance(t, store, "other", 250)
+
+	entries := ledger(t, store, "saver")
+	if len(entries) != 1 || entries[0].Amount != 3 || entries[0].Balance != 253 || entries[0].Reference != period.ID() {
+		t.Fatalf("ledger = %+v, want one entry of 3 for %s", entries, period.ID())
+	}
+}
+
+func TestAccrueCompounds(t *testing.T) {
+	store, engine := setup(t, RoundHalfEven, 10_000)
+
+	// Каждый день проценты считаются с баланса, в который вошли проценты прошлых дней:
+	// 10000 → 10100 → 10201 → 10303 (102,01 округляется до 102).
+	want := []int{100, 101, 102}
+	balance := 10_000
+	for i, period := 0, daily(day); i < len(want); i, period = i+1, period.Next() {
+		accrual, err := engine.Accrue(context.Background(), period, period.End())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accrual.Total != want[i] {
+			t.Fatalf("day %d accrued %d, want %d", i+1, accrual.Total, want[i])
+		}
+		balance += want[i]
+		checkBalance(t, store, "saver", balance)
+	}
+	if entries := ledger(t, store, "saver"); len(entries) != len(want) || entries[len(want)-1].Balance != 10_303 {
+		t.Fatalf("ledger = %+v, want %d entries ending at 10303", entries, len(want))
+	}
+}
+
+// TestAccrueIdempotent: повторное начисление за тот же период, в том числе из AccrueDue
+// и после прерванного пакета, не зачисляет проценты второй раз.
+func TestAccrueIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store, engine := setup(t, RoundHalfEven, 10_000)
+	period := daily(day)
+
+	// Пакет прервался сразу после проводки: её повтор возвращает ту же запись.
+	started, err := store.BeginAccrual(ctx, period, period.End())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := store.PostInterest(ctx, started.ID, "saver", func(models.Account) int { return 100 }, period.End())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accrual, err := engine.Accrue(ctx, period, period.End())
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := engine.Accrue(ctx, period, period.End().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != accrual || accrual.Total != 100 || accrual.Postings != 1 {
+		t.Fatalf("accruals = %+v and %+v, want the same completed accrual of 100", accrual, again)
+	}
+	if due, err := engine.AccrueDue(ctx, period.End().Add(time.Hour)); err != nil || len(due) != 0 {
+		t.Fatalf("AccrueDue = %+v, %v; want nothing left to accrue", due, err)
+	}
+	checkBalance(t, store, "saver", 10_100)
+	if entries := ledger(t, store, "saver"); len(entries) != 1 || entries[0] != first {
+		t.Fatalf("ledger = %+v, want only %+v", entries, first)
+	}
+}
+
+func TestProjectDryRun(t *testing.T) {
+	ctx := context.Background()
+	store, engine := setup(t, RoundHalfEven, 10_000)
+	period := daily(day)
+
+	for i := 0; i < 2; i++ {
+		accrual, postings, err := engine.Project(ctx, period)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accrual.Total != 100 || accrual.Postings != 1 || len(postings) != 1 || postings[0].Amount != 100 || postings[0].Rate != percentADay {
+			t.Fatalf("projection = %+v, %+v; want one posting of 100", accrual, postings)
+		}
+	}
+
+	checkBalance(t, store, "saver", 10_000)
+	if entries := ledger(t, store, "saver"); len(entries) != 0 {
+		t.Fatalf("dry run posted %+v", entries)
+	}
+	if accruals, err := store.Accruals(ctx); err != nil || len(accruals) != 0 {
+		t.Fatalf("dry run started accruals %+v, %v", accruals, err)
+	}
+}
@@ -0,0 +1,118 @@
+package accounts
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/validation"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// Создать аккаунт
+func (h *Handler) LegacyCreateAccount(c echo.Context) error {
+	var request dto.CreateAccountRequest // {"name": "alice", "amount": 50}
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	v := validation.New()
+	name := v.Name("name", request.Name)
+	v.Amount("amount", request.Amount)
+	status := v.InitialStatus("status", request.Status)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	_, err := h.storage.Create(c.Request().Context(), models.Account{
+		Name:   name,
+		Amount: request.Amount,
+		Status: status,
+	})
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// Находит аккаунт
+func (h *Handler) LegacyGetAccount(c echo.Context) error {
+
+	v := validation.New()
+	name := v.Lookup("name", c.QueryParams().Get("name"))
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	account, err := h.storage.Get(c.Request().Context(), name)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, accountResponse(account))
+}
+
+// Удаляет аккаунт
+func (h *Handler) LegacyDeleteAccount(c echo.Context) error {
+	var request dto.DeleteAccountRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+	v := validation.New()
+	name := v.Lookup("name", request.Name)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	if err := h.storage.Delete(c.Request().Context(), name, actor(c)); err != nil {
+		return writeError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// Меняет баланс
+func (h *Handler) LegacyPatchAccount(c echo.Context) error {
+	var request dto.PatchAccountRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+	v := validation.New()
+	name := v.Lookup("name", request.Name)
+	v.Amount("amount", request.Amount)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	if _, err := h.storage.ChangeAmount(c.Request().Context(), name, request.Amount); err != nil {
+		return writeError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// Меняет имя
+func (h *Handler) LegacyChangeAccount(c echo.Context) error {
+	var request dto.ChangeAccountRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+	v := validation.New()
+	name := v.Lookup("name", request.Name)
+	newName := v.Name("new_name", request.NewName)
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	if _, err := h.storage.ChangeName(c.Request().Context(), name, newName); err != nil {
+		return writeError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
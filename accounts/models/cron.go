@@ -0,0 +1,169 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron — расписание в формате crontab из пяти полей: минута, час, день месяца, месяц, день недели.
+// Поле — список через запятую из *, N, N-M с необязательным шагом /S; месяцы и дни недели можно
+// писать именами (jan, mon), воскресенье — 0 или 7. Как в cron, если ограничены и день месяца,
+// и день недели, подходит любой из них. Поддерживаются @hourly, @daily, @weekly, @monthly и @yearly.
+// Время считается в UTC.
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	// domAny и dowAny — поле записано как *; тогда совпадение дня определяет другое поле.
+	domAny, dowAny bool
+}
+
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var (
+	monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// ParseCron разбирает выражение вида "0 9 1 * *" или макрос "@monthly".
+func ParseCron(expr string) (Cron, error) {
+	spec := strings.ToLower(strings.TrimSpace(expr))
+	if macro, ok := cronMacros[spec]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("expected 5 fields (minute hour day month weekday), got %d in %q", len(fields), expr)
+	}
+
+	var c Cron
+	var err error
+	if c.minute, err = cronField(fields[0], 0, 59, nil, 0); err != nil {
+		return Cron{}, fmt.Errorf("minute: %w", err)
+	}
+	if c.hour, err = cronField(fields[1], 0, 23, nil, 0); err != nil {
+		return Cron{}, fmt.Errorf("hour: %w", err)
+	}
+	if c.dom, err = cronField(fields[2], 1, 31, nil, 0); err != nil {
+		return Cron{}, fmt.Errorf("day of month: %w", err)
+	}
+	if c.month, err = cronField(fields[3], 1, 12, monthNames, 1); err != nil {
+		return Cron{}, fmt.Errorf("month: %w", err)
+	}
+	if c.dow, err = cronField(fields[4], 0, 7, dayNames, 0); err != nil {
+		return Cron{}, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 — тоже воскресенье.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+
+	return c, nil
+}
+
+// cronField разбирает одно поле в битовую маску значений от lo до hi.
+// names — имена значений, начиная со значения base.
+func cronField(field string, lo, hi int, names []string, base int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		from, to := lo, hi
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			first, last, _ := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = cronValue(first, lo, hi, names, base); err != nil {
+				return 0, err
+			}
+			if to, err = cronValue(last, lo, hi, names, base); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := cronValue(rangePart, lo, hi, names, base)
+			if err != nil {
+				return 0, err
+			}
+			from = value
+			if !hasStep {
+				to = value
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+func cronValue(s string, lo, hi int, names []string, base int) (int, error) {
+	for i, name := range names {
+		if s == name {
+			return base + i, nil
+		}
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil || value < lo || value > hi {
+		return 0, fmt.Errorf("value %q is out of range %d-%d", s, lo, hi)
+	}
+
+	return value, nil
+}
+
+// Next возвращает первый момент расписания строго после t или нулевое время,
+// если за пять лет такого нет (например, 30 февраля).
+func (c Cron) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+
+	return dom || dow
+}
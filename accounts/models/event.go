@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// EventType — вид события об изменении аккаунта.
+type EventType string
+
+const (
+	EventAccountCreated  EventType = "account.created"
+	EventAccountUpdated  EventType = "account.updated"
+	EventAccountRenamed  EventType = "account.renamed"
+	EventAccountDeleted  EventType = "account.deleted"
+	EventAccountRestored EventType = "account.restored"
+	EventStatusChanged   EventType = "account.status_changed"
+	// EventBalanceChanged — изменился баланс: установка, перевод, списание холда, проценты.
+	EventBalanceChanged EventType = "account.balance_changed"
+)
+
+// EventTypes — все виды событий.
+var EventTypes = []EventType{
+	EventAccountCreated, EventAccountUpdated, EventAccountRenamed, EventAccountDeleted,
+	EventAccountRestored, EventStatusChanged, EventBalanceChanged,
+}
+
+// Event — событие об изменении аккаунта; Account — аккаунт после изменения.
+type Event struct {
+	ID string
+	// Sequence — номер события в outbox хранилища; события одного аккаунта нумеруются в порядке изменений.
+	Sequence   int64
+	Type       EventType
+	Account    Account
+	OccurredAt time.Time
+}
+
+// NewEvent создаёт событие с новым ID.
+func NewEvent(eventType EventType, account Account) Event {
+	return Event{ID: NewID(), Type: eventType, Account: account, OccurredAt: time.Now().UTC()}
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// HoldStatus — состояние холда.
+type HoldStatus string
+
+const (
+	// HoldActive — средства зарезервированы и недоступны для списаний.
+	HoldActive   HoldStatus = "active"
+	HoldCaptured HoldStatus = "captured"
+	HoldReleased HoldStatus = "released"
+	// HoldExpired — холд не списан и не снят до ExpiresAt, резерв освобождён.
+	HoldExpired HoldStatus = "expired"
+)
+
+// Hold — резерв средств на аккаунте: уменьшает доступный баланс, но не сам баланс.
+// Холд списывается (Capture) целиком или частично, снимается (Release) или истекает в ExpiresAt.
+type Hold struct {
+	ID        string
+	AccountID string
+	Amount    int
+	Status    HoldStatus
+	// Captured — списанная сумма; остаток холда при списании освобождается.
+	Captured  int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// ClosedAt — момент списания, снятия или истечения.
+	ClosedAt time.Time
+}
+
+// At возвращает холд, каким он виден в момент now: активный холд с истёкшим сроком считается истёкшим,
+// даже если хранилище ещё не отметило это.
+func (h Hold) At(now time.Time) Hold {
+	if h.Status == HoldActive && !now.Before(h.ExpiresAt) {
+		h.Status = HoldExpired
+		h.ClosedAt = h.ExpiresAt
+	}
+
+	return h
+}
+
+// Active сообщает, что холд резервирует средства в момент now.
+func (h Hold) Active(now time.Time) bool {
+	return h.At(now).Status == HoldActive
+}
+
+// Held — сумма холдов, активных в момент now.
+func Held(holds []Hold, now time.Time) int {
+	held := 0
+	for _, h := range holds {
+		if h.Active(now) {
+			held += h.Amount
+		}
+	}
+
+	return held
+}
@@ -0,0 +1,23 @@
+package models
+
+import "github.com/google/uuid"
+
+// NewID выдаёт неизменяемый идентификатор аккаунта. UUIDv7 растёт со временем,
+// поэтому новые строки попадают в конец индекса первичного ключа.
+func NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// ParseID сообщает, похожа ли ссылка на аккаунт на идентификатор, и возвращает его каноническую запись.
+// Ссылки в других формах считаются именами.
+func ParseID(ref string) (string, bool) {
+	if len(ref) != 36 {
+		return "", false
+	}
+	id, err := uuid.Parse(ref)
+	if err != nil {
+		return "", false
+	}
+
+	return id.String(), true
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Compounding — период капитализации: начисленные за него проценты прибавляются к балансу.
+type Compounding string
+
+const (
+	CompoundDaily   Compounding = "daily"
+	CompoundMonthly Compounding = "monthly"
+)
+
+// Period — период начисления процентов: сутки или календарный месяц в UTC.
+type Period struct {
+	Compounding Compounding
+	Start       time.Time
+}
+
+// PeriodOf возвращает период, в который попадает t.
+func PeriodOf(compounding Compounding, t time.Time) Period {
+	t = t.UTC()
+	if compounding == CompoundMonthly {
+		return Period{Compounding: compounding, Start: time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)}
+	}
+
+	return Period{Compounding: compounding, Start: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// ParsePeriod разбирает ID периода: "daily-2026-10-18" или "monthly-2026-10".
+func ParsePeriod(id string) (Period, error) {
+	compounding, date, _ := strings.Cut(id, "-")
+	var layout string
+	switch Compounding(compounding) {
+	case CompoundDaily:
+		layout = time.DateOnly
+	case CompoundMonthly:
+		layout = "2006-01"
+	default:
+		return Period{}, fmt.Errorf("invalid period %q, expected daily-YYYY-MM-DD or monthly-YYYY-MM", id)
+	}
+	start, err := time.Parse(layout, date)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid period %q, expected daily-YYYY-MM-DD or monthly-YYYY-MM", id)
+	}
+
+	return Period{Compounding: Compounding(compounding), Start: start}, nil
+}
+
+// ID — запись периода, которую понимает ParsePeriod; она же ID пакета начисления за период.
+func (p Period) ID() string {
+	if p.Compounding == CompoundMonthly {
+		return string(p.Compounding) + "-" + p.Start.Format("2006-01")
+	}
+
+	return string(p.Compounding) + "-" + p.Start.Format(time.DateOnly)
+}
+
+// End — начало следующего периода.
+func (p Period) End() time.Time {
+	if p.Compounding == CompoundMonthly {
+		return p.Start.AddDate(0, 1, 0)
+	}
+
+	return p.Start.AddDate(0, 0, 1)
+}
+
+// Days — число дней в периоде.
+func (p Period) Days() int {
+	return int(p.End().Sub(p.Start) / (24 * time.Hour))
+}
+
+func (p Period) Next() Period {
+	return Period{Compounding: p.Compounding, Start: p.End()}
+}
+
+// Closed сообщает, что период закончился к моменту now и за него можно начислять.
+func (p Period) Closed(now time.Time) bool {
+	return !now.Before(p.End())
+}
+
+// AccrualStatus — состояние пакета начисления.
+type AccrualStatus string
+
+const (
+	// AccrualRunning — пакет начат и не доведён до конца; его продолжают с Cursor.
+	AccrualRunning   AccrualStatus = "running"
+	AccrualCompleted AccrualStatus = "completed"
+)
+
+// Accrual — пакет начисления процентов за период. ID пакета совпадает с Period.ID,
+// так что за один период проценты начисляются не больше одного раза.
+type Accrual struct {
+	ID     string
+	Period Period
+	Status AccrualStatus
+	// Cursor — ID последнего обработанного аккаунта; аккаунты обходятся по возрастанию ID.
+	Cursor string
+	// Postings и Total — число и сумма проводок пакета.
+	Postings   int
+	Total      int
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// EntryKind — вид проводки.
+type EntryKind string
+
+const EntryInterest EntryKind = "interest"
+
+// LedgerEntry — проводка по счёту: Amount зачислено, Balance — баланс после проводки.
+// Reference связывает проводку с её источником, например с пакетом начисления; у аккаунта
+// не бывает двух проводок с одним Reference.
+type LedgerEntry struct {
+	ID        string
+	AccountID string
+	Kind      EntryKind
+	Amount    int
+	Balance   int
+	Reference string
+	PostedAt  time.Time
+}
+
+// Posting — расчёт процентов по аккаунту: Rate — годовая ставка в базисных пунктах,
+// Amount — сумма к зачислению на баланс Balance.
+type Posting struct {
+	AccountID string
+	Name      string
+	Type      string
+	Balance   int
+	Rate      int
+	Amount    int
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Operator — операция требования селектора меток.
+type Operator string
+
+const (
+	OpEquals    Operator = "="
+	OpNotEquals Operator = "!="
+	// OpExists требует, чтобы метка была, с любым значением.
+	OpExists    Operator = "exists"
+	OpNotExists Operator = "!exists"
+)
+
+// Requirement — одно требование селектора, например tier=gold.
+type Requirement struct {
+	Key   string
+	Op    Operator
+	Value string
+}
+
+// Selector — требования к меткам через запятую; аккаунт подходит, если выполнены все.
+// Синтаксис: key=value (или key==value), key!=value, key, !key.
+// key!=value выполняется и для аккаунтов без метки key.
+type Selector []Requirement
+
+// ParseSelector разбирает селектор вида "tier=gold,region!=us". Пустая строка выбирает всё.
+func ParseSelector(s string) (Selector, error) {
+	var selector Selector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("empty requirement in %q", s)
+		}
+
+		var r Requirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			r = Requirement{Key: key, Op: OpNotEquals, Value: value}
+		case strings.Contains(part, "=="):
+			key, value, _ := strings.Cut(part, "==")
+			r = Requirement{Key: key, Op: OpEquals, Value: value}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			r = Requirement{Key: key, Op: OpEquals, Value: value}
+		case strings.HasPrefix(part, "!"):
+			r = Requirement{Key: part[1:], Op: OpNotExists}
+		default:
+			r = Requirement{Key: part, Op: OpExists}
+		}
+		r.Key = strings.TrimSpace(r.Key)
+		r.Value = strings.TrimSpace(r.Value)
+		if r.Key == "" {
+			return nil, fmt.Errorf("requirement %q has no label key", part)
+		}
+		selector = append(selector, r)
+	}
+
+	return selector, nil
+}
+
+// Matches сообщает, что метки удовлетворяют всем требованиям.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		value, ok := labels[r.Key]
+		switch r.Op {
+		case OpEquals:
+			if !ok || value != r.Value {
+				return false
+			}
+		case OpNotEquals:
+			if ok && value == r.Value {
+				return false
+			}
+		case OpExists:
+			if !ok {
+				return false
+			}
+		case OpNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		switch r.Op {
+		case OpExists:
+			parts = append(parts, r.Key)
+		case OpNotExists:
+			parts = append(parts, "!"+r.Key)
+		default:
+			parts = append(parts, r.Key+string(r.Op)+r.Value)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// FormatLabels записывает метки как key=value через запятую, по порядку ключей.
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+labels[key])
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// MetadataChange — изменение описания и меток аккаунта.
+type MetadataChange struct {
+	// Description, если не nil, заменяет описание.
+	Description *string
+	// ReplaceLabels заменяет все метки на SetLabels; иначе SetLabels дополняют текущие.
+	ReplaceLabels bool
+	SetLabels     map[string]string
+	RemoveLabels  []string
+}
+
+// Empty сообщает, что изменение ничего не меняет.
+func (c MetadataChange) Empty() bool {
+	return c.Description == nil && !c.ReplaceLabels && len(c.SetLabels) == 0 && len(c.RemoveLabels) == 0
+}
+
+// Apply возвращает новые метки; labels не меняются, потому что их могут читать параллельно.
+func (c MetadataChange) Apply(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+len(c.SetLabels))
+	if !c.ReplaceLabels {
+		for key, value := range labels {
+			result[key] = value
+		}
+	}
+	for _, key := range c.RemoveLabels {
+		delete(result, key)
+	}
+	for key, value := range c.SetLabels {
+		result[key] = value
+	}
+
+	return result
+}
+
+// Update — изменение аккаунта по маске полей: nil-поля и пустой Metadata не меняются.
+type Update struct {
+	Name     *string
+	Amount   *int
+	Metadata MetadataChange
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     Selector
+		err      bool
+	}{
+		{selector: "", want: nil},
+		{selector: "  ", want: nil},
+		{selector: "tier=gold,!legacy", want: Selector{{Key: "tier", Op: OpEquals, Value: "gold"}, {Key: "legacy", Op: OpNotExists}}},
+		{selector: "tier==gold", want: Selector{{Key: "tier", Op: OpEquals, Value: "gold"}}},
+		{selector: " region != us , vip ", want: Selector{{Key: "region", Op: OpNotEquals, Value: "us"}, {Key: "vip", Op: OpExists}}},
+		{selector: "tier=", want: Selector{{Key: "tier", Op: OpEquals}}},
+		{selector: "tier=gold,,vip", err: true},
+		{selector: "tier=gold,", err: true},
+		{selector: ",", err: true},
+		{selector: "=gold", err: true},
+		{selector: "!=us", err: true},
+		{selector: "!", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, err := ParseSelector(tt.selector)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("ParseSelector() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseSelector() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	selector, err := ParseSelector("tier=gold,!legacy,region!=us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := selector.String(); got != "tier=gold,!legacy,region!=us" {
+		t.Fatalf("String() = %q", got)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{name: "all met", labels: map[string]string{"tier": "gold", "region": "eu"}, want: true},
+		{name: "not equal holds without the label", labels: map[string]string{"tier": "gold"}, want: true},
+		{name: "other tier", labels: map[string]string{"tier": "silver"}, want: false},
+		{name: "no tier", labels: nil, want: false},
+		{name: "legacy", labels: map[string]string{"tier": "gold", "legacy": ""}, want: false},
+		{name: "excluded region", labels: map[string]string{"tier": "gold", "region": "us"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selector.Matches(tt.labels); got != tt.want {
+				t.Fatalf("Matches(%v) = %t, want %t", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataChangeApply(t *testing.T) {
+	labels := map[string]string{"tier": "gold", "region": "eu"}
+	tests := []struct {
+		name   string
+		change MetadataChange
+		want   map[string]string
+	}{
+		{name: "set and remove", change: MetadataChange{SetLabels: map[string]string{"tier": "silver"}, RemoveLabels: []string{"region"}},
+			want: map[string]string{"tier": "silver"}},
+		{name: "replace", change: MetadataChange{ReplaceLabels: true, SetLabels: map[string]string{"vip": "yes"}},
+			want: map[string]string{"vip": "yes"}},
+		{name: "replace with nothing", change: MetadataChange{ReplaceLabels: true}, want: map[string]string{}},
+		{name: "remove a missing label", change: MetadataChange{RemoveLabels: []string{"vip"}}, want: labels},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.Apply(labels); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+			if len(labels) != 2 || labels["tier"] != "gold" {
+				t.Fatalf("Apply changed its input: %v", labels)
+			}
+		})
+	}
+}
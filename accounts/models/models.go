@@ -1,6 +1,53 @@
 package models
 
+import "time"
+
+// Account — аккаунт. ID выдаётся при создании и не меняется; Name можно переименовать.
+// Операции принимают ссылку на аккаунт: его ID или имя.
 type Account struct {
-	Name   string
+	ID   string
+	Name string
+	// Amount — баланс по учёту; часть его может быть зарезервирована холдами.
 	Amount int
+	// Held — сумма активных холдов; хранилище вычисляет её при каждом чтении.
+	Held   int
+	Status Status
+	// Description и Labels — произвольные сведения об аккаунте, например labels region=eu, tier=gold.
+	Description string
+	Labels      map[string]string
+	// CreatedAt — момент создания, UpdatedAt — последнего изменения.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// DeletedAt и DeletedBy заполнены у удалённого аккаунта; до очистки его можно восстановить.
+	DeletedAt time.Time
+	DeletedBy string
+}
+
+// Available — баланс, доступный для списаний: Amount без активных холдов.
+func (a Account) Available() int {
+	return a.Amount - a.Held
 }
+
+// Deleted сообщает, что аккаунт удалён и ждёт очистки.
+func (a Account) Deleted() bool {
+	return !a.DeletedAt.IsZero()
+}
+
+// RefersTo сообщает, что ref — ID или текущее имя аккаунта.
+func (a Account) RefersTo(ref string) bool {
+	if id, ok := ParseID(ref); ok && a.ID == id {
+		return true
+	}
+
+	return a.Name == ref
+}
+
+// Rename — запись истории переименований аккаунта.
+type Rename struct {
+	From string
+	To   string
+	At   time.Time
+}
+
+// UnknownActor записывается как автор изменения, если клиент не представился.
+const UnknownActor = "unknown"
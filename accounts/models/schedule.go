@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// ScheduleStatus — состояние расписания перевода.
+type ScheduleStatus string
+
+const (
+	ScheduleActive ScheduleStatus = "active"
+	// SchedulePaused — расписание не исполняется, пока его не возобновят.
+	SchedulePaused    ScheduleStatus = "paused"
+	ScheduleCancelled ScheduleStatus = "cancelled"
+	// ScheduleCompleted — разовый перевод исполнен, повторов не будет.
+	ScheduleCompleted ScheduleStatus = "completed"
+)
+
+// Schedule — поручение перевести Amount со счёта From на счёт To: один раз в NextRunAt
+// или по расписанию Cron. From и To — ID аккаунтов, так что переименование поручение не ломает.
+type Schedule struct {
+	ID     string
+	From   string
+	To     string
+	Amount int
+	// Cron пуст у разового перевода.
+	Cron        string
+	Description string
+	Status      ScheduleStatus
+	// NextRunAt — срок следующего исполнения; нулевой у отменённого и завершённого расписания.
+	NextRunAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Recurring сообщает, что перевод повторяется по Cron.
+func (s Schedule) Recurring() bool {
+	return s.Cron != ""
+}
+
+// After — срок исполнения после t: следующий момент Cron или нулевое время у разового перевода.
+func (s Schedule) After(t time.Time) time.Time {
+	if !s.Recurring() {
+		return time.Time{}
+	}
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return c.Next(t)
+}
+
+// Due сообщает, что расписание пора исполнить в момент now.
+func (s Schedule) Due(now time.Time) bool {
+	return s.Status == ScheduleActive && !s.NextRunAt.IsZero() && !now.Before(s.NextRunAt)
+}
+
+// RunStatus — итог исполнения расписания.
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	// RunFailed — перевод отклонён, например из-за нехватки средств; повторяется уже следующий срок.
+	RunFailed RunStatus = "failed"
+)
+
+// ScheduleRun — запись об исполнении расписания за срок DueAt.
+// Срок исполняется не больше одного раза: по паре (ScheduleID, DueAt) повтор находит прежнюю запись.
+type ScheduleRun struct {
+	ScheduleID string
+	DueAt      time.Time
+	Status     RunStatus
+	// Error — причина отказа у неуспешного исполнения.
+	Error string
+	RanAt time.Time
+}
+
+// ScheduleAction — управляющее действие над расписанием.
+type ScheduleAction string
+
+const (
+	SchedulePause  ScheduleAction = "pause"
+	ScheduleResume ScheduleAction = "resume"
+	ScheduleCancel ScheduleAction = "cancel"
+)
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Status — состояние жизненного цикла аккаунта.
+type Status string
+
+const (
+	// StatusPending — аккаунт открыт, но ещё не активирован: списания запрещены.
+	StatusPending Status = "pending"
+	StatusActive  Status = "active"
+	// StatusFrozen — аккаунт заморожен, например на время расследования: разрешены только зачисления.
+	StatusFrozen Status = "frozen"
+	// StatusClosed — аккаунт закрыт навсегда и доступен только для чтения.
+	StatusClosed Status = "closed"
+)
+
+// Statuses — все статусы в порядке жизненного цикла.
+var Statuses = []Status{StatusPending, StatusActive, StatusFrozen, StatusClosed}
+
+// Change — именованная смена статуса: из каких статусов она возможна и во что переводит.
+// Из closed выхода нет.
+type Change struct {
+	Name string
+	From []Status
+	To   Status
+}
+
+var (
+	Activate = Change{Name: "activate", From: []Status{StatusPending}, To: StatusActive}
+	Freeze   = Change{Name: "freeze", From: []Status{StatusActive}, To: StatusFrozen}
+	Unfreeze = Change{Name: "unfreeze", From: []Status{StatusFrozen}, To: StatusActive}
+	Close    = Change{Name: "close", From: []Status{StatusPending, StatusActive, StatusFrozen}, To: StatusClosed}
+)
+
+// Changes — все смены статуса.
+var Changes = []Change{Activate, Freeze, Unfreeze, Close}
+
+// AllowedFrom сообщает, возможна ли смена из статуса s.
+func (c Change) AllowedFrom(s Status) bool {
+	for _, from := range c.From {
+		if from == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Action — операция записи, которую разрешает или запрещает статус.
+type Action string
+
+const (
+	ActionCredit Action = "credit"
+	ActionDebit  Action = "debit"
+	ActionRename Action = "rename"
+	ActionDelete Action = "delete"
+	// ActionAnnotate — смена описания и меток.
+	ActionAnnotate Action = "annotate"
+)
+
+// Allows сообщает, разрешена ли операция в статусе s.
+func (s Status) Allows(action Action) bool {
+	switch s {
+	case StatusActive:
+		return true
+	case StatusPending:
+		return action != ActionDebit
+	case StatusFrozen:
+		// Замороженный аккаунт можно разметить, например меткой расследования.
+		return action == ActionCredit || action == ActionAnnotate
+	default:
+		return false
+	}
+}
+
+// Transition — запись истории статусов аккаунта.
+type Transition struct {
+	From   Status
+	To     Status
+	Reason string
+	Actor  string
+	At     time.Time
+}
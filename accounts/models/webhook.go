@@ -0,0 +1,67 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"time"
+)
+
+// secretPrefix отличает сгенерированные секреты подписи от заданных клиентом.
+const secretPrefix = "whsec_"
+
+// Subscription — подписка на события: они отправляются POST запросом на URL,
+// подписанным секретом Secret. Пустые EventTypes и Accounts означают «все».
+type Subscription struct {
+	ID     string
+	URL    string
+	Secret string
+	// EventTypes — виды событий, о которых сообщать.
+	EventTypes []EventType
+	// Accounts — ID аккаунтов, о которых сообщать.
+	Accounts  []string
+	CreatedAt time.Time
+}
+
+// Matches сообщает, что подписка ждёт событие.
+func (s Subscription) Matches(event Event) bool {
+	return (len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, event.Type)) &&
+		(len(s.Accounts) == 0 || slices.Contains(s.Accounts, event.Account.ID))
+}
+
+// NewSecret генерирует секрет подписи из 32 случайных байт.
+func NewSecret() string {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+
+	return secretPrefix + hex.EncodeToString(secret)
+}
+
+// DeliveryStatus — состояние доставки события подписке.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending — доставка ждёт очередной попытки в NextAttemptAt.
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead — попытки кончились; доставка лежит в очереди недоставленных, пока её не повторят.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Delivery — доставка события подписке. Payload — тело запроса, оно не меняется между попытками.
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      EventType
+	AccountID      string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	// LastError и LastStatusCode — итог последней неудачной попытки; код 0 — ответа не было.
+	LastError      string
+	LastStatusCode int
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+}
@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// schemas собирает components.schemas: структуры DTO по json тегам, сообщения proto — по protojson именам.
+type schemas map[string]*Schema
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// of регистрирует схему для значения v и возвращает ссылку на неё.
+func (s schemas) of(v any) *Schema {
+	if m, ok := v.(proto.Message); ok {
+		return s.message(m.ProtoReflect().Descriptor())
+	}
+
+	return s.goType(reflect.TypeOf(v))
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (s schemas) goType(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.goType(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int32, reflect.Int16, reflect.Int8, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.goType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.goType(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := s[name]; ok {
+			return ref(name)
+		}
+
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		s[name] = schema
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			fieldName, options, _ := strings.Cut(tag, ",")
+			if fieldName == "" {
+				fieldName = field.Name
+			}
+			schema.Properties[fieldName] = s.goType(field.Type)
+			if field.Type.Kind() != reflect.Pointer && !strings.Contains(options, "omitempty") {
+				schema.Required = append(schema.Required, fieldName)
+			}
+		}
+
+		return ref(name)
+	default:
+		return &Schema{}
+	}
+}
+
+func (s schemas) message(md protoreflect.MessageDescriptor) *Schema {
+	name := string(md.FullName())
+	// Известные типы protojson кодирует строками.
+	if name == "google.protobuf.Timestamp" {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if name == "google.protobuf.FieldMask" {
+		return &Schema{Type: "string", Description: "comma-separated field paths"}
+	}
+
+	if _, ok := s[name]; ok {
+		return ref(name)
+	}
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s[name] = schema
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		schema.Properties[field.JSONName()] = s.field(field)
+	}
+
+	return ref(name)
+}
+
+func (s schemas) field(fd protoreflect.FieldDescriptor) *Schema {
+	if fd.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: s.field(fd.MapValue())}
+	}
+
+	var schema *Schema
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		schema = &Schema{Type: "string"}
+	case protoreflect.BoolKind:
+		schema = &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		schema = &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson кодирует 64-битные числа строками.
+		schema = &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		schema = &Schema{Type: "number"}
+	case protoreflect.BytesKind:
+		schema = &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		schema = &Schema{Type: "string"}
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			schema.Enum = append(schema.Enum, string(values.Get(i).Name()))
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		schema = s.message(fd.Message())
+	default:
+		schema = &Schema{}
+	}
+
+	if fd.IsList() {
+		return &Schema{Type: "array", Items: schema}
+	}
+
+	return schema
+}
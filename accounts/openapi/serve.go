@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+// Register отдаёт документ по /openapi.json и страницу с интерактивной документацией по /docs.
+func Register(e *echo.Echo, doc *Document) {
+	e.GET("/openapi.json", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, doc)
+	})
+	e.GET("/docs", func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, docsPage)
+	})
+}
+
+// Routes возвращает маршруты, зарегистрированные в echo, в виде, пригодном для Verify.
+func Routes(e *echo.Echo) []Route {
+	routes := make([]Route, 0, len(e.Routes()))
+	for _, route := range e.Routes() {
+		routes = append(routes, Route{Method: route.Method, Path: route.Path})
+	}
+
+	return routes
+}
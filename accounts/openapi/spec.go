@@ -0,0 +1,449 @@
+package openapi
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/proto"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operation описывает один маршрут сервера. Путь записан в синтаксисе echo (:name).
+type operation struct {
+	method     string
+	path       string
+	id         string
+	summary    string
+	tag        string
+	deprecated bool
+	// params — параметры запроса и заголовки, кроме параметров пути.
+	params   []Parameter
+	request  any
+	status   int
+	response any
+	headers  map[string]string
+	errors   []int
+	// contentType ответа без описанной схемы, например text/html.
+	contentType string
+}
+
+var (
+	nameQuery           = Parameter{Name: "name", In: "query", Required: true, Description: "account ID or name", Schema: &Schema{Type: "string"}}
+	includeDeletedQuery = Parameter{Name: "include_deleted", In: "query", Description: "also return deleted accounts that are not purged yet", Schema: &Schema{Type: "boolean"}}
+	labelSelectorQuery  = Parameter{Name: "label_selector", In: "query", Description: "comma-separated label requirements: key=value, key!=value, key, !key", Schema: &Schema{Type: "string"}}
+	updateMaskQuery     = Parameter{Name: "update_mask", In: "query", Description: "comma-separated fields to change: name, amount, description, labels or labels.<key>; defaults to the fields present in the body", Schema: &Schema{Type: "string"}}
+	accountQuery        = Parameter{Name: "account", In: "query", Description: "only schedules where this account ID or name is the source or the target", Schema: &Schema{Type: "string"}}
+	deliveryStatusQuery = Parameter{Name: "status", In: "query", Description: "only deliveries in this state: pending, delivered or dead", Schema: &Schema{Type: "string"}}
+	actorHeader         = Parameter{Name: "X-Actor", In: "header", Description: "who performs the request; recorded as the author of the deletion", Schema: &Schema{Type: "string"}}
+)
+
+// statusChangeErrors — ответы с ошибкой у activate, freeze, unfreeze и close.
+var statusChangeErrors = []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}
+
+// holdCloseErrors — ответы с ошибкой у capture и release.
+var holdCloseErrors = []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}
+
+// scheduleChangeErrors — ответы с ошибкой у pause, resume и cancel.
+var scheduleChangeErrors = []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}
+
+// operations — все маршруты cmd/server. Проверка Verify следит, чтобы таблица совпадала с зарегистрированными маршрутами.
+var operations = []operation{
+	{method: "GET", path: "/v1/accounts", id: "listAccounts", summary: "List accounts", tag: "accounts",
+		params: []Parameter{includeDeletedQuery, labelSelectorQuery}, status: http.StatusOK, response: dto.ListAccountsResponse{}},
+	{method: "POST", path: "/v1/accounts", id: "createAccount", summary: "Create an account", tag: "accounts",
+		request: dto.CreateAccountRequest{}, status: http.StatusCreated, response: dto.GetAccountResponse{},
+		headers: map[string]string{"Location": "URL of the created account by its ID; it does not change on rename"},
+		errors:  []int{http.StatusBadRequest, http.StatusConflict}},
+	{method: "GET", path: "/v1/exports/accounts", id: "exportAccounts", summary: "Export all accounts at one point in time as NDJSON", tag: "accounts",
+		params: []Parameter{includeDeletedQuery, labelSelectorQuery}, status: http.StatusOK, contentType: "application/x-ndjson",
+		errors: []int{http.StatusBadRequest}},
+	{method: "GET", path: "/v1/accounts/:name", id: "getAccount", summary: "Get an account", tag: "accounts",
+		params: []Parameter{includeDeletedQuery, labelSelectorQuery}, status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "PATCH", path: "/v1/accounts/:name", id: "updateAccount", summary: "Change name, amount, description or labels of an account", tag: "accounts",
+		params: []Parameter{updateMaskQuery}, request: dto.UpdateAccountRequest{}, status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "DELETE", path: "/v1/accounts/:name", id: "deleteAccount", summary: "Delete an account; it can be restored until purged; rejected while holds are active", tag: "accounts",
+		params: []Parameter{actorHeader}, status: http.StatusNoContent, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "POST", path: "/v1/accounts/:name/restore", id: "restoreAccount", summary: "Restore a deleted account", tag: "accounts",
+		status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+	{method: "POST", path: "/v1/accounts/:name/activate", id: "activateAccount", summary: "Activate a pending account", tag: "accounts",
+		params: []Parameter{actorHeader}, request: dto.ChangeStatusRequest{}, status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: statusChangeErrors},
+	{method: "POST", path: "/v1/accounts/:name/freeze", id: "freezeAccount", summary: "Freeze an account: only reads and credits are allowed", tag: "accounts",
+		params: []Parameter{actorHeader}, request: dto.ChangeStatusRequest{}, status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: statusChangeErrors},
+	{method: "POST", path: "/v1/accounts/:name/unfreeze", id: "unfreezeAccount", summary: "Unfreeze a frozen account", tag: "accounts",
+		params: []Parameter{actorHeader}, request: dto.ChangeStatusRequest{}, status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: statusChangeErrors},
+	{method: "POST", path: "/v1/accounts/:name/close", id: "closeAccount", summary: "Close an account for good: only reads are allowed; rejected while holds are active", tag: "accounts",
+		params: []Parameter{actorHeader}, request: dto.ChangeStatusRequest{}, status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: statusChangeErrors},
+	{method: "GET", path: "/v1/accounts/:name/transitions", id: "accountTransitions", summary: "Status history of an account", tag: "accounts",
+		status: http.StatusOK, response: dto.StatusHistoryResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/v1/accounts/:name/renames", id: "accountRenames", summary: "Rename history of an account", tag: "accounts",
+		status: http.StatusOK, response: dto.RenameHistoryResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/v1/accounts/:name/ledger", id: "accountLedger", summary: "Ledger entries of an account, oldest first", tag: "interest",
+		status: http.StatusOK, response: dto.LedgerResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/v1/accounts/:name/holds", id: "authorizeHold", summary: "Reserve part of the available balance until captured, released or expired", tag: "holds",
+		request: dto.AuthorizeRequest{}, status: http.StatusCreated, response: dto.HoldResponse{},
+		headers: map[string]string{"Location": "URL of the created hold"},
+		errors:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}},
+	{method: "GET", path: "/v1/accounts/:name/holds", id: "listHolds", summary: "Holds of an account, including closed ones", tag: "holds",
+		status: http.StatusOK, response: dto.ListHoldsResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/v1/holds/:hold_id", id: "getHold", summary: "Get a hold", tag: "holds",
+		status: http.StatusOK, response: dto.HoldResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/v1/holds/:hold_id/capture", id: "captureHold", summary: "Debit the whole hold or a part of it; the rest becomes available again", tag: "holds",
+		request: dto.CaptureRequest{}, status: http.StatusOK, response: dto.CaptureResponse{},
+		errors: holdCloseErrors},
+	{method: "POST", path: "/v1/holds/:hold_id/release", id: "releaseHold", summary: "Release a hold without changing the balance", tag: "holds",
+		status: http.StatusOK, response: dto.HoldResponse{},
+		errors: holdCloseErrors},
+	{method: "POST", path: "/v1/schedules", id: "createSchedule", summary: "Schedule a one-off transfer at run_at or a recurring one by cron", tag: "schedules",
+		request: dto.CreateScheduleRequest{}, status: http.StatusCreated, response: dto.ScheduleResponse{},
+		headers: map[string]string{"Location": "URL of the created schedule"},
+		errors:  []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/v1/schedules", id: "listSchedules", summary: "List transfer schedules", tag: "schedules",
+		params: []Parameter{accountQuery}, status: http.StatusOK, response: dto.ListSchedulesResponse{},
+		errors: []int{http.StatusNotFound}},
+	{method: "GET", path: "/v1/schedules/:schedule_id", id: "getSchedule", summary: "Get a transfer schedule", tag: "schedules",
+		status: http.StatusOK, response: dto.ScheduleResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/v1/schedules/:schedule_id/pause", id: "pauseSchedule", summary: "Pause a schedule", tag: "schedules",
+		status: http.StatusOK, response: dto.ScheduleResponse{},
+		errors: scheduleChangeErrors},
+	{method: "POST", path: "/v1/schedules/:schedule_id/resume", id: "resumeSchedule", summary: "Resume a paused schedule; runs missed while paused are skipped", tag: "schedules",
+		status: http.StatusOK, response: dto.ScheduleResponse{},
+		errors: scheduleChangeErrors},
+	{method: "POST", path: "/v1/schedules/:schedule_id/cancel", id: "cancelSchedule", summary: "Cancel a schedule for good", tag: "schedules",
+		status: http.StatusOK, response: dto.ScheduleResponse{},
+		errors: scheduleChangeErrors},
+	{method: "GET", path: "/v1/schedules/:schedule_id/runs", id: "scheduleRuns", summary: "Runs of a schedule, oldest first", tag: "schedules",
+		status: http.StatusOK, response: dto.ScheduleRunsResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/v1/interest/accruals", id: "accrueInterest", summary: "Accrue interest for an ended period, or project the postings with dry_run", tag: "interest",
+		request: dto.AccrueInterestRequest{}, status: http.StatusOK, response: dto.AccrualResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity}},
+	{method: "GET", path: "/v1/interest/accruals", id: "listAccruals", summary: "Interest accrual batches, oldest period first", tag: "interest",
+		status: http.StatusOK, response: dto.ListAccrualsResponse{}},
+	{method: "GET", path: "/v1/interest/accruals/:accrual_id", id: "getAccrual", summary: "Get an interest accrual batch", tag: "interest",
+		status: http.StatusOK, response: dto.AccrualResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/v1/webhooks", id: "createWebhook", summary: "Subscribe a URL to account events; the signing secret is returned only here", tag: "webhooks",
+		request: dto.CreateWebhookRequest{}, status: http.StatusCreated, response: dto.WebhookResponse{},
+		headers: map[string]string{"Location": "URL of the created webhook"},
+		errors:  []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/v1/webhooks", id: "listWebhooks", summary: "List webhook subscriptions", tag: "webhooks",
+		status: http.StatusOK, response: dto.ListWebhooksResponse{}},
+	{method: "GET", path: "/v1/webhooks/:webhook_id", id: "getWebhook", summary: "Get a webhook subscription", tag: "webhooks",
+		status: http.StatusOK, response: dto.WebhookResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "DELETE", path: "/v1/webhooks/:webhook_id", id: "deleteWebhook", summary: "Delete a webhook subscription with its deliveries", tag: "webhooks",
+		status: http.StatusNoContent, errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/v1/webhooks/:webhook_id/deliveries", id: "listDeliveries", summary: "Deliveries of a webhook, oldest first; status=dead lists the dead-letter queue", tag: "webhooks",
+		params: []Parameter{deliveryStatusQuery}, status: http.StatusOK, response: dto.ListDeliveriesResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/v1/webhooks/:webhook_id/deliveries/:delivery_id/replay", id: "replayDelivery", summary: "Queue a delivery again with a full set of attempts", tag: "webhooks",
+		status: http.StatusAccepted, response: dto.DeliveryResponse{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+	{method: "GET", path: "/account", id: "legacyGetAccount", summary: "Get an account", tag: "legacy", deprecated: true,
+		params: []Parameter{nameQuery}, status: http.StatusOK, response: dto.GetAccountResponse{},
+		errors: []int{http.StatusNotFound}},
+	{method: "POST", path: "/account/create", id: "legacyCreateAccount", summary: "Create an account", tag: "legacy", deprecated: true,
+		request: dto.CreateAccountRequest{}, status: http.StatusCreated,
+		errors: []int{http.StatusBadRequest, http.StatusConflict}},
+	{method: "POST", path: "/account/delete", id: "legacyDeleteAccount", summary: "Delete an account", tag: "legacy", deprecated: true,
+		params: []Parameter{actorHeader}, request: dto.DeleteAccountRequest{}, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "POST", path: "/account/change_amount", id: "legacyChangeAmount", summary: "Change amount of an account", tag: "legacy", deprecated: true,
+		request: dto.PatchAccountRequest{}, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "POST", path: "/account/change_name", id: "legacyChangeName", summary: "Rename an account", tag: "legacy", deprecated: true,
+		request: dto.ChangeAccountRequest{}, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+
+	{method: "GET", path: "/gateway/account", id: "gatewayList", summary: "Account.List", tag: "gateway",
+		params: []Parameter{includeDeletedQuery, labelSelectorQuery}, status: http.StatusOK, response: &proto.ListAccountsReply{},
+		errors: []int{http.StatusBadRequest}},
+	{method: "GET", path: "/gateway/account/:name", id: "gatewayGet", summary: "Account.Get", tag: "gateway",
+		params: []Parameter{includeDeletedQuery, labelSelectorQuery}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/gateway/account", id: "gatewayCreate", summary: "Account.Create", tag: "gateway",
+		request: &proto.CreateAccountRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: []int{http.StatusBadRequest, http.StatusConflict}},
+	{method: "PUT", path: "/gateway/account/:name/amount", id: "gatewayChangeAmount", summary: "Account.ChangeAmount", tag: "gateway",
+		request: &proto.PatchAccountRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "PUT", path: "/gateway/account/:name/name", id: "gatewayChangeName", summary: "Account.ChangeName", tag: "gateway",
+		request: &proto.ChangeAccountRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "PATCH", path: "/gateway/account/:name", id: "gatewayUpdate", summary: "Account.Update", tag: "gateway",
+		params:  []Parameter{{Name: "update_mask", In: "query", Description: "comma-separated fields to change, required here or in the body", Schema: &Schema{Type: "string"}}},
+		request: &proto.UpdateAccountRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "DELETE", path: "/gateway/account/:name", id: "gatewayDelete", summary: "Account.Delete", tag: "gateway",
+		params: []Parameter{actorHeader}, status: http.StatusOK, response: &proto.Empty{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}},
+	{method: "POST", path: "/gateway/account/:name/restore", id: "gatewayRestore", summary: "Account.Restore", tag: "gateway",
+		status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+	{method: "POST", path: "/gateway/account/:name/activate", id: "gatewayActivate", summary: "Account.Activate", tag: "gateway",
+		params: []Parameter{actorHeader}, request: &proto.ChangeStatusRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: statusChangeErrors},
+	{method: "POST", path: "/gateway/account/:name/freeze", id: "gatewayFreeze", summary: "Account.Freeze", tag: "gateway",
+		params: []Parameter{actorHeader}, request: &proto.ChangeStatusRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: statusChangeErrors},
+	{method: "POST", path: "/gateway/account/:name/unfreeze", id: "gatewayUnfreeze", summary: "Account.Unfreeze", tag: "gateway",
+		params: []Parameter{actorHeader}, request: &proto.ChangeStatusRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: statusChangeErrors},
+	{method: "POST", path: "/gateway/account/:name/close", id: "gatewayClose", summary: "Account.Close", tag: "gateway",
+		params: []Parameter{actorHeader}, request: &proto.ChangeStatusRequest{}, status: http.StatusOK, response: &proto.GetAccountReply{},
+		errors: statusChangeErrors},
+	{method: "GET", path: "/gateway/account/:name/transitions", id: "gatewayStatusHistory", summary: "Account.StatusHistory", tag: "gateway",
+		status: http.StatusOK, response: &proto.StatusHistoryReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/gateway/account/:name/renames", id: "gatewayRenameHistory", summary: "Account.RenameHistory", tag: "gateway",
+		status: http.StatusOK, response: &proto.RenameHistoryReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/gateway/account/:name/holds", id: "gatewayAuthorize", summary: "Account.Authorize", tag: "gateway",
+		request: &proto.AuthorizeRequest{}, status: http.StatusOK, response: &proto.Hold{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}},
+	{method: "GET", path: "/gateway/account/:name/holds", id: "gatewayListHolds", summary: "Account.ListHolds", tag: "gateway",
+		status: http.StatusOK, response: &proto.ListHoldsReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/gateway/hold/:hold_id", id: "gatewayGetHold", summary: "Account.GetHold", tag: "gateway",
+		status: http.StatusOK, response: &proto.Hold{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/gateway/hold/:hold_id/capture", id: "gatewayCapture", summary: "Account.Capture", tag: "gateway",
+		request: &proto.CaptureRequest{}, status: http.StatusOK, response: &proto.CaptureReply{},
+		errors: holdCloseErrors},
+	{method: "POST", path: "/gateway/hold/:hold_id/release", id: "gatewayRelease", summary: "Account.Release", tag: "gateway",
+		status: http.StatusOK, response: &proto.Hold{},
+		errors: holdCloseErrors},
+	{method: "POST", path: "/gateway/schedule", id: "gatewayCreateSchedule", summary: "Account.CreateSchedule", tag: "gateway",
+		request: &proto.CreateScheduleRequest{}, status: http.StatusOK, response: &proto.Schedule{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/gateway/schedule", id: "gatewayListSchedules", summary: "Account.ListSchedules", tag: "gateway",
+		params: []Parameter{accountQuery}, status: http.StatusOK, response: &proto.ListSchedulesReply{},
+		errors: []int{http.StatusNotFound}},
+	{method: "GET", path: "/gateway/schedule/:schedule_id", id: "gatewayGetSchedule", summary: "Account.GetSchedule", tag: "gateway",
+		status: http.StatusOK, response: &proto.Schedule{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/gateway/schedule/:schedule_id/pause", id: "gatewayPauseSchedule", summary: "Account.PauseSchedule", tag: "gateway",
+		status: http.StatusOK, response: &proto.Schedule{},
+		errors: scheduleChangeErrors},
+	{method: "POST", path: "/gateway/schedule/:schedule_id/resume", id: "gatewayResumeSchedule", summary: "Account.ResumeSchedule", tag: "gateway",
+		status: http.StatusOK, response: &proto.Schedule{},
+		errors: scheduleChangeErrors},
+	{method: "POST", path: "/gateway/schedule/:schedule_id/cancel", id: "gatewayCancelSchedule", summary: "Account.CancelSchedule", tag: "gateway",
+		status: http.StatusOK, response: &proto.Schedule{},
+		errors: scheduleChangeErrors},
+	{method: "GET", path: "/gateway/schedule/:schedule_id/runs", id: "gatewayScheduleRuns", summary: "Account.ScheduleRuns", tag: "gateway",
+		status: http.StatusOK, response: &proto.ScheduleRunsReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/gateway/account/:name/ledger", id: "gatewayLedger", summary: "Account.Ledger", tag: "gateway",
+		status: http.StatusOK, response: &proto.LedgerReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/gateway/accrual", id: "gatewayAccrueInterest", summary: "Account.AccrueInterest", tag: "gateway",
+		request: &proto.AccrueInterestRequest{}, status: http.StatusOK, response: &proto.Accrual{},
+		errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity}},
+	{method: "GET", path: "/gateway/accrual", id: "gatewayListAccruals", summary: "Account.ListAccruals", tag: "gateway",
+		status: http.StatusOK, response: &proto.ListAccrualsReply{}},
+	{method: "GET", path: "/gateway/accrual/:accrual_id", id: "gatewayGetAccrual", summary: "Account.GetAccrual", tag: "gateway",
+		status: http.StatusOK, response: &proto.Accrual{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/gateway/webhook", id: "gatewayCreateWebhook", summary: "Account.CreateWebhook", tag: "gateway",
+		request: &proto.CreateWebhookRequest{}, status: http.StatusOK, response: &proto.Webhook{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/gateway/webhook", id: "gatewayListWebhooks", summary: "Account.ListWebhooks", tag: "gateway",
+		status: http.StatusOK, response: &proto.ListWebhooksReply{}},
+	{method: "GET", path: "/gateway/webhook/:webhook_id", id: "gatewayGetWebhook", summary: "Account.GetWebhook", tag: "gateway",
+		status: http.StatusOK, response: &proto.Webhook{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "DELETE", path: "/gateway/webhook/:webhook_id", id: "gatewayDeleteWebhook", summary: "Account.DeleteWebhook", tag: "gateway",
+		status: http.StatusOK, response: &proto.Empty{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "GET", path: "/gateway/webhook/:webhook_id/delivery", id: "gatewayListDeliveries", summary: "Account.ListDeliveries", tag: "gateway",
+		params: []Parameter{deliveryStatusQuery}, status: http.StatusOK, response: &proto.ListDeliveriesReply{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: "POST", path: "/gateway/webhook/:webhook_id/delivery/:delivery_id/replay", id: "gatewayReplayDelivery", summary: "Account.ReplayDelivery", tag: "gateway",
+		status: http.StatusOK, response: &proto.Delivery{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+	{method: "GET", path: "/openapi.json", id: "openapi", summary: "This OpenAPI document", tag: "docs",
+		status: http.StatusOK, contentType: "application/json"},
+	{method: "GET", path: "/docs", id: "docs", summary: "Interactive API explorer", tag: "docs",
+		status: http.StatusOK, contentType: "text/html"},
+}
+
+// Build собирает OpenAPI документ по таблице operations.
+func Build() *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Accounts API",
+			Version:     "1.0.0",
+			Description: "REST API of the accounts server. Errors are returned as Problem objects with a stable code.",
+		},
+		Paths: map[string]*PathItem{},
+	}
+
+	s := schemas{}
+	problem := s.of(errs.Problem{})
+	s["Problem"].Properties["code"].Enum = codes()
+
+	for _, op := range operations {
+		path, params := openAPIPath(op.path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+
+		o := &Operation{
+			OperationID: op.id,
+			Summary:     op.summary,
+			Tags:        []string{op.tag},
+			Deprecated:  op.deprecated,
+			Parameters:  append(params, op.params...),
+			Responses:   map[string]*Response{},
+		}
+		if op.request != nil {
+			o.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: s.of(op.request)}},
+			}
+		}
+
+		success := &Response{Description: http.StatusText(op.status)}
+		switch {
+		case op.response != nil:
+			success.Content = map[string]*MediaType{"application/json": {Schema: s.of(op.response)}}
+		case op.contentType == "application/json":
+			success.Content = map[string]*MediaType{op.contentType: {Schema: &Schema{Type: "object"}}}
+		case op.contentType != "":
+			success.Content = map[string]*MediaType{op.contentType: {Schema: &Schema{Type: "string"}}}
+		}
+		if len(op.headers) > 0 || op.deprecated {
+			success.Headers = map[string]*Header{}
+		}
+		for name, description := range op.headers {
+			success.Headers[name] = &Header{Description: description, Schema: &Schema{Type: "string"}}
+		}
+		if op.deprecated {
+			success.Headers["Deprecation"] = &Header{Description: "Date the route was deprecated (RFC 9745)", Schema: &Schema{Type: "string"}}
+			success.Headers["Sunset"] = &Header{Description: "Date the route will be removed (RFC 8594)", Schema: &Schema{Type: "string"}}
+		}
+		o.Responses[strconv.Itoa(op.status)] = success
+
+		for _, status := range op.errors {
+			o.Responses[strconv.Itoa(status)] = &Response{
+				Description: http.StatusText(status),
+				Content:     map[string]*MediaType{"application/json": {Schema: problem}},
+			}
+		}
+		o.Responses["default"] = &Response{
+			Description: "Unexpected error",
+			Content:     map[string]*MediaType{"application/json": {Schema: problem}},
+		}
+
+		*item.operation(op.method) = o
+	}
+
+	doc.Components.Schemas = s
+
+	return doc
+}
+
+// openAPIPath переводит путь echo (/v1/accounts/:name) в шаблон OpenAPI (/v1/accounts/{name}).
+// Параметр :name во всех маршрутах — ссылка на аккаунт: его ID или имя; остальные параметры пути описаны в pathParams.
+func openAPIPath(path string) (string, []Parameter) {
+	var params []Parameter
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Description: pathParams[name], Schema: &Schema{Type: "string"}})
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// pathParams — описания параметров пути.
+var pathParams = map[string]string{
+	"name":        "account ID or name",
+	"hold_id":     "hold ID",
+	"schedule_id": "schedule ID",
+	"accrual_id":  "accrual ID: daily-YYYY-MM-DD or monthly-YYYY-MM",
+	"webhook_id":  "webhook subscription ID",
+	"delivery_id": "delivery ID",
+}
+
+func codes() []string {
+	values := make([]string, 0, len(errs.Codes))
+	for _, code := range errs.Codes {
+		values = append(values, string(code))
+	}
+
+	return values
+}
+
+// Route — маршрут, зарегистрированный в сервере.
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Verify сравнивает маршруты сервера с документом и перечисляет все расхождения.
+func Verify(doc *Document, routes []Route) error {
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+			if *item.operation(method) != nil {
+				documented[method+" "+path] = true
+			}
+		}
+	}
+
+	registered := map[string]bool{}
+	for _, route := range routes {
+		if item := (&PathItem{}).operation(route.Method); item == nil {
+			// служебные маршруты echo, например echo_route_not_found
+			continue
+		}
+		path, _ := openAPIPath(route.Path)
+		registered[route.Method+" "+path] = true
+	}
+
+	var problems []string
+	for route := range registered {
+		if !documented[route] {
+			problems = append(problems, "route "+route+" is not described in the OpenAPI document")
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			problems = append(problems, "operation "+route+" is described but not registered")
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+
+	return fmt.Errorf("openapi spec drift:\n  %s", strings.Join(problems, "\n  "))
+}
@@ -0,0 +1,25 @@
+package openapi_test
+
+import (
+	"awesomeProject/accounts"
+	"awesomeProject/accounts/gateway"
+	"awesomeProject/accounts/openapi"
+	"awesomeProject/accounts/rpc"
+	"awesomeProject/accounts/storage"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestRoutesMatchDocument падает, когда маршруты сервера и OpenAPI документ расходятся.
+func TestRoutesMatchDocument(t *testing.T) {
+	store := storage.NewMemory()
+	e := echo.New()
+	accounts.New(store).Register(e)
+	gateway.Register(e, rpc.New(store))
+	openapi.Register(e, openapi.Build())
+
+	if err := openapi.Verify(openapi.Build(), openapi.Routes(e)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,77 @@
+package accounts
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// legacyDeprecatedAt — дата, с которой RPC-маршруты /account/* объявлены устаревшими.
+	legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	// legacySunsetAt — дата, после которой RPC-маршруты /account/* будут удалены.
+	legacySunsetAt = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// Register регистрирует API /v1/accounts, /v1/exports/accounts, /v1/holds, /v1/schedules, /v1/interest, /v1/webhooks и устаревшие маршруты /account/*.
+func (h *Handler) Register(e *echo.Echo) {
+	v1 := e.Group("/v1")
+	v1.GET("/accounts", h.ListAccounts)
+	v1.POST("/accounts", h.CreateAccount)
+	v1.GET("/accounts/:name", h.GetAccount)
+	v1.PATCH("/accounts/:name", h.UpdateAccount)
+	v1.DELETE("/accounts/:name", h.DeleteAccount)
+	v1.POST("/accounts/:name/restore", h.RestoreAccount)
+	v1.POST("/accounts/:name/activate", h.ActivateAccount)
+	v1.POST("/accounts/:name/freeze", h.FreezeAccount)
+	v1.POST("/accounts/:name/unfreeze", h.UnfreezeAccount)
+	v1.POST("/accounts/:name/close", h.CloseAccount)
+	v1.GET("/accounts/:name/transitions", h.StatusHistory)
+	v1.GET("/accounts/:name/renames", h.RenameHistory)
+	v1.GET("/accounts/:name/ledger", h.Ledger)
+	v1.POST("/accounts/:name/holds", h.Authorize)
+	v1.GET("/accounts/:name/holds", h.ListHolds)
+	// Выгрузка вне /accounts/:name, чтобы не отнимать у аккаунтов ни одного имени.
+	v1.GET("/exports/accounts", h.ExportAccounts)
+	v1.GET("/holds/:hold_id", h.GetHold)
+	v1.POST("/holds/:hold_id/capture", h.CaptureHold)
+	v1.POST("/holds/:hold_id/release", h.ReleaseHold)
+	v1.POST("/schedules", h.CreateSchedule)
+	v1.GET("/schedules", h.ListSchedules)
+	v1.GET("/schedules/:schedule_id", h.GetSchedule)
+	v1.POST("/schedules/:schedule_id/pause", h.PauseSchedule)
+	v1.POST("/schedules/:schedule_id/resume", h.ResumeSchedule)
+	v1.POST("/schedules/:schedule_id/cancel", h.CancelSchedule)
+	v1.GET("/schedules/:schedule_id/runs", h.ScheduleRuns)
+	v1.POST("/interest/accruals", h.AccrueInterest)
+	v1.GET("/interest/accruals", h.ListAccruals)
+	v1.GET("/interest/accruals/:accrual_id", h.GetAccrual)
+	v1.POST("/webhooks", h.CreateWebhook)
+	v1.GET("/webhooks", h.ListWebhooks)
+	v1.GET("/webhooks/:webhook_id", h.GetWebhook)
+	v1.DELETE("/webhooks/:webhook_id", h.DeleteWebhook)
+	v1.GET("/webhooks/:webhook_id/deliveries", h.ListDeliveries)
+	v1.POST("/webhooks/:webhook_id/deliveries/:delivery_id/replay", h.ReplayDelivery)
+
+	legacy := e.Group("/account", deprecated("/v1/accounts"))
+	legacy.GET("", h.LegacyGetAccount)
+	legacy.POST("/create", h.LegacyCreateAccount)
+	legacy.POST("/delete", h.LegacyDeleteAccount)
+	legacy.POST("/change_amount", h.LegacyPatchAccount)
+	legacy.POST("/change_name", h.LegacyChangeAccount)
+}
+
+// deprecated добавляет заголовки Deprecation (RFC 9745), Sunset (RFC 8594) и ссылку на замену.
+func deprecated(successor string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set("Deprecation", "@"+strconv.FormatInt(legacyDeprecatedAt.Unix(), 10))
+			header.Set("Sunset", legacySunsetAt.Format(http.TimeFormat))
+			header.Add("Link", "<"+successor+">; rel=\"successor-version\"")
+
+			return next(c)
+		}
+	}
+}
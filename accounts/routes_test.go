@@ -0,0 +1,134 @@
+package accounts
+
+import (
+	"awesomeProject/accounts/storage"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// newTestServer регистрирует обработчик поверх хранилища в памяти так же, как cmd/server.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	New(storage.NewMemory()).Register(e)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// call отправляет запрос с JSON телом payload, если оно не пустое, и возвращает ответ с прочитанным телом.
+func call(t *testing.T, server *httptest.Server, method, path, payload string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp, string(body)
+}
+
+func TestLegacyDeprecationHeaders(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, path := range []string{"/account?name=alice", "/account/create"} {
+		method := http.MethodGet
+		if path == "/account/create" {
+			method = http.MethodPost
+		}
+		// Заголовки ставятся и на ответы с ошибкой.
+		resp, _ := call(t, server, method, path, `{}`)
+		if got := resp.Header.Get("Deprecation"); got != "@1792368000" {
+			t.Errorf("%s Deprecation = %q, want @1792368000", path, got)
+		}
+		if got := resp.Header.Get("Sunset"); got != "Mon, 19 Apr 2027 00:00:00 GMT" {
+			t.Errorf("%s Sunset = %q", path, got)
+		}
+		if got := resp.Header.Get("Link"); got != `</v1/accounts>; rel="successor-version"` {
+			t.Errorf("%s Link = %q", path, got)
+		}
+	}
+
+	resp, _ := call(t, server, http.MethodGet, "/v1/accounts", "")
+	for _, header := range []string{"Deprecation", "Sunset", "Link"} {
+		if got := resp.Header.Get(header); got != "" {
+			t.Errorf("/v1/accounts has %s: %q", header, got)
+		}
+	}
+}
+
+// TestLegacyRoutes проходит по маршрутам /account/* с теми же статусами и телами, что были до /v1.
+func TestLegacyRoutes(t *testing.T) {
+	server := newTestServer(t)
+	get := func(name string) (int, map[string]any) {
+		t.Helper()
+		resp, body := call(t, server, http.MethodGet, "/account?"+url.Values{"name": {name}}.Encode(), "")
+		var account map[string]any
+		if resp.StatusCode == http.StatusOK {
+			if err := json.Unmarshal([]byte(body), &account); err != nil {
+				t.Fatalf("get %s: %v in %s", name, err, body)
+			}
+		}
+		return resp.StatusCode, account
+	}
+	steps := []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{path: "/account/create", body: `{"name":"alice","amount":50}`, status: http.StatusCreated},
+		{path: "/account/create", body: `{"name":"alice","amount":50}`, status: http.StatusConflict},
+		{path: "/account/create", body: `{"name":"","amount":50}`, status: http.StatusBadRequest},
+		{path: "/account/change_amount", body: `{"name":"alice","amount":70}`, status: http.StatusOK},
+		{path: "/account/change_amount", body: `{"name":"nobody","amount":70}`, status: http.StatusNotFound},
+		{path: "/account/change_name", body: `{"name":"alice","new_name":"bob"}`, status: http.StatusOK},
+		{path: "/account/change_name", body: `{"name":"bob","new_name":""}`, status: http.StatusBadRequest},
+	}
+	for _, step := range steps {
+		resp, body := call(t, server, http.MethodPost, step.path, step.body)
+		if resp.StatusCode != step.status {
+			t.Fatalf("POST %s %s = %d %s, want %d", step.path, step.body, resp.StatusCode, body, step.status)
+		}
+		if step.status < 300 && body != "" {
+			t.Fatalf("POST %s answered %q, want no body", step.path, body)
+		}
+	}
+
+	status, account := get("bob")
+	if status != http.StatusOK || account["name"] != "bob" || account["amount"] != float64(70) {
+		t.Fatalf("get bob = %d %v, want bob with 70", status, account)
+	}
+	if status, _ := get("alice"); status != http.StatusNotFound {
+		t.Fatalf("get alice after rename = %d, want 404", status)
+	}
+
+	if resp, body := call(t, server, http.MethodPost, "/account/delete", `{"name":"bob"}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete = %d %s", resp.StatusCode, body)
+	}
+	if status, _ := get("bob"); status != http.StatusNotFound {
+		t.Fatalf("get after delete = %d, want 404", status)
+	}
+	if resp, _ := call(t, server, http.MethodPost, "/account/delete", `{"name":"bob"}`); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("second delete = %d, want 404", resp.StatusCode)
+	}
+}
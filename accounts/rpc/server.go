@@ -0,0 +1,838 @@
+package rpc
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/interest"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"awesomeProject/accounts/validation"
+	"awesomeProject/proto"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func New(storage storage.Storage, opts ...Option) *Server {
+	s := &Server{
+		storage: storage,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Server реализует gRPC сервис Account поверх общего хранилища.
+// Методы возвращают ошибки из каталога errs, в статусы их переводит errs.UnaryServerInterceptor.
+type Server struct {
+	proto.UnimplementedAccountServer
+	storage storage.Storage
+	// renameAliases — сколько Get находит аккаунт по старому имени; 0 — не находит.
+	renameAliases time.Duration
+	// interest начисляет проценты; nil — начисление не настроено.
+	interest *interest.Engine
+}
+
+type Option func(s *Server)
+
+// WithRenameAliases разрешает Get находить аккаунт по имени, которое он носил до переименования
+// не раньше ttl назад; ответ сообщает об этом в redirected_from.
+func WithRenameAliases(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.renameAliases = ttl
+	}
+}
+
+// WithInterest включает AccrueInterest.
+func WithInterest(engine *interest.Engine) Option {
+	return func(s *Server) {
+		s.interest = engine
+	}
+}
+
+func (s *Server) Get(ctx context.Context, req *proto.GetAccountRequest) (*proto.GetAccountReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	opts := readOptions(v, req.GetIncludeDeleted(), req.GetLabelSelector())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if s.renameAliases > 0 {
+		opts = append(opts, storage.FollowRenames(s.renameAliases))
+	}
+
+	account, err := s.storage.Get(ctx, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := accountReply(account)
+	if !account.RefersTo(name) {
+		reply.RedirectedFrom = name
+	}
+
+	return reply, nil
+}
+
+func (s *Server) List(ctx context.Context, req *proto.ListAccountsRequest) (*proto.ListAccountsReply, error) {
+	v := validation.New()
+	opts := readOptions(v, req.GetIncludeDeleted(), req.GetLabelSelector())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.storage.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListAccountsReply{Accounts: make([]*proto.GetAccountReply, 0, len(accounts))}
+	for _, account := range accounts {
+		reply.Accounts = append(reply.Accounts, accountReply(account))
+	}
+
+	return reply, nil
+}
+
+func (s *Server) Create(ctx context.Context, req *proto.CreateAccountRequest) (*proto.GetAccountReply, error) {
+	v := validation.New()
+	name := v.Name("name", req.GetName())
+	v.Amount("amount", int(req.GetAmount()))
+	status := v.InitialStatus("status", req.GetStatus())
+	description := v.Description("description", req.GetDescription())
+	labels := v.Labels("labels", req.GetLabels())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.storage.Create(ctx, models.Account{
+		Name:        name,
+		Amount:      int(req.GetAmount()),
+		Status:      status,
+		Description: description,
+		Labels:      labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accountReply(account), nil
+}
+
+func (s *Server) ChangeAmount(ctx context.Context, req *proto.PatchAccountRequest) (*proto.GetAccountReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	v.Amount("amount", int(req.GetAmount()))
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.storage.ChangeAmount(ctx, name, int(req.GetAmount()))
+	if err != nil {
+		return nil, err
+	}
+
+	return accountReply(account), nil
+}
+
+func (s *Server) ChangeName(ctx context.Context, req *proto.ChangeAccountRequest) (*proto.GetAccountReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	newName := v.Name("new_name", req.GetNewName())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.storage.ChangeName(ctx, name, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountReply(account), nil
+}
+
+// Update требует update_mask: в proto3 нельзя отличить непереданное поле от пустого.
+// Путь name меняет имя на new_name.
+func (s *Server) Update(ctx context.Context, req *proto.UpdateAccountRequest) (*proto.GetAccountReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	mask := req.GetUpdateMask().GetPaths()
+	validation.Check(v, "update_mask", strings.Join(mask, ","), validation.Required())
+	newName, amount, description := req.GetNewName(), int(req.GetAmount()), req.GetDescription()
+	update := v.Update("update_mask", mask, validation.UpdateFields{
+		Name:        &newName,
+		Amount:      &amount,
+		Description: &description,
+		Labels:      req.GetLabels(),
+	})
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account, err := storage.Update(ctx, s.storage, name, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountReply(account), nil
+}
+
+func (s *Server) Delete(ctx context.Context, req *proto.DeleteAccountRequest) (*proto.Empty, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Delete(ctx, name, actorFrom(ctx)); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (s *Server) Restore(ctx context.Context, req *proto.RestoreAccountRequest) (*proto.GetAccountReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.storage.Restore(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountReply(account), nil
+}
+
+func (s *Server) Activate(ctx context.Context, req *proto.ChangeStatusRequest) (*proto.GetAccountReply, error) {
+	return s.changeStatus(ctx, req, models.Activate)
+}
+
+func (s *Server) Freeze(ctx context.Context, req *proto.ChangeStatusRequest) (*proto.GetAccountReply, error) {
+	return s.changeStatus(ctx, req, models.Freeze)
+}
+
+func (s *Server) Unfreeze(ctx context.Context, req *proto.ChangeStatusRequest) (*proto.GetAccountReply, error) {
+	return s.changeStatus(ctx, req, models.Unfreeze)
+}
+
+func (s *Server) Close(ctx context.Context, req *proto.ChangeStatusRequest) (*proto.GetAccountReply, error) {
+	return s.changeStatus(ctx, req, models.Close)
+}
+
+func (s *Server) changeStatus(ctx context.Context, req *proto.ChangeStatusRequest, change models.Change) (*proto.GetAccountReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	reason := v.Reason("reason", req.GetReason())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.storage.SetStatus(ctx, name, change, reason, actorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return accountReply(account), nil
+}
+
+func (s *Server) StatusHistory(ctx context.Context, req *proto.StatusHistoryRequest) (*proto.StatusHistoryReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	history, err := s.storage.StatusHistory(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.StatusHistoryReply{Transitions: make([]*proto.Transition, 0, len(history))}
+	for _, t := range history {
+		reply.Transitions = append(reply.Transitions, &proto.Transition{
+			From:   string(t.From),
+			To:     string(t.To),
+			Reason: t.Reason,
+			Actor:  t.Actor,
+			At:     timestamppb.New(t.At),
+		})
+	}
+
+	return reply, nil
+}
+
+func (s *Server) RenameHistory(ctx context.Context, req *proto.RenameHistoryRequest) (*proto.RenameHistoryReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	history, err := s.storage.RenameHistory(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.RenameHistoryReply{Renames: make([]*proto.Rename, 0, len(history))}
+	for _, r := range history {
+		reply.Renames = append(reply.Renames, &proto.Rename{
+			From: r.From,
+			To:   r.To,
+			At:   timestamppb.New(r.At),
+		})
+	}
+
+	return reply, nil
+}
+
+// ActorMetadata — ключ метаданных с именем того, кто выполняет запрос.
+const ActorMetadata = "x-actor"
+
+func (s *Server) Authorize(ctx context.Context, req *proto.AuthorizeRequest) (*proto.Hold, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	amount := int(req.GetAmount())
+	validation.Check(v, "amount", amount, validation.HoldAmount...)
+	ttl := v.HoldTTL("ttl_seconds", int(req.GetTtlSeconds()))
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hold, err := s.storage.Authorize(ctx, name, amount, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return holdReply(hold), nil
+}
+
+func (s *Server) Capture(ctx context.Context, req *proto.CaptureRequest) (*proto.CaptureReply, error) {
+	v := validation.New()
+	validation.Check(v, "hold_id", req.GetHoldId(), validation.Required())
+	amount := int(req.GetAmount())
+	if amount != 0 {
+		validation.Check(v, "amount", amount, validation.HoldAmount...)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hold, account, err := s.storage.Capture(ctx, req.GetHoldId(), amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CaptureReply{Hold: holdReply(hold), Account: accountReply(account)}, nil
+}
+
+func (s *Server) Release(ctx context.Context, req *proto.ReleaseRequest) (*proto.Hold, error) {
+	v := validation.New()
+	validation.Check(v, "hold_id", req.GetHoldId(), validation.Required())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hold, err := s.storage.Release(ctx, req.GetHoldId())
+	if err != nil {
+		return nil, err
+	}
+
+	return holdReply(hold), nil
+}
+
+func (s *Server) GetHold(ctx context.Context, req *proto.GetHoldRequest) (*proto.Hold, error) {
+	v := validation.New()
+	validation.Check(v, "hold_id", req.GetHoldId(), validation.Required())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hold, err := s.storage.Hold(ctx, req.GetHoldId())
+	if err != nil {
+		return nil, err
+	}
+
+	return holdReply(hold), nil
+}
+
+func (s *Server) ListHolds(ctx context.Context, req *proto.ListHoldsRequest) (*proto.ListHoldsReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	holds, err := s.storage.Holds(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListHoldsReply{Holds: make([]*proto.Hold, 0, len(holds))}
+	for _, hold := range holds {
+		reply.Holds = append(reply.Holds, holdReply(hold))
+	}
+
+	return reply, nil
+}
+
+func (s *Server) CreateSchedule(ctx context.Context, req *proto.CreateScheduleRequest) (*proto.Schedule, error) {
+	v := validation.New()
+	schedule := models.Schedule{
+		From:        v.Lookup("from", req.GetFrom()),
+		To:          v.Lookup("to", req.GetTo()),
+		Amount:      int(req.GetAmount()),
+		Cron:        v.Cron("cron", req.GetCron()),
+		Description: v.Description("description", req.GetDescription()),
+	}
+	validation.Check(v, "amount", schedule.Amount, validation.TransferAmount...)
+	var runAt *time.Time
+	if req.GetRunAt() != nil {
+		t := req.GetRunAt().AsTime()
+		runAt = &t
+	}
+	schedule.NextRunAt = v.RunAt("run_at", runAt, schedule.Recurring())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.storage.CreateSchedule(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduleReply(schedule), nil
+}
+
+func (s *Server) GetSchedule(ctx context.Context, req *proto.ScheduleRequest) (*proto.Schedule, error) {
+	id, err := scheduleID(req)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.storage.Schedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduleReply(schedule), nil
+}
+
+func (s *Server) ListSchedules(ctx context.Context, req *proto.ListSchedulesRequest) (*proto.ListSchedulesReply, error) {
+	schedules, err := s.storage.Schedules(ctx, validation.NormalizeName(req.GetAccount()))
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListSchedulesReply{Schedules: make([]*proto.Schedule, 0, len(schedules))}
+	for _, schedule := range schedules {
+		reply.Schedules = append(reply.Schedules, scheduleReply(schedule))
+	}
+
+	return reply, nil
+}
+
+func (s *Server) PauseSchedule(ctx context.Context, req *proto.ScheduleRequest) (*proto.Schedule, error) {
+	return s.changeSchedule(ctx, req, models.SchedulePause)
+}
+
+func (s *Server) ResumeSchedule(ctx context.Context, req *proto.ScheduleRequest) (*proto.Schedule, error) {
+	return s.changeSchedule(ctx, req, models.ScheduleResume)
+}
+
+func (s *Server) CancelSchedule(ctx context.Context, req *proto.ScheduleRequest) (*proto.Schedule, error) {
+	return s.changeSchedule(ctx, req, models.ScheduleCancel)
+}
+
+func (s *Server) changeSchedule(ctx context.Context, req *proto.ScheduleRequest, action models.ScheduleAction) (*proto.Schedule, error) {
+	id, err := scheduleID(req)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.storage.ChangeSchedule(ctx, id, action, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduleReply(schedule), nil
+}
+
+func (s *Server) ScheduleRuns(ctx context.Context, req *proto.ScheduleRequest) (*proto.ScheduleRunsReply, error) {
+	id, err := scheduleID(req)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.storage.ScheduleRuns(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ScheduleRunsReply{Runs: make([]*proto.ScheduleRun, 0, len(runs))}
+	for _, run := range runs {
+		reply.Runs = append(reply.Runs, &proto.ScheduleRun{
+			DueAt:  timestamppb.New(run.DueAt),
+			Status: string(run.Status),
+			Error:  run.Error,
+			RanAt:  timestamppb.New(run.RanAt),
+		})
+	}
+
+	return reply, nil
+}
+
+func scheduleID(req *proto.ScheduleRequest) (string, error) {
+	v := validation.New()
+	validation.Check(v, "schedule_id", req.GetScheduleId(), validation.Required())
+
+	return req.GetScheduleId(), v.Err()
+}
+
+func actorFrom(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(ActorMetadata); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+
+	return models.UnknownActor
+}
+
+// readOptions собирает опции чтения; ошибки селектора попадают в v.
+func readOptions(v *validation.Validator, includeDeleted bool, selector string) []storage.ReadOption {
+	var opts []storage.ReadOption
+	if includeDeleted {
+		opts = append(opts, storage.IncludeDeleted())
+	}
+	if selector != "" {
+		opts = append(opts, storage.MatchLabels(v.Selector("label_selector", selector)))
+	}
+
+	return opts
+}
+
+func accountReply(account models.Account) *proto.GetAccountReply {
+	reply := &proto.GetAccountReply{
+		Id:          account.ID,
+		Name:        account.Name,
+		Amount:      int32(account.Amount),
+		Held:        int32(account.Held),
+		Available:   int32(account.Available()),
+		Status:      string(account.Status),
+		Description: account.Description,
+		Labels:      account.Labels,
+		CreatedAt:   timestamppb.New(account.CreatedAt),
+		UpdatedAt:   timestamppb.New(account.UpdatedAt),
+	}
+	if account.Deleted() {
+		reply.DeletedAt = timestamppb.New(account.DeletedAt)
+		reply.DeletedBy = account.DeletedBy
+	}
+
+	return reply
+}
+
+func holdReply(hold models.Hold) *proto.Hold {
+	reply := &proto.Hold{
+		Id:        hold.ID,
+		AccountId: hold.AccountID,
+		Amount:    int32(hold.Amount),
+		Status:    string(hold.Status),
+		Captured:  int32(hold.Captured),
+		CreatedAt: timestamppb.New(hold.CreatedAt),
+		ExpiresAt: timestamppb.New(hold.ExpiresAt),
+	}
+	if !hold.ClosedAt.IsZero() {
+		reply.ClosedAt = timestamppb.New(hold.ClosedAt)
+	}
+
+	return reply
+}
+
+func scheduleReply(schedule models.Schedule) *proto.Schedule {
+	reply := &proto.Schedule{
+		Id:          schedule.ID,
+		From:        schedule.From,
+		To:          schedule.To,
+		Amount:      int32(schedule.Amount),
+		Cron:        schedule.Cron,
+		Description: schedule.Description,
+		Status:      string(schedule.Status),
+		CreatedAt:   timestamppb.New(schedule.CreatedAt),
+		UpdatedAt:   timestamppb.New(schedule.UpdatedAt),
+	}
+	if !schedule.NextRunAt.IsZero() {
+		reply.NextRunAt = timestamppb.New(schedule.NextRunAt)
+	}
+
+	return reply
+}
+
+func (s *Server) AccrueInterest(ctx context.Context, req *proto.AccrueInterestRequest) (*proto.Accrual, error) {
+	if s.interest == nil {
+		return nil, errs.InterestDisabled()
+	}
+	v := validation.New()
+	period := v.Period("period", req.GetPeriod())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if req.GetDryRun() {
+		accrual, postings, err := s.interest.Project(ctx, period)
+		if err != nil {
+			return nil, err
+		}
+		reply := accrualReply(accrual)
+		reply.DryRun = true
+		for _, p := range postings {
+			reply.Projected = append(reply.Projected, &proto.Posting{
+				AccountId: p.AccountID,
+				Name:      p.Name,
+				Type:      p.Type,
+				Balance:   int32(p.Balance),
+				RateBps:   int32(p.Rate),
+				Amount:    int32(p.Amount),
+			})
+		}
+		return reply, nil
+	}
+
+	accrual, err := s.interest.Accrue(ctx, period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return accrualReply(accrual), nil
+}
+
+func (s *Server) GetAccrual(ctx context.Context, req *proto.GetAccrualRequest) (*proto.Accrual, error) {
+	v := validation.New()
+	validation.Check(v, "accrual_id", req.GetAccrualId(), validation.Required())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	accrual, err := s.storage.Accrual(ctx, req.GetAccrualId())
+	if err != nil {
+		return nil, err
+	}
+
+	return accrualReply(accrual), nil
+}
+
+func (s *Server) ListAccruals(ctx context.Context, _ *proto.Empty) (*proto.ListAccrualsReply, error) {
+	accruals, err := s.storage.Accruals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListAccrualsReply{Accruals: make([]*proto.Accrual, 0, len(accruals))}
+	for _, accrual := range accruals {
+		reply.Accruals = append(reply.Accruals, accrualReply(accrual))
+	}
+
+	return reply, nil
+}
+
+func (s *Server) Ledger(ctx context.Context, req *proto.LedgerRequest) (*proto.LedgerReply, error) {
+	v := validation.New()
+	name := v.Lookup("name", req.GetName())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.storage.Ledger(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.LedgerReply{Entries: make([]*proto.LedgerEntry, 0, len(entries))}
+	for _, entry := range entries {
+		reply.Entries = append(reply.Entries, &proto.LedgerEntry{
+			Id:        entry.ID,
+			Kind:      string(entry.Kind),
+			Amount:    int32(entry.Amount),
+			Balance:   int32(entry.Balance),
+			Reference: entry.Reference,
+			PostedAt:  timestamppb.New(entry.PostedAt),
+		})
+	}
+
+	return reply, nil
+}
+
+func accrualReply(accrual models.Accrual) *proto.Accrual {
+	reply := &proto.Accrual{
+		Id:          accrual.ID,
+		Compounding: string(accrual.Period.Compounding),
+		PeriodStart: timestamppb.New(accrual.Period.Start),
+		PeriodEnd:   timestamppb.New(accrual.Period.End()),
+		Status:      string(accrual.Status),
+		Cursor:      accrual.Cursor,
+		Postings:    int32(accrual.Postings),
+		Total:       int64(accrual.Total),
+	}
+	if !accrual.StartedAt.IsZero() {
+		reply.StartedAt = timestamppb.New(accrual.StartedAt)
+	}
+	if !accrual.FinishedAt.IsZero() {
+		reply.FinishedAt = timestamppb.New(accrual.FinishedAt)
+	}
+
+	return reply
+}
+
+func (s *Server) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.Webhook, error) {
+	v := validation.New()
+	subscription := models.Subscription{
+		URL:        v.WebhookURL("url", req.GetUrl()),
+		EventTypes: v.EventTypes("event_types", req.GetEventTypes()),
+		Secret:     v.Secret("secret", req.GetSecret()),
+	}
+	for i, account := range req.GetAccounts() {
+		subscription.Accounts = append(subscription.Accounts, v.Lookup(fmt.Sprintf("accounts.%d", i), account))
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if subscription.Secret == "" {
+		subscription.Secret = models.NewSecret()
+	}
+
+	subscription, err := s.storage.CreateSubscription(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+	reply := webhookReply(subscription)
+	reply.Secret = subscription.Secret
+
+	return reply, nil
+}
+
+func (s *Server) GetWebhook(ctx context.Context, req *proto.WebhookRequest) (*proto.Webhook, error) {
+	id, err := webhookID(req.GetWebhookId())
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.storage.Subscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookReply(subscription), nil
+}
+
+func (s *Server) ListWebhooks(ctx context.Context, _ *proto.Empty) (*proto.ListWebhooksReply, error) {
+	subscriptions, err := s.storage.Subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListWebhooksReply{Webhooks: make([]*proto.Webhook, 0, len(subscriptions))}
+	for _, subscription := range subscriptions {
+		reply.Webhooks = append(reply.Webhooks, webhookReply(subscription))
+	}
+
+	return reply, nil
+}
+
+func (s *Server) DeleteWebhook(ctx context.Context, req *proto.WebhookRequest) (*proto.Empty, error) {
+	id, err := webhookID(req.GetWebhookId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.DeleteSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (s *Server) ListDeliveries(ctx context.Context, req *proto.ListDeliveriesRequest) (*proto.ListDeliveriesReply, error) {
+	v := validation.New()
+	validation.Check(v, "webhook_id", req.GetWebhookId(), validation.Required())
+	status := v.DeliveryStatus("status", req.GetStatus())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.storage.Deliveries(ctx, req.GetWebhookId(), status)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListDeliveriesReply{Deliveries: make([]*proto.Delivery, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		reply.Deliveries = append(reply.Deliveries, deliveryReply(delivery))
+	}
+
+	return reply, nil
+}
+
+func (s *Server) ReplayDelivery(ctx context.Context, req *proto.ReplayDeliveryRequest) (*proto.Delivery, error) {
+	v := validation.New()
+	validation.Check(v, "webhook_id", req.GetWebhookId(), validation.Required())
+	validation.Check(v, "delivery_id", req.GetDeliveryId(), validation.Required())
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.storage.ReplayDelivery(ctx, req.GetWebhookId(), req.GetDeliveryId(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveryReply(delivery), nil
+}
+
+func webhookID(id string) (string, error) {
+	v := validation.New()
+	validation.Check(v, "webhook_id", id, validation.Required())
+
+	return id, v.Err()
+}
+
+func webhookReply(subscription models.Subscription) *proto.Webhook {
+	reply := &proto.Webhook{
+		Id:        subscription.ID,
+		Url:       subscription.URL,
+		Accounts:  subscription.Accounts,
+		CreatedAt: timestamppb.New(subscription.CreatedAt),
+	}
+	for _, eventType := range subscription.EventTypes {
+		reply.EventTypes = append(reply.EventTypes, string(eventType))
+	}
+
+	return reply
+}
+
+func deliveryReply(delivery models.Delivery) *proto.Delivery {
+	reply := &proto.Delivery{
+		Id:             delivery.ID,
+		EventId:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		AccountId:      delivery.AccountID,
+		Status:         string(delivery.Status),
+		Attempts:       int32(delivery.Attempts),
+		NextAttemptAt:  timestamppb.New(delivery.NextAttemptAt),
+		LastError:      delivery.LastError,
+		LastStatusCode: int32(delivery.LastStatusCode),
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+	if !delivery.DeliveredAt.IsZero() {
+		reply.DeliveredAt = timestamppb.New(delivery.DeliveredAt)
+	}
+
+	return reply
+}
@@ -0,0 +1,169 @@
+// Package scheduler исполняет расписания переводов из storage.Storage.
+//
+// Планировщик гарантирует исполнение хотя бы один раз: срок, исполнение которого сорвалось
+// (хранилище недоступно, процесс упал), повторяется на следующем проходе. Дважды срок
+// не исполняется — это обеспечивает storage.Storage.RunSchedule, так что несколько
+// планировщиков могут работать с одним хранилищем одновременно.
+package scheduler
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultInterval  = 10 * time.Second
+	DefaultBatchSize = 100
+)
+
+// Clock даёт планировщику текущее время; тесты подменяют его ManualClock.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock стоит на месте, пока его не передвинут.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+// Advance сдвигает часы вперёд на d и возвращает новое время.
+func (c *ManualClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	return c.now
+}
+
+type Scheduler struct {
+	store     storage.Storage
+	clock     Clock
+	interval  time.Duration
+	batchSize int
+}
+
+type Option func(s *Scheduler)
+
+func WithClock(clock Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithInterval задаёт период проходов Run.
+func WithInterval(interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = interval
+	}
+}
+
+// WithBatchSize ограничивает число расписаний, которые читаются из хранилища за раз.
+func WithBatchSize(n int) Option {
+	return func(s *Scheduler) {
+		s.batchSize = n
+	}
+}
+
+func New(store storage.Storage, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		store:     store,
+		clock:     systemClock{},
+		interval:  DefaultInterval,
+		batchSize: DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Run исполняет наступившие сроки раз в интервал, пока не отменён ctx.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("run scheduled transfers failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue исполняет все сроки, наступившие к текущему времени часов, и возвращает записи о них.
+// Расписание, пропустившее несколько сроков, догоняет их по одному, от давних к новым.
+// Срок, исполнение которого сорвалось, остаётся до следующего вызова; первая такая ошибка возвращается.
+func (s *Scheduler) RunDue(ctx context.Context) ([]models.ScheduleRun, error) {
+	now := s.clock.Now()
+	var runs []models.ScheduleRun
+	var firstErr error
+
+	for {
+		due, err := s.store.DueSchedules(ctx, now, s.batchSize)
+		if err != nil {
+			return runs, err
+		}
+
+		progress := false
+		for _, schedule := range due {
+			run, err := s.store.RunSchedule(ctx, schedule.ID, schedule.NextRunAt, now)
+			switch {
+			case errors.Is(err, errs.ErrFailedPrecondition), errors.Is(err, errs.ErrNotFound):
+				// Расписание успели приостановить, отменить или исполнить в другом планировщике.
+				continue
+			case err != nil:
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			progress = true
+			runs = append(runs, run)
+			if run.Status == models.RunFailed {
+				log.Printf("scheduled transfer %s due at %s failed: %s", run.ScheduleID, run.DueAt.Format(time.RFC3339), run.Error)
+			}
+		}
+		// Исполненный срок сдвигает расписание вперёд, так что проходы кончаются, когда сдвигать нечего.
+		if !progress {
+			return runs, firstErr
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"context"
+	"testing"
+	"time"
+)
+
+// base — начало тестового времени; сроки расписаний отсчитываются от него.
+var base = time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// setup заводит хранилище с аккаунтами from (100) и to (0), часы на base и планировщик на них.
+func setup(t *testing.T) (*storage.Memory, *ManualClock, *Scheduler) {
+	t.Helper()
+	store := storage.NewMemory()
+	for _, account := range []models.Account{{Name: "from", Amount: 100}, {Name: "to"}} {
+		if _, err := store.Create(context.Background(), account); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock := NewManualClock(base)
+
+	return store, clock, New(store, WithClock(clock))
+}
+
+func createSchedule(t *testing.T, store storage.Storage, cron string, nextRunAt time.Time) models.Schedule {
+	t.Helper()
+	schedule, err := store.CreateSchedule(context.Background(), models.Schedule{From: "from", To: "to", Amount: 10, Cron: cron, NextRunAt: nextRunAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return schedule
+}
+
+// runDue делает проход планировщика и сверяет сроки исполненных переводов.
+func runDue(t *testing.T, s *Scheduler, want ...time.Time) {
+	t.Helper()
+	runs, err := s.RunDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("RunDue ran %d times, want %d: %+v", len(runs), len(want), runs)
+	}
+	for i, run := range runs {
+		if !run.DueAt.Equal(want[i]) || run.Status != models.RunSucceeded {
+			t.Fatalf("run %d = %+v, want a successful run due at %s", i, run, want[i])
+		}
+	}
+}
+
+func checkBalance(t *testing.T, store storage.Storage, name string, want int) {
+	t.Helper()
+	account, err := store.Get(context.Background(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Amount != want {
+		t.Fatalf("%s has %d, want %d", name, account.Amount, want)
+	}
+}
+
+func TestOneOff(t *testing.T) {
+	store, clock, s := setup(t)
+	schedule := createSchedule(t, store, "", base.Add(time.Hour))
+
+	runDue(t, s)
+	clock.Advance(time.Hour - time.Second)
+	runDue(t, s)
+
+	clock.Advance(time.Second)
+	runDue(t, s, base.Add(time.Hour))
+	checkBalance(t, store, "from", 90)
+	checkBalance(t, store, "to", 10)
+
+	clock.Advance(24 * time.Hour)
+	runDue(t, s)
+	schedule, err := store.Schedule(context.Background(), schedule.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Status != models.ScheduleCompleted || !schedule.NextRunAt.IsZero() {
+		t.Fatalf("schedule = %+v, want completed", schedule)
+	}
+}
+
+func TestCron(t *testing.T) {
+	store, clock, s := setup(t)
+	createSchedule(t, store, "0 * * * *", base)
+
+	runDue(t, s, base)
+	clock.Advance(30 * time.Minute)
+	runDue(t, s)
+	clock.Advance(30 * time.Minute)
+	runDue(t, s, base.Add(time.Hour))
+	checkBalance(t, store, "to", 20)
+}
+
+func TestCatchUpMissedRuns(t *testing.T) {
+	store, clock, s := setup(t)
+	schedule := createSchedule(t, store, "0 * * * *", base)
+
+	// Планировщик простоял три с половиной часа: пропущенные сроки исполняются по порядку.
+	clock.Set(base.Add(3*time.Hour + 30*time.Minute))
+	runDue(t, s, base, base.Add(time.Hour), base.Add(2*time.Hour), base.Add(3*time.Hour))
+	checkBalance(t, store, "to", 40)
+
+	schedule, err := store.Schedule(context.Background(), schedule.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schedule.NextRunAt.Equal(base.Add(4 * time.Hour)) {
+		t.Fatalf("next run at %s, want %s", schedule.NextRunAt, base.Add(4*time.Hour))
+	}
+}
+
+func TestPauseResumeSkipsMissedRuns(t *testing.T) {
+	ctx := context.Background()
+	store, clock, s := setup(t)
+	schedule := createSchedule(t, store, "0 * * * *", base)
+	runDue(t, s, base)
+
+	if _, err := store.ChangeSchedule(ctx, schedule.ID, models.SchedulePause, clock.Advance(10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	clock.Set(base.Add(5*time.Hour + 30*time.Minute))
+	runDue(t, s)
+
+	// Сроки, пропущенные на паузе, не догоняются: следующий — первый после возобновления.
+	resumed, err := store.ChangeSchedule(ctx, schedule.ID, models.ScheduleResume, clock.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.NextRunAt.Equal(base.Add(6 * time.Hour)) {
+		t.Fatalf("next run at %s after resume, want %s", resumed.NextRunAt, base.Add(6*time.Hour))
+	}
+	runDue(t, s)
+	clock.Set(base.Add(6 * time.Hour))
+	runDue(t, s, base.Add(6*time.Hour))
+	checkBalance(t, store, "to", 20)
+}
+
+func TestRunScheduleIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store, clock, s := setup(t)
+	schedule := createSchedule(t, store, "0 * * * *", base)
+
+	first, err := store.RunSchedule(ctx, schedule.ID, base, clock.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Повтор того же срока, например из второго планировщика, возвращает прежнюю запись и не переводит снова.
+	second, err := store.RunSchedule(ctx, schedule.ID, base, clock.Advance(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != first {
+		t.Fatalf("second run = %+v, want the first %+v", second, first)
+	}
+	runDue(t, s)
+	checkBalance(t, store, "from", 90)
+
+	runs, err := store.ScheduleRuns(ctx, schedule.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("%d runs recorded, want 1", len(runs))
+	}
+}
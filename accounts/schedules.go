@@ -0,0 +1,155 @@
+package accounts
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/validation"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Создаёт разовый или повторяющийся перевод
+func (h *Handler) CreateSchedule(c echo.Context) error {
+	var request dto.CreateScheduleRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	v := validation.New()
+	schedule := models.Schedule{
+		From:        v.Lookup("from", request.From),
+		To:          v.Lookup("to", request.To),
+		Amount:      request.Amount,
+		Cron:        v.Cron("cron", request.Cron),
+		Description: v.Description("description", request.Description),
+	}
+	validation.Check(v, "amount", request.Amount, validation.TransferAmount...)
+	schedule.NextRunAt = v.RunAt("run_at", request.RunAt, schedule.Recurring())
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	schedule, err := h.storage.CreateSchedule(c.Request().Context(), schedule)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, "/v1/schedules/"+url.PathEscape(schedule.ID))
+
+	return c.JSON(http.StatusCreated, scheduleResponse(schedule))
+}
+
+// Расписания всех аккаунтов или только аккаунта из параметра account
+func (h *Handler) ListSchedules(c echo.Context) error {
+	account := validation.NormalizeName(c.QueryParam("account"))
+	schedules, err := h.storage.Schedules(c.Request().Context(), account)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.ListSchedulesResponse{Schedules: make([]dto.ScheduleResponse, 0, len(schedules))}
+	for _, schedule := range schedules {
+		response.Schedules = append(response.Schedules, scheduleResponse(schedule))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetSchedule(c echo.Context) error {
+	id, err := scheduleParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	schedule, err := h.storage.Schedule(c.Request().Context(), id)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, scheduleResponse(schedule))
+}
+
+func (h *Handler) PauseSchedule(c echo.Context) error {
+	return h.changeSchedule(c, models.SchedulePause)
+}
+
+// Возобновляет расписание; сроки, пропущенные за время паузы, не исполняются
+func (h *Handler) ResumeSchedule(c echo.Context) error {
+	return h.changeSchedule(c, models.ScheduleResume)
+}
+
+func (h *Handler) CancelSchedule(c echo.Context) error {
+	return h.changeSchedule(c, models.ScheduleCancel)
+}
+
+func (h *Handler) changeSchedule(c echo.Context, action models.ScheduleAction) error {
+	id, err := scheduleParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	schedule, err := h.storage.ChangeSchedule(c.Request().Context(), id, action, time.Now())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, scheduleResponse(schedule))
+}
+
+// Исполнения расписания от старых к новым
+func (h *Handler) ScheduleRuns(c echo.Context) error {
+	id, err := scheduleParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	runs, err := h.storage.ScheduleRuns(c.Request().Context(), id)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.ScheduleRunsResponse{Runs: make([]dto.ScheduleRunResponse, 0, len(runs))}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, dto.ScheduleRunResponse{
+			DueAt:  run.DueAt,
+			Status: string(run.Status),
+			Error:  run.Error,
+			RanAt:  run.RanAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func scheduleParam(c echo.Context) (string, error) {
+	id, err := url.PathUnescape(c.Param("schedule_id"))
+	if err != nil || len(id) == 0 {
+		return "", errs.InvalidField("schedule_id", "invalid schedule ID in path")
+	}
+
+	return id, nil
+}
+
+func scheduleResponse(schedule models.Schedule) dto.ScheduleResponse {
+	response := dto.ScheduleResponse{
+		ID:          schedule.ID,
+		From:        schedule.From,
+		To:          schedule.To,
+		Amount:      schedule.Amount,
+		Cron:        schedule.Cron,
+		Description: schedule.Description,
+		Status:      string(schedule.Status),
+		CreatedAt:   schedule.CreatedAt,
+		UpdatedAt:   schedule.UpdatedAt,
+	}
+	if !schedule.NextRunAt.IsZero() {
+		nextRunAt := schedule.NextRunAt
+		response.NextRunAt = &nextRunAt
+	}
+
+	return response
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// deleteAccount заводит аккаунт name и сразу удаляет его.
+func deleteAccount(t *testing.T, m *Memory, name string) models.Account {
+	t.Helper()
+	ctx := context.Background()
+	account, err := m.Create(ctx, models.Account{Name: name, Amount: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete(ctx, account.ID, "auditor"); err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
+
+func TestDeletedHiddenFromReads(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	fillMemory(t, m, 1, 10)
+	account := deleteAccount(t, m, "alice")
+
+	for _, ref := range []string{account.ID, "alice"} {
+		if _, err := m.Get(ctx, ref); !errors.Is(err, errs.ErrNotFound) {
+			t.Fatalf("get %s = %v, want not_found", ref, err)
+		}
+		deleted, err := m.Get(ctx, ref, IncludeDeleted())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !deleted.Deleted() || deleted.DeletedBy != "auditor" || deleted.Amount != 10 {
+			t.Fatalf("get %s with deleted = %+v, want alice deleted by auditor", ref, deleted)
+		}
+	}
+
+	if list, err := m.List(ctx); err != nil || len(list) != 1 {
+		t.Fatalf("list = %+v, %v; want only the live account", list, err)
+	}
+	if list, err := m.List(ctx, IncludeDeleted()); err != nil || len(list) != 2 {
+		t.Fatalf("list with deleted = %+v, %v; want both accounts", list, err)
+	}
+	if _, err := m.ChangeAmount(ctx, "alice", 20); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("change of a deleted account = %v, want not_found", err)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	account := deleteAccount(t, m, "alice")
+
+	restored, err := m.Restore(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.ID != account.ID || restored.Deleted() || restored.Amount != 10 {
+		t.Fatalf("restored = %+v, want alice as before the delete", restored)
+	}
+	if _, err := m.Get(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Restore(ctx, "alice"); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("restore of a live account = %v, want not_found", err)
+	}
+}
+
+// TestNameReservedUntilPurge: имя удалённого аккаунта занято, пока его можно восстановить.
+func TestNameReservedUntilPurge(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	account := deleteAccount(t, m, "alice")
+	other := fillMemory(t, m, 1, 0)[0]
+
+	if _, err := m.Create(ctx, models.Account{Name: "alice"}); !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("create over a deleted name = %v, want already_exists", err)
+	}
+	if _, err := m.ChangeName(ctx, other, "alice"); !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("rename to a deleted name = %v, want already_exists", err)
+	}
+
+	// Удалённые позже срока не очищаются.
+	if n, err := m.Purge(ctx, account.CreatedAt.Add(-time.Minute)); err != nil || n != 0 {
+		t.Fatalf("purge before the delete = %d, %v; want 0", n, err)
+	}
+	if n, err := m.Purge(ctx, time.Now().Add(time.Minute)); err != nil || n != 1 {
+		t.Fatalf("purge = %d, %v; want 1", n, err)
+	}
+
+	if _, err := m.Restore(ctx, account.ID); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("restore after purge = %v, want not_found", err)
+	}
+	if _, err := m.Get(ctx, account.ID, IncludeDeleted()); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("get after purge = %v, want not_found", err)
+	}
+	created, err := m.Create(ctx, models.Account{Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == account.ID || created.Amount != 0 {
+		t.Fatalf("created = %+v, want a new account", created)
+	}
+}
+
+func TestPurgeDropsSchedules(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	ids := fillMemory(t, m, 3, 100)
+	create := func(from, to string) models.Schedule {
+		t.Helper()
+		schedule, err := m.CreateSchedule(ctx, models.Schedule{From: from, To: to, Amount: 1, Cron: "0 * * * *"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return schedule
+	}
+	outgoing, incoming, kept := create(ids[0], ids[1]), create(ids[2], ids[0]), create(ids[1], ids[2])
+
+	if err := m.Delete(ctx, ids[0], "test"); err != nil {
+		t.Fatal(err)
+	}
+	// До очистки расписания остаются: аккаунт ещё можно восстановить.
+	if _, err := m.Schedule(ctx, outgoing.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Purge(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, schedule := range []models.Schedule{outgoing, incoming} {
+		if _, err := m.Schedule(ctx, schedule.ID); !errors.Is(err, errs.ErrNotFound) {
+			t.Fatalf("schedule %s of the purged account = %v, want not_found", schedule.ID, err)
+		}
+	}
+	schedules, err := m.Schedules(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedules) != 1 || schedules[0].ID != kept.ID {
+		t.Fatalf("schedules after purge = %+v, want only %s", schedules, kept.ID)
+	}
+}
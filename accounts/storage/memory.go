@@ -0,0 +1,1306 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"context"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultShards — число шардов NewMemory; с запасом на число ядер типичного сервера.
+const DefaultShards = 64
+
+func NewMemory() *Memory {
+	return NewShardedMemory(DefaultShards)
+}
+
+// NewShardedMemory создаёт хранилище из shards шардов; 1 шард — прежний вариант с одним мьютексом.
+func NewShardedMemory(shards int) *Memory {
+	m := &Memory{
+		shards:       make([]*shard, max(shards, 1)),
+		snapshots:    make(map[uint64]int),
+		schedules:    make(map[string]models.Schedule),
+		scheduleRuns: make(map[string][]models.ScheduleRun),
+		accruals:     make(map[string]models.Accrual),
+		webhooks:     make(map[string]models.Subscription),
+		deliveries:   make(map[string]models.Delivery),
+		enqueued:     make(map[string]bool),
+	}
+	for i := range m.shards {
+		m.shards[i] = &shard{
+			history: make(map[string][]models.Transition),
+			renames: make(map[string][]models.Rename),
+			holds:   make(map[string][]models.Hold),
+			ledger:  make(map[string][]models.LedgerEntry),
+		}
+	}
+
+	return m
+}
+
+// Memory хранит аккаунты в памяти процесса.
+//
+// Аккаунты разложены по шардам по хешу ID, у каждого шарда свой мьютекс для писателей,
+// поэтому записи в разные шарды не ждут друг друга. Операции над несколькими шардами берут
+// блокировки по возрастанию номера шарда, так что встречные переводы не могут
+// взаимно заблокироваться. Имена занимаются в общем индексе names атомарно, без блокировок,
+// поэтому переименование трогает только шард самого аккаунта.
+//
+// Каждая запись создаёт новую неизменяемую версию аккаунта с номером коммита (MVCC).
+// Читатели не берут блокировок: они видят версии с номером не больше опубликованного,
+// а снимок (Snapshot) фиксирует этот номер на всё время своей жизни.
+// Версии, которые не нужны ни одному открытому снимку, удаляются.
+type Memory struct {
+	shards []*shard
+
+	// names — имя → ID. Имя остаётся занятым удалённым аккаунтом до очистки.
+	names sync.Map
+	// aliases — старое имя → *alias последнего аккаунта, который носил это имя до переименования.
+	aliases sync.Map
+	// holdAccounts — ID холда → ID аккаунта.
+	holdAccounts sync.Map
+
+	// commitGuard упорядочивает коммиты: номер выдаётся и публикуется только после установки всех версий.
+	commitGuard sync.Mutex
+	// committed — номер последнего опубликованного коммита.
+	committed atomic.Uint64
+
+	// snapshotsGuard защищает snapshots: номер коммита → число открытых снимков на нём.
+	snapshotsGuard sync.Mutex
+	snapshots      map[uint64]int
+
+	// scheduleGuard защищает расписания и их исполнения. Его берут раньше блокировок шардов:
+	// RunSchedule переводит деньги, не отпуская его.
+	scheduleGuard sync.Mutex
+	schedules     map[string]models.Schedule
+	scheduleRuns  map[string][]models.ScheduleRun
+
+	// accrualGuard защищает пакеты начисления; как и scheduleGuard, его берут раньше блокировок шардов.
+	accrualGuard sync.Mutex
+	accruals     map[string]models.Accrual
+
+	// webhookGuard защищает подписки и их доставки; под ним не берут других блокировок.
+	webhookGuard sync.Mutex
+	webhooks     map[string]models.Subscription
+	deliveries   map[string]models.Delivery
+	// enqueued — ID событий, по которым уже заведены доставки.
+	enqueued map[string]bool
+
+	// outboxGuard защищает outbox; его берут под commitGuard, поэтому события идут в порядке коммитов.
+	// pending — недоставленные события по возрастанию Sequence, delivered — доставленные до очистки.
+	outboxGuard sync.Mutex
+	sequence    int64
+	pending     []models.Event
+	delivered   []deliveredEvent
+}
+
+type deliveredEvent struct {
+	id string
+	at time.Time
+}
+
+type shard struct {
+	// guard берут только писатели.
+	guard sync.Mutex
+	// chains — ID → *chain; sync.Map позволяет читателям обходить шард без блокировок.
+	chains sync.Map
+	// history — ID → переходы статусов; защищена guard.
+	history map[string][]models.Transition
+	// renames — ID → переименования; защищена guard.
+	renames map[string][]models.Rename
+	// holds — ID → все холды аккаунта; защищена guard.
+	holds map[string][]models.Hold
+	// ledger — ID → проводки; защищён guard.
+	ledger map[string][]models.LedgerEntry
+	// active — ID → []models.Hold активных холдов, копия из holds для чтения без блокировок.
+	// Холды не версионируются: снимок видит их текущее состояние.
+	active sync.Map
+	// Дополнение до строки кеша, чтобы мьютексы соседних шардов не делили её между ядрами.
+	_ [64]byte
+}
+
+// chain — версии одного аккаунта, от новой к старой.
+type chain struct {
+	head atomic.Pointer[version]
+}
+
+// version — неизменяемое состояние аккаунта на момент коммита seq; deleted — надгробие очищенного аккаунта.
+type version struct {
+	seq     uint64
+	account models.Account
+	deleted bool
+	prev    atomic.Pointer[version]
+}
+
+// visible возвращает версию, видимую на момент коммита seq.
+func (c *chain) visible(seq uint64) (models.Account, bool) {
+	account, ok, _ := c.at(seq)
+
+	return account, ok
+}
+
+// at ищет версию на момент seq; reached=false, если таких версий в цепочке нет:
+// аккаунт создан позже или версии уже собраны.
+func (c *chain) at(seq uint64) (account models.Account, ok, reached bool) {
+	for v := c.head.Load(); v != nil; v = v.prev.Load() {
+		if v.seq <= seq {
+			return v.account, !v.deleted, true
+		}
+	}
+
+	return models.Account{}, false, false
+}
+
+// latest — последняя версия.
+func (c *chain) latest() (models.Account, bool) {
+	v := c.head.Load()
+	if v == nil || v.deleted {
+		return models.Account{}, false
+	}
+
+	return v.account, true
+}
+
+// alias — старое имя аккаунта id, освобождённое переименованием в момент at.
+type alias struct {
+	id string
+	at time.Time
+}
+
+// write — изменение одного аккаунта внутри коммита.
+type write struct {
+	shard   *shard
+	id      string
+	account models.Account
+	deleted bool
+	// event — событие, которое коммит пишет в outbox; пустое — без события.
+	event models.EventType
+}
+
+func (m *Memory) shardIndex(id string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+
+	return int(h.Sum32() % uint32(len(m.shards)))
+}
+
+func (m *Memory) shardFor(id string) *shard {
+	return m.shards[m.shardIndex(id)]
+}
+
+// lockPair блокирует шарды двух ID в фиксированном порядке и возвращает функцию разблокировки.
+func (m *Memory) lockPair(a, b string) (*shard, *shard, func()) {
+	i, j := m.shardIndex(a), m.shardIndex(b)
+	first, second := m.shards[i], m.shards[j]
+
+	if i == j {
+		first.guard.Lock()
+		return first, second, first.guard.Unlock
+	}
+	if i > j {
+		second.guard.Lock()
+		first.guard.Lock()
+	} else {
+		first.guard.Lock()
+		second.guard.Lock()
+	}
+
+	return first, second, func() {
+		first.guard.Unlock()
+		second.guard.Unlock()
+	}
+}
+
+// lookup — последняя версия аккаунта, включая удалённые, но не очищенные.
+func (s *shard) lookup(id string) (models.Account, bool) {
+	value, ok := s.chains.Load(id)
+	if !ok {
+		return models.Account{}, false
+	}
+
+	return value.(*chain).latest()
+}
+
+// publishHolds обновляет копию активных холдов аккаунта id; вызывающий держит guard.
+func (s *shard) publishHolds(id string) {
+	var active []models.Hold
+	for _, h := range s.holds[id] {
+		if h.Status == models.HoldActive {
+			active = append(active, h)
+		}
+	}
+	if len(active) == 0 {
+		s.active.Delete(id)
+		return
+	}
+	s.active.Store(id, active)
+}
+
+// withHeld заполняет Held прочитанного аккаунта по текущим активным холдам.
+func (m *Memory) withHeld(account models.Account) models.Account {
+	account.Held = 0
+	if value, ok := m.shardFor(account.ID).active.Load(account.ID); ok {
+		account.Held = models.Held(value.([]models.Hold), time.Now())
+	}
+
+	return account
+}
+
+// resolve находит ID по ссылке: сначала как идентификатор, затем как имя.
+func (m *Memory) resolve(ref string) (string, bool) {
+	if id, ok := models.ParseID(ref); ok {
+		if _, ok := m.shardFor(id).chains.Load(id); ok {
+			return id, true
+		}
+	}
+
+	value, ok := m.names.Load(ref)
+	if !ok {
+		return "", false
+	}
+
+	return value.(string), true
+}
+
+// lock находит аккаунт по ссылке, включая удалённые, и блокирует его шард.
+// Если аккаунт переименовали между поиском и блокировкой, поиск повторяется.
+func (m *Memory) lock(ref string) (*shard, models.Account, func(), error) {
+	for {
+		id, ok := m.resolve(ref)
+		if !ok {
+			return nil, models.Account{}, nil, errs.AccountNotFound(ref)
+		}
+
+		s := m.shardFor(id)
+		s.guard.Lock()
+		account, ok := s.lookup(id)
+		if ok && account.RefersTo(ref) {
+			account.Held = models.Held(s.holds[id], time.Now())
+			return s, account, s.guard.Unlock, nil
+		}
+		s.guard.Unlock()
+		if !ok {
+			return nil, models.Account{}, nil, errs.AccountNotFound(ref)
+		}
+	}
+}
+
+// lockLive — как lock, но удалённые аккаунты считаются отсутствующими.
+func (m *Memory) lockLive(ref string) (*shard, models.Account, func(), error) {
+	s, account, unlock, err := m.lock(ref)
+	if err != nil {
+		return nil, models.Account{}, nil, err
+	}
+	if account.Deleted() {
+		unlock()
+		return nil, models.Account{}, nil, errs.AccountNotFound(ref)
+	}
+
+	return s, account, unlock, nil
+}
+
+// lockTransfer находит два живых аккаунта и блокирует их шарды в фиксированном порядке.
+func (m *Memory) lockTransfer(from, to string) (fromShard, toShard *shard, source, target models.Account, unlock func(), err error) {
+	for {
+		fromID, ok := m.resolve(from)
+		if !ok {
+			return nil, nil, models.Account{}, models.Account{}, nil, errs.AccountNotFound(from)
+		}
+		toID, ok := m.resolve(to)
+		if !ok {
+			return nil, nil, models.Account{}, models.Account{}, nil, errs.AccountNotFound(to)
+		}
+		if fromID == toID {
+			return nil, nil, models.Account{}, models.Account{}, nil, errs.InvalidField("to", "must differ from the source account")
+		}
+
+		fromShard, toShard, unlock = m.lockPair(fromID, toID)
+		source, sourceOK := fromShard.lookup(fromID)
+		target, targetOK := toShard.lookup(toID)
+		switch {
+		case !sourceOK || source.Deleted():
+			err = errs.AccountNotFound(from)
+		case !targetOK || target.Deleted():
+			err = errs.AccountNotFound(to)
+		case !source.RefersTo(from) || !target.RefersTo(to):
+			// Один из аккаунтов переименовали после resolve — ищем заново.
+			unlock()
+			continue
+		default:
+			now := time.Now()
+			source.Held = models.Held(fromShard.holds[fromID], now)
+			target.Held = models.Held(toShard.holds[toID], now)
+			return fromShard, toShard, source, target, unlock, nil
+		}
+		unlock()
+
+		return nil, nil, models.Account{}, models.Account{}, nil, err
+	}
+}
+
+// claim занимает имя за аккаунтом id; ошибка, если имя занято живым или удалённым аккаунтом.
+func (m *Memory) claim(name, id string) error {
+	value, loaded := m.names.LoadOrStore(name, id)
+	if !loaded || value.(string) == id {
+		return nil
+	}
+
+	owner := value.(string)
+	if account, ok := m.shardFor(owner).lookup(owner); ok && account.Deleted() {
+		return errs.AccountDeleted(name)
+	}
+
+	return errs.AccountAlreadyExists(name)
+}
+
+// commit атомарно устанавливает версии всех writes под одним номером коммита.
+// Вызывающий держит блокировки шардов всех затронутых аккаунтов.
+func (m *Memory) commit(writes ...write) {
+	horizon := m.horizon()
+
+	m.commitGuard.Lock()
+	seq := m.committed.Load() + 1
+	for _, w := range writes {
+		value, _ := w.shard.chains.LoadOrStore(w.id, &chain{})
+		c := value.(*chain)
+
+		v := &version{seq: seq, account: w.account, deleted: w.deleted}
+		v.prev.Store(c.head.Load())
+		c.head.Store(v)
+		trim(c, horizon)
+	}
+	m.record(writes)
+	m.committed.Store(seq)
+	m.commitGuard.Unlock()
+}
+
+// record пишет в outbox события writes; вызывается под commitGuard.
+func (m *Memory) record(writes []write) {
+	m.outboxGuard.Lock()
+	defer m.outboxGuard.Unlock()
+
+	for _, w := range writes {
+		if w.event == "" {
+			continue
+		}
+		m.sequence++
+		event := models.NewEvent(w.event, w.account)
+		event.Sequence = m.sequence
+		m.pending = append(m.pending, event)
+	}
+}
+
+// trim отрезает версии старше самой новой версии, видимой на горизонте.
+func trim(c *chain, horizon uint64) {
+	for v := c.head.Load(); v != nil; v = v.prev.Load() {
+		if v.seq <= horizon {
+			v.prev.Store(nil)
+			return
+		}
+	}
+}
+
+func (m *Memory) Get(_ context.Context, ref string, opts ...ReadOption) (models.Account, error) {
+	o := readOptionsOf(opts)
+	if account, ok := m.get(ref); ok && o.visible(account) {
+		return m.withHeld(account), nil
+	}
+	if o.followRenames > 0 {
+		if account, ok := m.renamed(ref, o.followRenames); ok && o.visible(account) {
+			return m.withHeld(account), nil
+		}
+	}
+
+	return models.Account{}, errs.AccountNotFound(ref)
+}
+
+// get читает последнюю опубликованную версию аккаунта, включая удалённые.
+func (m *Memory) get(ref string) (models.Account, bool) {
+	id, ok := m.resolve(ref)
+	if !ok {
+		return models.Account{}, false
+	}
+	value, ok := m.shardFor(id).chains.Load(id)
+	if !ok {
+		return models.Account{}, false
+	}
+	// Get не регистрирует снимок, поэтому нужные ему версии могут собрать;
+	// тогда чтение повторяется на более новом коммите.
+	c := value.(*chain)
+	for {
+		seq := m.committed.Load()
+		account, ok, reached := c.at(seq)
+		if reached || seq == m.committed.Load() {
+			// Имя могли освободить переименованием уже после resolve.
+			return account, ok && account.RefersTo(ref)
+		}
+	}
+}
+
+// renamed ищет аккаунт, который освободил имя name переименованием не раньше within назад.
+// Занятое имя принадлежит своему нынешнему владельцу, даже удалённому.
+func (m *Memory) renamed(name string, within time.Duration) (models.Account, bool) {
+	value, ok := m.aliases.Load(name)
+	if !ok {
+		return models.Account{}, false
+	}
+	a := value.(*alias)
+	if time.Since(a.at) > within {
+		return models.Account{}, false
+	}
+	if _, taken := m.names.Load(name); taken {
+		return models.Account{}, false
+	}
+
+	return m.get(a.id)
+}
+
+// List читает из снимка и не блокирует писателей.
+func (m *Memory) List(_ context.Context, opts ...ReadOption) ([]models.Account, error) {
+	snapshot := m.Snapshot()
+	defer snapshot.Close()
+
+	o := readOptionsOf(opts)
+	accounts := make([]models.Account, 0)
+	for _, account := range snapshot.List() {
+		if o.visible(account) {
+			accounts = append(accounts, m.withHeld(account))
+		}
+	}
+
+	return accounts, nil
+}
+
+func (m *Memory) Export(ctx context.Context, fn func(models.Account) error, opts ...ReadOption) error {
+	snapshot := m.Snapshot()
+	defer snapshot.Close()
+
+	o := readOptionsOf(opts)
+	for _, account := range snapshot.List() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !o.visible(account) {
+			continue
+		}
+		if err := fn(m.withHeld(account)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) Create(_ context.Context, account models.Account) (models.Account, error) {
+	account.ID = models.NewID()
+	account.Status = initialStatus(account.Status)
+	account.Labels = maps.Clone(account.Labels)
+	account.CreatedAt = time.Now().UTC()
+	account.UpdatedAt = account.CreatedAt
+	if err := m.claim(account.Name, account.ID); err != nil {
+		return models.Account{}, err
+	}
+
+	s := m.shardFor(account.ID)
+	s.guard.Lock()
+	defer s.guard.Unlock()
+
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventAccountCreated})
+
+	return account, nil
+}
+
+func (m *Memory) ChangeAmount(_ context.Context, ref string, amount int) (models.Account, error) {
+	s, account, unlock, err := m.lockLive(ref)
+	if err != nil {
+		return models.Account{}, err
+	}
+	defer unlock()
+
+	if err := checkAmount(account, amount); err != nil {
+		return models.Account{}, err
+	}
+
+	account.Amount = amount
+	touch(&account)
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventBalanceChanged})
+
+	return account, nil
+}
+
+func (m *Memory) ChangeName(_ context.Context, ref, newName string) (models.Account, error) {
+	s, account, unlock, err := m.lockLive(ref)
+	if err != nil {
+		return models.Account{}, err
+	}
+	defer unlock()
+
+	if err := checkAction(account, models.ActionRename); err != nil {
+		return models.Account{}, err
+	}
+	if account.Name == newName {
+		return account, nil
+	}
+	if err := m.claim(newName, account.ID); err != nil {
+		return models.Account{}, err
+	}
+
+	rename := models.Rename{From: account.Name, To: newName, At: time.Now().UTC()}
+	account.Name = newName
+	account.UpdatedAt = rename.At
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventAccountRenamed})
+	m.names.CompareAndDelete(rename.From, account.ID)
+	s.renames[account.ID] = append(s.renames[account.ID], rename)
+	m.aliases.Store(rename.From, &alias{id: account.ID, at: rename.At})
+
+	return account, nil
+}
+
+func (m *Memory) ChangeMetadata(_ context.Context, ref string, change models.MetadataChange) (models.Account, error) {
+	s, account, unlock, err := m.lockLive(ref)
+	if err != nil {
+		return models.Account{}, err
+	}
+	defer unlock()
+
+	if err := annotate(&account, change); err != nil {
+		return models.Account{}, err
+	}
+
+	touch(&account)
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventAccountUpdated})
+
+	return account, nil
+}
+
+func (m *Memory) Delete(_ context.Context, ref, actor string) error {
+	s, account, unlock, err := m.lockLive(ref)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := checkDelete(account); err != nil {
+		return err
+	}
+
+	account.DeletedAt = time.Now().UTC()
+	account.DeletedBy = actor
+	account.UpdatedAt = account.DeletedAt
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventAccountDeleted})
+
+	return nil
+}
+
+func (m *Memory) Restore(_ context.Context, ref string) (models.Account, error) {
+	s, account, unlock, err := m.lock(ref)
+	if err != nil {
+		return models.Account{}, errs.DeletedAccountNotFound(ref)
+	}
+	defer unlock()
+
+	if !account.Deleted() {
+		return models.Account{}, errs.DeletedAccountNotFound(ref)
+	}
+
+	account.DeletedAt = time.Time{}
+	account.DeletedBy = ""
+	touch(&account)
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventAccountRestored})
+
+	return account, nil
+}
+
+// Purge удаляет версии окончательно, оставляя надгробия; их уберёт сборка мусора.
+// Имена очищенных аккаунтов освобождаются, их старые имена больше никуда не ведут.
+func (m *Memory) Purge(_ context.Context, before time.Time) (int, error) {
+	purged := 0
+	var purgedIDs []string
+	for _, s := range m.shards {
+		s.guard.Lock()
+		var writes []write
+		s.chains.Range(func(id, value any) bool {
+			account, ok := value.(*chain).latest()
+			if ok && account.Deleted() && account.DeletedAt.Before(before) {
+				writes = append(writes, write{shard: s, id: id.(string), account: account, deleted: true})
+			}
+			return true
+		})
+		if len(writes) > 0 {
+			m.commit(writes...)
+			purged += len(writes)
+		}
+		for _, w := range writes {
+			delete(s.history, w.id)
+			m.names.CompareAndDelete(w.account.Name, w.id)
+			for _, r := range s.renames[w.id] {
+				if value, ok := m.aliases.Load(r.From); ok && value.(*alias).id == w.id {
+					m.aliases.CompareAndDelete(r.From, value)
+				}
+			}
+			delete(s.renames, w.id)
+			for _, h := range s.holds[w.id] {
+				m.holdAccounts.Delete(h.ID)
+			}
+			delete(s.holds, w.id)
+			delete(s.ledger, w.id)
+			s.active.Delete(w.id)
+			purgedIDs = append(purgedIDs, w.id)
+		}
+		s.guard.Unlock()
+	}
+	m.dropSchedules(purgedIDs)
+
+	return purged, nil
+}
+
+// dropSchedules удаляет расписания очищенных аккаунтов вместе с их исполнениями.
+func (m *Memory) dropSchedules(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	for id, schedule := range m.schedules {
+		if slices.Contains(ids, schedule.From) || slices.Contains(ids, schedule.To) {
+			delete(m.schedules, id)
+			delete(m.scheduleRuns, id)
+		}
+	}
+}
+
+func (m *Memory) SetStatus(_ context.Context, ref string, change models.Change, reason, actor string) (models.Account, error) {
+	s, account, unlock, err := m.lockLive(ref)
+	if err != nil {
+		return models.Account{}, err
+	}
+	defer unlock()
+
+	t, err := transition(account, change, reason, actor)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	account.Status = change.To
+	account.UpdatedAt = t.At
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventStatusChanged})
+	s.history[account.ID] = append(s.history[account.ID], t)
+
+	return account, nil
+}
+
+// StatusHistory доступна и для удалённых аккаунтов, пока они не очищены.
+func (m *Memory) StatusHistory(_ context.Context, ref string) ([]models.Transition, error) {
+	s, account, unlock, err := m.lock(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return append([]models.Transition{}, s.history[account.ID]...), nil
+}
+
+// RenameHistory доступна и для удалённых аккаунтов, пока они не очищены.
+func (m *Memory) RenameHistory(_ context.Context, ref string) ([]models.Rename, error) {
+	s, account, unlock, err := m.lock(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return append([]models.Rename{}, s.renames[account.ID]...), nil
+}
+
+func (m *Memory) Transfer(_ context.Context, from, to string, amount int) (models.Account, models.Account, error) {
+	if err := checkTransfer(from, to, amount); err != nil {
+		return models.Account{}, models.Account{}, err
+	}
+
+	fromShard, toShard, source, target, unlock, err := m.lockTransfer(from, to)
+	if err != nil {
+		return models.Account{}, models.Account{}, err
+	}
+	defer unlock()
+
+	if err := applyTransfer(&source, &target, amount); err != nil {
+		return models.Account{}, models.Account{}, err
+	}
+
+	m.commit(
+		write{shard: fromShard, id: source.ID, account: source, event: models.EventBalanceChanged},
+		write{shard: toShard, id: target.ID, account: target, event: models.EventBalanceChanged},
+	)
+
+	return source, target, nil
+}
+
+func (m *Memory) Authorize(_ context.Context, ref string, amount int, ttl time.Duration) (models.Hold, error) {
+	s, account, unlock, err := m.lockLive(ref)
+	if err != nil {
+		return models.Hold{}, err
+	}
+	defer unlock()
+
+	hold, err := authorize(account, amount, ttl)
+	if err != nil {
+		return models.Hold{}, err
+	}
+
+	s.holds[account.ID] = append(s.holds[account.ID], hold)
+	s.publishHolds(account.ID)
+	m.holdAccounts.Store(hold.ID, account.ID)
+
+	return hold, nil
+}
+
+// lockHold находит холд и блокирует шард его аккаунта; i — номер холда в shard.holds.
+func (m *Memory) lockHold(holdID string) (s *shard, account models.Account, i int, unlock func(), err error) {
+	value, ok := m.holdAccounts.Load(holdID)
+	if !ok {
+		return nil, models.Account{}, 0, nil, errs.HoldNotFound(holdID)
+	}
+	s, account, unlock, err = m.lock(value.(string))
+	if err != nil {
+		return nil, models.Account{}, 0, nil, errs.HoldNotFound(holdID)
+	}
+	for i, h := range s.holds[account.ID] {
+		if h.ID == holdID {
+			return s, account, i, unlock, nil
+		}
+	}
+	unlock()
+
+	return nil, models.Account{}, 0, nil, errs.HoldNotFound(holdID)
+}
+
+func (m *Memory) Capture(_ context.Context, holdID string, amount int) (models.Hold, models.Account, error) {
+	s, account, i, unlock, err := m.lockHold(holdID)
+	if err != nil {
+		return models.Hold{}, models.Account{}, err
+	}
+	defer unlock()
+
+	if account.Deleted() {
+		return models.Hold{}, models.Account{}, errs.AccountNotFound(account.Name)
+	}
+	hold := s.holds[account.ID][i]
+	if err := capture(&account, &hold, amount); err != nil {
+		return models.Hold{}, models.Account{}, err
+	}
+
+	m.commit(write{shard: s, id: account.ID, account: account, event: models.EventBalanceChanged})
+	s.holds[account.ID][i] = hold
+	s.publishHolds(account.ID)
+
+	return hold, account, nil
+}
+
+// Release снимает холд и у удалённого аккаунта: снятие только освобождает средства.
+func (m *Memory) Release(_ context.Context, holdID string) (models.Hold, error) {
+	s, account, i, unlock, err := m.lockHold(holdID)
+	if err != nil {
+		return models.Hold{}, err
+	}
+	defer unlock()
+
+	hold := s.holds[account.ID][i]
+	if err := release(&hold); err != nil {
+		return models.Hold{}, err
+	}
+
+	s.holds[account.ID][i] = hold
+	s.publishHolds(account.ID)
+
+	return hold, nil
+}
+
+func (m *Memory) Hold(_ context.Context, holdID string) (models.Hold, error) {
+	s, account, i, unlock, err := m.lockHold(holdID)
+	if err != nil {
+		return models.Hold{}, err
+	}
+	defer unlock()
+
+	return s.holds[account.ID][i].At(time.Now()), nil
+}
+
+// Holds доступны и для удалённых аккаунтов, пока они не очищены.
+func (m *Memory) Holds(_ context.Context, ref string) ([]models.Hold, error) {
+	s, account, unlock, err := m.lock(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	now := time.Now()
+	holds := make([]models.Hold, 0, len(s.holds[account.ID]))
+	for _, h := range s.holds[account.ID] {
+		holds = append(holds, h.At(now))
+	}
+
+	return holds, nil
+}
+
+func (m *Memory) ExpireHolds(_ context.Context, now time.Time) (int, error) {
+	expired := 0
+	for _, s := range m.shards {
+		s.guard.Lock()
+		for id, holds := range s.holds {
+			changed := false
+			for i, h := range holds {
+				if h.Status == models.HoldActive && !h.Active(now) {
+					holds[i] = h.At(now)
+					changed = true
+					expired++
+				}
+			}
+			if changed {
+				s.publishHolds(id)
+			}
+		}
+		s.guard.Unlock()
+	}
+
+	return expired, nil
+}
+
+func (m *Memory) CreateSchedule(ctx context.Context, schedule models.Schedule) (models.Schedule, error) {
+	from, err := m.Get(ctx, schedule.From)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	to, err := m.Get(ctx, schedule.To)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	schedule.From, schedule.To = from.ID, to.ID
+	if schedule, err = newSchedule(schedule, time.Now()); err != nil {
+		return models.Schedule{}, err
+	}
+
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	m.schedules[schedule.ID] = schedule
+
+	return schedule, nil
+}
+
+func (m *Memory) Schedule(_ context.Context, id string) (models.Schedule, error) {
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	schedule, ok := m.schedules[id]
+	if !ok {
+		return models.Schedule{}, errs.ScheduleNotFound(id)
+	}
+
+	return schedule, nil
+}
+
+// Schedules находит расписания и удалённого, ещё не очищенного аккаунта.
+func (m *Memory) Schedules(ctx context.Context, ref string) ([]models.Schedule, error) {
+	var accountID string
+	if ref != "" {
+		account, err := m.Get(ctx, ref, IncludeDeleted())
+		if err != nil {
+			return nil, err
+		}
+		accountID = account.ID
+	}
+
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	schedules := make([]models.Schedule, 0)
+	for _, schedule := range m.schedules {
+		if accountID == "" || schedule.From == accountID || schedule.To == accountID {
+			schedules = append(schedules, schedule)
+		}
+	}
+	slices.SortFunc(schedules, func(a, b models.Schedule) int { return strings.Compare(a.ID, b.ID) })
+
+	return schedules, nil
+}
+
+func (m *Memory) ChangeSchedule(_ context.Context, id string, action models.ScheduleAction, now time.Time) (models.Schedule, error) {
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	schedule, ok := m.schedules[id]
+	if !ok {
+		return models.Schedule{}, errs.ScheduleNotFound(id)
+	}
+	schedule, err := changeSchedule(schedule, action, now)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	m.schedules[id] = schedule
+
+	return schedule, nil
+}
+
+func (m *Memory) DueSchedules(_ context.Context, now time.Time, limit int) ([]models.Schedule, error) {
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	var due []models.Schedule
+	for _, schedule := range m.schedules {
+		if schedule.Due(now) {
+			due = append(due, schedule)
+		}
+	}
+	slices.SortFunc(due, func(a, b models.Schedule) int {
+		if c := a.NextRunAt.Compare(b.NextRunAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
+
+func (m *Memory) RunSchedule(ctx context.Context, id string, dueAt, now time.Time) (models.ScheduleRun, error) {
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	schedule, ok := m.schedules[id]
+	if !ok {
+		return models.ScheduleRun{}, errs.ScheduleNotFound(id)
+	}
+	for _, run := range m.scheduleRuns[id] {
+		if run.DueAt.Equal(dueAt) {
+			return run, nil
+		}
+	}
+	if err := checkDue(schedule, dueAt); err != nil {
+		return models.ScheduleRun{}, err
+	}
+
+	_, _, err := m.Transfer(ctx, schedule.From, schedule.To, schedule.Amount)
+	run, err := finishRun(&schedule, dueAt, now, err)
+	if err != nil {
+		return models.ScheduleRun{}, err
+	}
+	m.schedules[id] = schedule
+	m.scheduleRuns[id] = append(m.scheduleRuns[id], run)
+
+	return run, nil
+}
+
+func (m *Memory) ScheduleRuns(_ context.Context, id string) ([]models.ScheduleRun, error) {
+	m.scheduleGuard.Lock()
+	defer m.scheduleGuard.Unlock()
+
+	if _, ok := m.schedules[id]; !ok {
+		return nil, errs.ScheduleNotFound(id)
+	}
+
+	return append(make([]models.ScheduleRun, 0, len(m.scheduleRuns[id])), m.scheduleRuns[id]...), nil
+}
+
+func (m *Memory) BeginAccrual(_ context.Context, period models.Period, now time.Time) (models.Accrual, error) {
+	m.accrualGuard.Lock()
+	defer m.accrualGuard.Unlock()
+
+	accrual, ok := m.accruals[period.ID()]
+	if !ok {
+		accrual = newAccrual(period, now)
+		m.accruals[accrual.ID] = accrual
+	}
+
+	return accrual, nil
+}
+
+func (m *Memory) PostInterest(_ context.Context, accrualID, ref string, interest func(models.Account) int, now time.Time) (models.LedgerEntry, error) {
+	m.accrualGuard.Lock()
+	defer m.accrualGuard.Unlock()
+
+	accrual, ok := m.accruals[accrualID]
+	if !ok {
+		return models.LedgerEntry{}, errs.AccrualNotFound(accrualID)
+	}
+	s, account, unlock, err := m.lockLive(ref)
+	if err != nil {
+		return models.LedgerEntry{}, err
+	}
+	defer unlock()
+
+	for _, entry := range s.ledger[account.ID] {
+		if entry.Reference == accrualID {
+			return entry, nil
+		}
+	}
+	entry, err := postInterest(&accrual, &account, interest(account), now)
+	if err != nil {
+		return models.LedgerEntry{}, err
+	}
+	if entry.ID != "" {
+		m.commit(write{shard: s, id: account.ID, account: account, event: models.EventBalanceChanged})
+		s.ledger[account.ID] = append(s.ledger[account.ID], entry)
+	}
+	m.accruals[accrualID] = accrual
+
+	return entry, nil
+}
+
+func (m *Memory) FinishAccrual(_ context.Context, id string, now time.Time) (models.Accrual, error) {
+	m.accrualGuard.Lock()
+	defer m.accrualGuard.Unlock()
+
+	accrual, ok := m.accruals[id]
+	if !ok {
+		return models.Accrual{}, errs.AccrualNotFound(id)
+	}
+	finishAccrual(&accrual, now)
+	m.accruals[id] = accrual
+
+	return accrual, nil
+}
+
+func (m *Memory) Accrual(_ context.Context, id string) (models.Accrual, error) {
+	m.accrualGuard.Lock()
+	defer m.accrualGuard.Unlock()
+
+	accrual, ok := m.accruals[id]
+	if !ok {
+		return models.Accrual{}, errs.AccrualNotFound(id)
+	}
+
+	return accrual, nil
+}
+
+func (m *Memory) Accruals(_ context.Context) ([]models.Accrual, error) {
+	m.accrualGuard.Lock()
+	defer m.accrualGuard.Unlock()
+
+	accruals := make([]models.Accrual, 0, len(m.accruals))
+	for _, accrual := range m.accruals {
+		accruals = append(accruals, accrual)
+	}
+	slices.SortFunc(accruals, func(a, b models.Accrual) int {
+		if c := a.Period.Start.Compare(b.Period.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return accruals, nil
+}
+
+func (m *Memory) Ledger(_ context.Context, ref string) ([]models.LedgerEntry, error) {
+	s, account, unlock, err := m.lock(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return append(make([]models.LedgerEntry, 0, len(s.ledger[account.ID])), s.ledger[account.ID]...), nil
+}
+
+func (m *Memory) CreateSubscription(ctx context.Context, subscription models.Subscription) (models.Subscription, error) {
+	accounts := make([]string, 0, len(subscription.Accounts))
+	for _, ref := range subscription.Accounts {
+		account, err := m.Get(ctx, ref)
+		if err != nil {
+			return models.Subscription{}, err
+		}
+		accounts = append(accounts, account.ID)
+	}
+	subscription.Accounts = accounts
+	subscription = newSubscription(subscription)
+
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	m.webhooks[subscription.ID] = subscription
+
+	return subscription, nil
+}
+
+func (m *Memory) Subscription(_ context.Context, id string) (models.Subscription, error) {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	subscription, ok := m.webhooks[id]
+	if !ok {
+		return models.Subscription{}, errs.SubscriptionNotFound(id)
+	}
+
+	return subscription, nil
+}
+
+func (m *Memory) Subscriptions(_ context.Context) ([]models.Subscription, error) {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	subscriptions := m.subscriptions()
+	slices.SortFunc(subscriptions, func(a, b models.Subscription) int { return strings.Compare(a.ID, b.ID) })
+
+	return subscriptions, nil
+}
+
+// subscriptions возвращает подписки в произвольном порядке; вызывается под webhookGuard.
+func (m *Memory) subscriptions() []models.Subscription {
+	subscriptions := make([]models.Subscription, 0, len(m.webhooks))
+	for _, subscription := range m.webhooks {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions
+}
+
+func (m *Memory) DeleteSubscription(_ context.Context, id string) error {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	if _, ok := m.webhooks[id]; !ok {
+		return errs.SubscriptionNotFound(id)
+	}
+	delete(m.webhooks, id)
+	maps.DeleteFunc(m.deliveries, func(_ string, delivery models.Delivery) bool { return delivery.SubscriptionID == id })
+
+	return nil
+}
+
+func (m *Memory) EnqueueEvent(_ context.Context, event models.Event, payload []byte) ([]models.Delivery, error) {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	if m.enqueued[event.ID] {
+		return nil, nil
+	}
+	m.enqueued[event.ID] = true
+	deliveries := newDeliveries(m.subscriptions(), event, payload)
+	for _, delivery := range deliveries {
+		m.deliveries[delivery.ID] = delivery
+	}
+
+	return deliveries, nil
+}
+
+func (m *Memory) DueDeliveries(_ context.Context, now time.Time, limit int) ([]models.Delivery, error) {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	var due []models.Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	slices.SortFunc(due, func(a, b models.Delivery) int {
+		if c := a.NextAttemptAt.Compare(b.NextAttemptAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
+
+func (m *Memory) UpdateDelivery(_ context.Context, delivery models.Delivery) error {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	if _, ok := m.deliveries[delivery.ID]; !ok {
+		return errs.DeliveryNotFound(delivery.ID)
+	}
+	m.deliveries[delivery.ID] = delivery
+
+	return nil
+}
+
+func (m *Memory) Deliveries(_ context.Context, subscriptionID string, status models.DeliveryStatus) ([]models.Delivery, error) {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	if _, ok := m.webhooks[subscriptionID]; !ok {
+		return nil, errs.SubscriptionNotFound(subscriptionID)
+	}
+	deliveries := make([]models.Delivery, 0)
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionID == subscriptionID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	slices.SortFunc(deliveries, func(a, b models.Delivery) int { return strings.Compare(a.ID, b.ID) })
+
+	return deliveries, nil
+}
+
+func (m *Memory) ReplayDelivery(_ context.Context, subscriptionID, id string, now time.Time) (models.Delivery, error) {
+	m.webhookGuard.Lock()
+	defer m.webhookGuard.Unlock()
+
+	delivery, ok := m.deliveries[id]
+	if !ok || delivery.SubscriptionID != subscriptionID {
+		return models.Delivery{}, errs.DeliveryNotFound(id)
+	}
+	replayDelivery(&delivery, now)
+	m.deliveries[id] = delivery
+
+	return delivery, nil
+}
+
+func (m *Memory) PendingEvents(_ context.Context, limit int) ([]models.Event, error) {
+	m.outboxGuard.Lock()
+	defer m.outboxGuard.Unlock()
+
+	n := min(limit, len(m.pending))
+
+	return append(make([]models.Event, 0, n), m.pending[:n]...), nil
+}
+
+func (m *Memory) MarkDelivered(_ context.Context, ids []string, now time.Time) error {
+	marked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		marked[id] = true
+	}
+
+	m.outboxGuard.Lock()
+	defer m.outboxGuard.Unlock()
+
+	pending := m.pending[:0]
+	for _, event := range m.pending {
+		if marked[event.ID] {
+			m.delivered = append(m.delivered, deliveredEvent{id: event.ID, at: now.UTC()})
+		} else {
+			pending = append(pending, event)
+		}
+	}
+	clear(m.pending[len(pending):])
+	m.pending = pending
+
+	return nil
+}
+
+func (m *Memory) PurgeEvents(_ context.Context, before time.Time) (int, error) {
+	m.outboxGuard.Lock()
+	defer m.outboxGuard.Unlock()
+
+	kept := m.delivered[:0]
+	for _, event := range m.delivered {
+		if !event.at.Before(before) {
+			kept = append(kept, event)
+		}
+	}
+	purged := len(m.delivered) - len(kept)
+	m.delivered = kept
+
+	return purged, nil
+}
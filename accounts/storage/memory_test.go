@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fillMemory заводит n аккаунтов с балансом amount и возвращает их ID.
+func fillMemory(tb testing.TB, m *Memory, n, amount int) []string {
+	tb.Helper()
+	ids := make([]string, n)
+	for i := range ids {
+		account, err := m.Create(context.Background(), models.Account{Name: fmt.Sprintf("account-%d", i), Amount: amount})
+		if err != nil {
+			tb.Fatal(err)
+		}
+		ids[i] = account.ID
+	}
+
+	return ids
+}
+
+// TestMemoryConcurrentTransferRename гоняет встречные переводы вместе с переименованиями
+// и чтениями; запускать с -race. Взаимная блокировка обрывается таймаутом.
+func TestMemoryConcurrentTransferRename(t *testing.T) {
+	const (
+		accounts = 16
+		amount   = 1000
+		workers  = 8
+		ops      = 2000
+	)
+	ctx := context.Background()
+	m := NewShardedMemory(4)
+	ids := fillMemory(t, m, accounts, amount)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < ops; i++ {
+				from, to := ids[rnd.Intn(accounts)], ids[rnd.Intn(accounts)]
+				switch rnd.Intn(3) {
+				case 0:
+					if from != to {
+						_, _, _ = m.Transfer(ctx, from, to, 1+rnd.Intn(10))
+					}
+				case 1:
+					_, _ = m.ChangeName(ctx, from, fmt.Sprintf("renamed-%d-%d", w, i))
+				default:
+					if _, err := m.Get(ctx, from); err != nil {
+						t.Errorf("get %s: %v", from, err)
+					}
+				}
+			}
+		}(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("concurrent transfers and renames did not finish: deadlock")
+	}
+
+	// Переводы не создают и не теряют деньги, а каждое имя ведёт к своему аккаунту.
+	total := 0
+	list, err := m.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range list {
+		total += account.Amount
+		found, err := m.Get(ctx, account.Name)
+		if err != nil || found.ID != account.ID {
+			t.Errorf("get by name %q = %s, %v; want %s", account.Name, found.ID, err, account.ID)
+		}
+	}
+	if len(list) != accounts || total != accounts*amount {
+		t.Fatalf("%d accounts with %d in total, want %d with %d", len(list), total, accounts, accounts*amount)
+	}
+}
+
+// benchShards — число шардов, которые сравнивают бенчмарки: один мьютекс против шардов по умолчанию.
+var benchShards = []int{1, DefaultShards}
+
+const benchKeys = 10_000
+
+// benchMemory запускает op параллельно на хранилище с каждым числом шардов из benchShards.
+func benchMemory(b *testing.B, op func(m *Memory, ids []string, rnd *rand.Rand)) {
+	for _, shards := range benchShards {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			m := NewShardedMemory(shards)
+			ids := fillMemory(b, m, benchKeys, 1_000_000)
+			var seed atomic.Int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				rnd := rand.New(rand.NewSource(seed.Add(1)))
+				for pb.Next() {
+					op(m, ids, rnd)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkMemoryGet(b *testing.B) {
+	ctx := context.Background()
+	benchMemory(b, func(m *Memory, ids []string, rnd *rand.Rand) {
+		_, _ = m.Get(ctx, ids[rnd.Intn(len(ids))])
+	})
+}
+
+func BenchmarkMemoryChangeAmount(b *testing.B) {
+	ctx := context.Background()
+	benchMemory(b, func(m *Memory, ids []string, rnd *rand.Rand) {
+		_, _ = m.ChangeAmount(ctx, ids[rnd.Intn(len(ids))], rnd.Intn(1000))
+	})
+}
+
+// BenchmarkMemoryMixed — девять чтений на одну запись.
+func BenchmarkMemoryMixed(b *testing.B) {
+	ctx := context.Background()
+	benchMemory(b, func(m *Memory, ids []string, rnd *rand.Rand) {
+		id := ids[rnd.Intn(len(ids))]
+		if rnd.Intn(10) == 0 {
+			_, _ = m.ChangeAmount(ctx, id, rnd.Intn(1000))
+		} else {
+			_, _ = m.Get(ctx, id)
+		}
+	})
+}
+
+// BenchmarkMemoryRename переименовывает туда и обратно, чтобы набор имён не менялся.
+func BenchmarkMemoryRename(b *testing.B) {
+	ctx := context.Background()
+	benchMemory(b, func(m *Memory, ids []string, rnd *rand.Rand) {
+		i := rnd.Intn(len(ids))
+		if _, err := m.ChangeName(ctx, ids[i], fmt.Sprintf("account-%d-renamed", i)); err == nil {
+			_, _ = m.ChangeName(ctx, ids[i], fmt.Sprintf("account-%d", i))
+		}
+	})
+}
+
+func BenchmarkMemoryTransfer(b *testing.B) {
+	ctx := context.Background()
+	benchMemory(b, func(m *Memory, ids []string, rnd *rand.Rand) {
+		from, to := rnd.Intn(len(ids)), rnd.Intn(len(ids))
+		if from != to {
+			_, _, _ = m.Transfer(ctx, ids[from], ids[to], 1)
+		}
+	})
+}
+
+// TestMemoryCloseWithActiveHolds: аккаунт не закрывается, пока на нём есть активный холд,
+// иначе зарезервированные деньги застряли бы на закрытом счёте без возможности списания.
+func TestMemoryCloseWithActiveHolds(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	id := fillMemory(t, m, 1, 100)[0]
+	hold, err := m.Authorize(ctx, id, 60, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.SetStatus(ctx, id, models.Close, "fraud", "test"); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("close with a hold = %v, want failed_precondition", err)
+	}
+	if _, account, err := m.Capture(ctx, hold.ID, 0); err != nil || account.Amount != 40 || account.Held != 0 {
+		t.Fatalf("capture = %+v, %v; want 40 left and nothing held", account, err)
+	}
+
+	account, err := m.SetStatus(ctx, id, models.Close, "fraud", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Status != models.StatusClosed {
+		t.Fatalf("status = %s, want closed", account.Status)
+	}
+}
+
+// TestMemoryDeleteWithActiveHolds: удалённый аккаунт очищается вместе с холдами,
+// поэтому зарезервированные деньги пропали бы без списания или снятия.
+func TestMemoryDeleteWithActiveHolds(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	id := fillMemory(t, m, 1, 100)[0]
+	hold, err := m.Authorize(ctx, id, 60, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Delete(ctx, id, "test"); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("delete with a hold = %v, want failed_precondition", err)
+	}
+	if _, err := m.Release(ctx, hold.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete(ctx, id, "test"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkAccount сверяет баланс и сумму холдов аккаунта, прочитанного через Get.
+func checkAccount(t *testing.T, m *Memory, id string, amount, held int) {
+	t.Helper()
+	account, err := m.Get(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Amount != amount || account.Held != held || account.Available() != amount-held {
+		t.Fatalf("account has amount %d, held %d, available %d; want %d, %d, %d",
+			account.Amount, account.Held, account.Available(), amount, held, amount-held)
+	}
+}
+
+func TestMemoryAuthorize(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	id := fillMemory(t, m, 1, 100)[0]
+
+	if _, err := m.Authorize(ctx, id, 60, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	checkAccount(t, m, id, 100, 60)
+
+	// Второй холд сверх доступного остатка отклоняется, хотя баланс его покрывает.
+	if _, err := m.Authorize(ctx, id, 41, time.Hour); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("authorize over the available balance = %v, want failed_precondition", err)
+	}
+	if _, err := m.Authorize(ctx, id, 40, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	checkAccount(t, m, id, 100, 100)
+
+	// Холды закрывают и прямое списание: баланс нельзя опустить ниже зарезервированного.
+	if _, err := m.ChangeAmount(ctx, id, 99); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("debit of held funds = %v, want failed_precondition", err)
+	}
+}
+
+func TestMemoryCapture(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	id := fillMemory(t, m, 1, 100)[0]
+	hold, err := m.Authorize(ctx, id, 60, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := m.Capture(ctx, hold.ID, 61); !errors.Is(err, errs.ErrInvalidArgument) {
+		t.Fatalf("capture over the hold = %v, want invalid_argument", err)
+	}
+	// Частичное списание закрывает холд и освобождает остаток.
+	captured, account, err := m.Capture(ctx, hold.ID, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if captured.Status != models.HoldCaptured || captured.Captured != 25 || account.Amount != 75 || account.Held != 0 {
+		t.Fatalf("capture = %+v, %+v; want 25 captured and 75 left", captured, account)
+	}
+	checkAccount(t, m, id, 75, 0)
+
+	if _, _, err := m.Capture(ctx, hold.ID, 10); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("second capture = %v, want failed_precondition", err)
+	}
+	if _, err := m.Release(ctx, hold.ID); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("release after capture = %v, want failed_precondition", err)
+	}
+	checkAccount(t, m, id, 75, 0)
+	if got, err := m.Hold(ctx, hold.ID); err != nil || got.Status != models.HoldCaptured || got.Captured != 25 {
+		t.Fatalf("hold = %+v, %v; want captured 25", got, err)
+	}
+}
+
+func TestMemoryRelease(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	id := fillMemory(t, m, 1, 100)[0]
+	hold, err := m.Authorize(ctx, id, 60, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	released, err := m.Release(ctx, hold.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released.Status != models.HoldReleased {
+		t.Fatalf("released hold is %s", released.Status)
+	}
+	checkAccount(t, m, id, 100, 0)
+	if _, _, err := m.Capture(ctx, hold.ID, 0); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("capture after release = %v, want failed_precondition", err)
+	}
+}
+
+func TestMemoryExpireHolds(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	id := fillMemory(t, m, 1, 100)[0]
+	short, err := m.Authorize(ctx, id, 30, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long, err := m.Authorize(ctx, id, 50, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Истёкший холд перестаёт резервировать средства сразу, ещё до прохода ExpireHolds.
+	time.Sleep(5 * time.Millisecond)
+	checkAccount(t, m, id, 100, 50)
+	if _, _, err := m.Capture(ctx, short.ID, 0); !errors.Is(err, errs.ErrFailedPrecondition) {
+		t.Fatalf("capture of an expired hold = %v, want failed_precondition", err)
+	}
+
+	if n, err := m.ExpireHolds(ctx, time.Now()); err != nil || n != 1 {
+		t.Fatalf("expire = %d, %v; want 1", n, err)
+	}
+	if n, err := m.ExpireHolds(ctx, long.ExpiresAt); err != nil || n != 1 {
+		t.Fatalf("expire at the deadline = %d, %v; want 1", n, err)
+	}
+	if n, err := m.ExpireHolds(ctx, long.ExpiresAt.Add(time.Hour)); err != nil || n != 0 {
+		t.Fatalf("second expire = %d, %v; want 0", n, err)
+	}
+	holds, err := m.Holds(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hold := range holds {
+		if hold.Status != models.HoldExpired || hold.Captured != 0 {
+			t.Fatalf("hold after expiry = %+v, want expired", hold)
+		}
+	}
+	checkAccount(t, m, id, 100, 0)
+}
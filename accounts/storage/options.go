@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"awesomeProject/accounts/models"
+	"time"
+)
+
+// ReadOption настраивает чтение аккаунтов.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	includeDeleted bool
+	followRenames  time.Duration
+	selector       models.Selector
+}
+
+// IncludeDeleted показывает удалённые, но ещё не очищенные аккаунты.
+func IncludeDeleted() ReadOption {
+	return func(o *readOptions) {
+		o.includeDeleted = true
+	}
+}
+
+// FollowRenames учитывается только в Get: имя, которое аккаунт носил до переименования не раньше
+// within назад, находит этот аккаунт, если имя с тех пор никому не досталось.
+// Перенаправление видно по результату: ref не совпадает ни с ID, ни с именем аккаунта.
+func FollowRenames(within time.Duration) ReadOption {
+	return func(o *readOptions) {
+		o.followRenames = within
+	}
+}
+
+// MatchLabels оставляет только аккаунты, метки которых удовлетворяют селектору;
+// Get не находит аккаунт, который ему не удовлетворяет.
+func MatchLabels(selector models.Selector) ReadOption {
+	return func(o *readOptions) {
+		o.selector = selector
+	}
+}
+
+func readOptionsOf(opts []ReadOption) readOptions {
+	var o readOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// visible сообщает, нужно ли показывать аккаунт при этих опциях.
+func (o readOptions) visible(account models.Account) bool {
+	return (!account.Deleted() || o.includeDeleted) && o.selector.Matches(account.Labels)
+}
@@ -0,0 +1,1588 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed schema.sql
+var schema string
+
+// OpenPostgres подключается к базе и создаёт таблицы, если их ещё нет.
+func OpenPostgres(ctx context.Context, connectionString string) (*Postgres, error) {
+	db, err := sql.Open("pgx", connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to apply schema: %w", err)
+	}
+
+	return NewPostgres(db), nil
+}
+
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+// Postgres хранит аккаунты в таблице accounts.
+type Postgres struct {
+	db *sql.DB
+}
+
+func (p *Postgres) Close() error {
+	return p.db.Close()
+}
+
+// accountColumns — столбцы, которые читает scanAccount. Held считается подзапросом по активным холдам,
+// поэтому таблица accounts в запросе не должна иметь псевдонима.
+const accountColumns = "id, name, amount, status, deleted_at, deleted_by, created_at, updated_at, description, labels, " +
+	"(SELECT COALESCE(SUM(h.amount), 0) FROM account_holds h WHERE h.account_id = accounts.id AND h.status = 'active' AND h.expires_at > now())"
+
+// byRef выбирает аккаунт по ссылке из параметров refArgs: $1 — ID или NULL, $2 — имя.
+// refFirst ставит совпадение по ID раньше совпадения по имени, как в Memory.
+const (
+	byRef    = "(id = $1 OR name = $2)"
+	refFirst = " ORDER BY id = $1 DESC NULLS LAST LIMIT 1"
+)
+
+// refArgs — параметры $1 и $2 условия byRef, за ними идут остальные.
+func refArgs(ref string, args ...any) []any {
+	var id any
+	if parsed, ok := models.ParseID(ref); ok {
+		id = parsed
+	}
+
+	return append([]any{id, ref}, args...)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAccount(row scanner) (models.Account, error) {
+	account := models.Account{}
+	var deletedAt sql.NullTime
+	var deletedBy sql.NullString
+	var labels []byte
+	err := row.Scan(&account.ID, &account.Name, &account.Amount, &account.Status, &deletedAt, &deletedBy,
+		&account.CreatedAt, &account.UpdatedAt, &account.Description, &labels, &account.Held)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if deletedAt.Valid {
+		account.DeletedAt = deletedAt.Time.UTC()
+	}
+	account.DeletedBy = deletedBy.String
+	account.CreatedAt = account.CreatedAt.UTC()
+	account.UpdatedAt = account.UpdatedAt.UTC()
+	if err := json.Unmarshal(labels, &account.Labels); err != nil {
+		return models.Account{}, fmt.Errorf("failed to decode labels: %w", err)
+	}
+	if len(account.Labels) == 0 {
+		account.Labels = nil
+	}
+
+	return account, nil
+}
+
+// labelsJSON кодирует метки для столбца labels; nil записывается как пустой объект.
+func labelsJSON(labels map[string]string) []byte {
+	if labels == nil {
+		return []byte("{}")
+	}
+	encoded, _ := json.Marshal(labels)
+
+	return encoded
+}
+
+// selectorSQL переводит селектор в условие на столбец labels; параметры нумеруются после args.
+func selectorSQL(selector models.Selector, args []any) (string, []any) {
+	conditions := []string{"TRUE"}
+	param := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	for _, r := range selector {
+		switch r.Op {
+		case models.OpEquals:
+			conditions = append(conditions, "labels @> jsonb_build_object("+param(r.Key)+"::text, "+param(r.Value)+"::text)")
+		case models.OpNotEquals:
+			conditions = append(conditions, "NOT labels @> jsonb_build_object("+param(r.Key)+"::text, "+param(r.Value)+"::text)")
+		case models.OpExists:
+			conditions = append(conditions, "labels ? "+param(r.Key))
+		case models.OpNotExists:
+			conditions = append(conditions, "NOT labels ? "+param(r.Key))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+func (p *Postgres) Get(ctx context.Context, ref string, opts ...ReadOption) (models.Account, error) {
+	o := readOptionsOf(opts)
+	row := p.db.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE "+byRef+" AND ($3 OR deleted_at IS NULL)"+refFirst,
+		refArgs(ref, o.includeDeleted)...)
+
+	account, err := scanAccount(row)
+	if errors.Is(err, sql.ErrNoRows) && o.followRenames > 0 {
+		account, err = p.renamed(ctx, ref, o)
+	}
+	if err == nil && !o.selector.Matches(account.Labels) {
+		err = sql.ErrNoRows
+	}
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Account{}, errs.AccountNotFound(ref)
+	case err != nil:
+		return models.Account{}, fmt.Errorf("failed to get account: %w", err)
+	default:
+		return account, nil
+	}
+}
+
+// renamed ищет аккаунт, который последним освободил имя name переименованием не раньше o.followRenames назад.
+// Занятое имя принадлежит своему нынешнему владельцу, даже удалённому.
+func (p *Postgres) renamed(ctx context.Context, name string, o readOptions) (models.Account, error) {
+	row := p.db.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE id = "+
+		"(SELECT account_id FROM account_renames WHERE old_name = $1 AND created_at > $2 ORDER BY id DESC LIMIT 1)"+
+		" AND ($3 OR deleted_at IS NULL) AND NOT EXISTS (SELECT 1 FROM accounts WHERE name = $1)",
+		name, time.Now().Add(-o.followRenames), o.includeDeleted)
+
+	return scanAccount(row)
+}
+
+func (p *Postgres) List(ctx context.Context, opts ...ReadOption) ([]models.Account, error) {
+	accounts := make([]models.Account, 0)
+	err := p.scanAll(ctx, p.db, readOptionsOf(opts), func(account models.Account) error {
+		accounts = append(accounts, account)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// Export читает в транзакции REPEATABLE READ, поэтому видит таблицу на момент первого запроса
+// и не держит блокировок, мешающих писателям.
+func (p *Postgres) Export(ctx context.Context, fn func(models.Account) error, opts ...ReadOption) error {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	return p.scanAll(ctx, tx, readOptionsOf(opts), fn)
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (p *Postgres) scanAll(ctx context.Context, q querier, o readOptions, fn func(models.Account) error) error {
+	matches, args := selectorSQL(o.selector, []any{o.includeDeleted})
+	rows, err := q.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE ($1 OR deleted_at IS NULL) AND "+matches+" ORDER BY name", args...)
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan account: %w", err)
+		}
+		if err := fn(account); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return nil
+}
+
+// taken возвращает ошибку, если имя занято живым или удалённым аккаунтом.
+func taken(ctx context.Context, tx *sql.Tx, name string) error {
+	var deletedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, "SELECT deleted_at FROM accounts WHERE name=$1", name).Scan(&deletedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to check account name: %w", err)
+	case deletedAt.Valid:
+		return errs.AccountDeleted(name)
+	default:
+		return errs.AccountAlreadyExists(name)
+	}
+}
+
+// lockAccount читает аккаунт по ссылке и блокирует его строку до конца транзакции.
+// filter — условие на состояние, например «deleted_at IS NULL» для живых аккаунтов.
+func lockAccount(ctx context.Context, tx *sql.Tx, ref, filter string) (models.Account, error) {
+	row := tx.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE "+byRef+" AND "+filter+refFirst+" FOR UPDATE", refArgs(ref)...)
+	account, err := scanAccount(row)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Account{}, errs.AccountNotFound(ref)
+	case err != nil:
+		return models.Account{}, fmt.Errorf("failed to lock account: %w", err)
+	default:
+		return account, nil
+	}
+}
+
+// Фильтры lockAccount.
+const (
+	live    = "deleted_at IS NULL"
+	deleted = "deleted_at IS NOT NULL"
+)
+
+func (p *Postgres) Create(ctx context.Context, account models.Account) (models.Account, error) {
+	account.ID = models.NewID()
+	account.Status = initialStatus(account.Status)
+	account.CreatedAt = time.Now().UTC()
+	account.UpdatedAt = account.CreatedAt
+
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		if err := taken(ctx, tx, account.Name); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, "INSERT INTO accounts(id, name, amount, status, created_at, updated_at, description, labels) VALUES($1, $2, $3, $4, $5, $6, $7, $8)",
+			account.ID, account.Name, account.Amount, account.Status, account.CreatedAt, account.UpdatedAt, account.Description, labelsJSON(account.Labels))
+		switch {
+		case isUniqueViolation(err):
+			return errs.AccountAlreadyExists(account.Name)
+		case err != nil:
+			return fmt.Errorf("failed to insert account: %w", err)
+		}
+
+		return record(ctx, tx, models.EventAccountCreated, account)
+	})
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return account, nil
+}
+
+func (p *Postgres) ChangeAmount(ctx context.Context, ref string, amount int) (models.Account, error) {
+	var account models.Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if account, err = lockAccount(ctx, tx, ref, live); err != nil {
+			return err
+		}
+		if err := checkAmount(account, amount); err != nil {
+			return err
+		}
+
+		account.Amount = amount
+		touch(&account)
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET amount = $1, updated_at = $2 WHERE id = $3", amount, account.UpdatedAt, account.ID); err != nil {
+			return fmt.Errorf("failed to change amount: %w", err)
+		}
+
+		return record(ctx, tx, models.EventBalanceChanged, account)
+	})
+
+	return account, err
+}
+
+func (p *Postgres) ChangeName(ctx context.Context, ref, newName string) (models.Account, error) {
+	var account models.Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if account, err = lockAccount(ctx, tx, ref, live); err != nil {
+			return err
+		}
+		if err := checkAction(account, models.ActionRename); err != nil {
+			return err
+		}
+		if account.Name == newName {
+			return nil
+		}
+		if err := taken(ctx, tx, newName); err != nil {
+			return err
+		}
+
+		at := time.Now().UTC()
+		_, err = tx.ExecContext(ctx, "UPDATE accounts SET name = $1, updated_at = $2 WHERE id = $3", newName, at, account.ID)
+		switch {
+		case isUniqueViolation(err):
+			return errs.AccountAlreadyExists(newName)
+		case err != nil:
+			return fmt.Errorf("failed to change name: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO account_renames(account_id, old_name, new_name, created_at) VALUES($1, $2, $3, $4)",
+			account.ID, account.Name, newName, at)
+		if err != nil {
+			return fmt.Errorf("failed to record rename: %w", err)
+		}
+		account.Name = newName
+		account.UpdatedAt = at
+
+		return record(ctx, tx, models.EventAccountRenamed, account)
+	})
+
+	return account, err
+}
+
+func (p *Postgres) ChangeMetadata(ctx context.Context, ref string, change models.MetadataChange) (models.Account, error) {
+	var account models.Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if account, err = lockAccount(ctx, tx, ref, live); err != nil {
+			return err
+		}
+		if err := annotate(&account, change); err != nil {
+			return err
+		}
+
+		touch(&account)
+		_, err = tx.ExecContext(ctx, "UPDATE accounts SET description = $1, labels = $2, updated_at = $3 WHERE id = $4",
+			account.Description, labelsJSON(account.Labels), account.UpdatedAt, account.ID)
+		if err != nil {
+			return fmt.Errorf("failed to change metadata: %w", err)
+		}
+
+		return record(ctx, tx, models.EventAccountUpdated, account)
+	})
+
+	return account, err
+}
+
+func (p *Postgres) Delete(ctx context.Context, ref, actor string) error {
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		account, err := lockAccount(ctx, tx, ref, live)
+		if err != nil {
+			return err
+		}
+		if err := checkDelete(account); err != nil {
+			return err
+		}
+
+		account.DeletedAt = time.Now().UTC()
+		account.DeletedBy = actor
+		account.UpdatedAt = account.DeletedAt
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET deleted_at = $2, deleted_by = $3, updated_at = $2 WHERE id = $1", account.ID, account.DeletedAt, actor); err != nil {
+			return fmt.Errorf("failed to delete account: %w", err)
+		}
+
+		return record(ctx, tx, models.EventAccountDeleted, account)
+	})
+}
+
+func (p *Postgres) Restore(ctx context.Context, ref string) (models.Account, error) {
+	var account models.Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		account, err = lockAccount(ctx, tx, ref, deleted)
+		if errors.Is(err, errs.ErrNotFound) {
+			return errs.DeletedAccountNotFound(ref)
+		}
+		if err != nil {
+			return err
+		}
+
+		account.DeletedAt = time.Time{}
+		account.DeletedBy = ""
+		touch(&account)
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET deleted_at = NULL, deleted_by = NULL, updated_at = $1 WHERE id = $2", account.UpdatedAt, account.ID); err != nil {
+			return fmt.Errorf("failed to restore account: %w", err)
+		}
+
+		return record(ctx, tx, models.EventAccountRestored, account)
+	})
+
+	return account, err
+}
+
+// Purge удаляет и истории статусов и переименований: их таблицы ссылаются на accounts с ON DELETE CASCADE.
+func (p *Postgres) Purge(ctx context.Context, before time.Time) (int, error) {
+	result, err := p.db.ExecContext(ctx, "DELETE FROM accounts WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge accounts: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge accounts: %w", err)
+	}
+
+	return int(purged), nil
+}
+
+func (p *Postgres) SetStatus(ctx context.Context, ref string, change models.Change, reason, actor string) (models.Account, error) {
+	var account models.Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if account, err = lockAccount(ctx, tx, ref, live); err != nil {
+			return err
+		}
+		t, err := transition(account, change, reason, actor)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET status = $1, updated_at = $2 WHERE id = $3", change.To, t.At, account.ID); err != nil {
+			return fmt.Errorf("failed to change status: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO account_transitions(account_id, from_status, to_status, reason, actor, created_at) VALUES($1, $2, $3, $4, $5, $6)",
+			account.ID, t.From, t.To, t.Reason, t.Actor, t.At)
+		if err != nil {
+			return fmt.Errorf("failed to record status transition: %w", err)
+		}
+		account.Status = change.To
+		account.UpdatedAt = t.At
+
+		return record(ctx, tx, models.EventStatusChanged, account)
+	})
+
+	return account, err
+}
+
+// accountID находит ID аккаунта по ссылке, включая удалённые аккаунты.
+func (p *Postgres) accountID(ctx context.Context, ref string) (string, error) {
+	var id string
+	err := p.db.QueryRowContext(ctx, "SELECT id FROM accounts WHERE "+byRef+refFirst, refArgs(ref)...).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", errs.AccountNotFound(ref)
+	case err != nil:
+		return "", fmt.Errorf("failed to get account: %w", err)
+	default:
+		return id, nil
+	}
+}
+
+// StatusHistory доступна и для удалённых аккаунтов, пока они не очищены.
+func (p *Postgres) StatusHistory(ctx context.Context, ref string) ([]models.Transition, error) {
+	id, err := p.accountID(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT from_status, to_status, reason, actor, created_at FROM account_transitions WHERE account_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status transitions: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]models.Transition, 0)
+	for rows.Next() {
+		var t models.Transition
+		if err := rows.Scan(&t.From, &t.To, &t.Reason, &t.Actor, &t.At); err != nil {
+			return nil, fmt.Errorf("failed to scan status transition: %w", err)
+		}
+		t.At = t.At.UTC()
+		history = append(history, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list status transitions: %w", err)
+	}
+
+	return history, nil
+}
+
+// RenameHistory доступна и для удалённых аккаунтов, пока они не очищены.
+func (p *Postgres) RenameHistory(ctx context.Context, ref string) ([]models.Rename, error) {
+	id, err := p.accountID(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT old_name, new_name, created_at FROM account_renames WHERE account_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list renames: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]models.Rename, 0)
+	for rows.Next() {
+		var r models.Rename
+		if err := rows.Scan(&r.From, &r.To, &r.At); err != nil {
+			return nil, fmt.Errorf("failed to scan rename: %w", err)
+		}
+		r.At = r.At.UTC()
+		history = append(history, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list renames: %w", err)
+	}
+
+	return history, nil
+}
+
+// Transfer блокирует обе строки в порядке ID, чтобы встречные переводы не взаимоблокировались.
+func (p *Postgres) Transfer(ctx context.Context, from, to string, amount int) (models.Account, models.Account, error) {
+	if err := checkTransfer(from, to, amount); err != nil {
+		return models.Account{}, models.Account{}, err
+	}
+
+	var source, target models.Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		source, target, err = transfer(ctx, tx, from, to, amount)
+
+		return err
+	})
+	if err != nil {
+		return models.Account{}, models.Account{}, err
+	}
+
+	return source, target, nil
+}
+
+// transfer переводит деньги внутри транзакции tx. Все проверки идут до первой записи,
+// так что после отказа перевода транзакцию можно продолжать.
+func transfer(ctx context.Context, tx *sql.Tx, from, to string, amount int) (models.Account, models.Account, error) {
+	var ids []string
+	for _, ref := range []string{from, to} {
+		if id, ok := models.ParseID(ref); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE (id = ANY($1) OR name = ANY($2)) AND deleted_at IS NULL ORDER BY id FOR UPDATE",
+		ids, []string{from, to})
+	if err != nil {
+		return models.Account{}, models.Account{}, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+	var locked []models.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			rows.Close()
+			return models.Account{}, models.Account{}, fmt.Errorf("failed to scan account: %w", err)
+		}
+		locked = append(locked, account)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.Account{}, models.Account{}, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	source, ok := pick(locked, from)
+	if !ok {
+		return models.Account{}, models.Account{}, errs.AccountNotFound(from)
+	}
+	target, ok := pick(locked, to)
+	if !ok {
+		return models.Account{}, models.Account{}, errs.AccountNotFound(to)
+	}
+	if source.ID == target.ID {
+		return models.Account{}, models.Account{}, errs.InvalidField("to", "must differ from the source account")
+	}
+	if err := applyTransfer(&source, &target, amount); err != nil {
+		return models.Account{}, models.Account{}, err
+	}
+
+	for _, account := range []models.Account{source, target} {
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET amount = $1, updated_at = $2 WHERE id = $3", account.Amount, account.UpdatedAt, account.ID); err != nil {
+			return models.Account{}, models.Account{}, fmt.Errorf("failed to change amount: %w", err)
+		}
+	}
+	if err := record(ctx, tx, models.EventBalanceChanged, source, target); err != nil {
+		return models.Account{}, models.Account{}, err
+	}
+
+	return source, target, nil
+}
+
+// pick выбирает аккаунт по ссылке, предпочитая совпадение по ID.
+func pick(accounts []models.Account, ref string) (models.Account, bool) {
+	id, isID := models.ParseID(ref)
+	for _, account := range accounts {
+		if isID && account.ID == id {
+			return account, true
+		}
+	}
+	for _, account := range accounts {
+		if account.Name == ref {
+			return account, true
+		}
+	}
+
+	return models.Account{}, false
+}
+
+// holdColumns — столбцы, которые читает scanHold.
+const holdColumns = "id, account_id, amount, status, captured, created_at, expires_at, closed_at"
+
+func scanHold(row scanner) (models.Hold, error) {
+	var h models.Hold
+	var closedAt sql.NullTime
+	if err := row.Scan(&h.ID, &h.AccountID, &h.Amount, &h.Status, &h.Captured, &h.CreatedAt, &h.ExpiresAt, &closedAt); err != nil {
+		return models.Hold{}, err
+	}
+	h.CreatedAt = h.CreatedAt.UTC()
+	h.ExpiresAt = h.ExpiresAt.UTC()
+	if closedAt.Valid {
+		h.ClosedAt = closedAt.Time.UTC()
+	}
+
+	return h.At(time.Now()), nil
+}
+
+func (p *Postgres) Authorize(ctx context.Context, ref string, amount int, ttl time.Duration) (models.Hold, error) {
+	var hold models.Hold
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		account, err := lockAccount(ctx, tx, ref, live)
+		if err != nil {
+			return err
+		}
+		if hold, err = authorize(account, amount, ttl); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO account_holds(id, account_id, amount, status, created_at, expires_at) VALUES($1, $2, $3, $4, $5, $6)",
+			hold.ID, hold.AccountID, hold.Amount, hold.Status, hold.CreatedAt, hold.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert hold: %w", err)
+		}
+
+		return nil
+	})
+
+	return hold, err
+}
+
+// lockHold блокирует строку аккаунта холда, а затем сам холд — в том же порядке, что и Authorize.
+func lockHold(ctx context.Context, tx *sql.Tx, holdID string) (models.Hold, models.Account, error) {
+	id, ok := models.ParseID(holdID)
+	if !ok {
+		return models.Hold{}, models.Account{}, errs.HoldNotFound(holdID)
+	}
+
+	var accountID string
+	err := tx.QueryRowContext(ctx, "SELECT account_id FROM account_holds WHERE id = $1", id).Scan(&accountID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Hold{}, models.Account{}, errs.HoldNotFound(holdID)
+	case err != nil:
+		return models.Hold{}, models.Account{}, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	account, err := lockAccount(ctx, tx, accountID, "TRUE")
+	if err != nil {
+		return models.Hold{}, models.Account{}, err
+	}
+	hold, err := scanHold(tx.QueryRowContext(ctx, "SELECT "+holdColumns+" FROM account_holds WHERE id = $1 FOR UPDATE", id))
+	if err != nil {
+		return models.Hold{}, models.Account{}, fmt.Errorf("failed to lock hold: %w", err)
+	}
+
+	return hold, account, nil
+}
+
+// closeHold записывает закрытый холд.
+func closeHold(ctx context.Context, tx *sql.Tx, hold models.Hold) error {
+	_, err := tx.ExecContext(ctx, "UPDATE account_holds SET status = $1, captured = $2, closed_at = $3 WHERE id = $4",
+		hold.Status, hold.Captured, hold.ClosedAt, hold.ID)
+	if err != nil {
+		return fmt.Errorf("failed to close hold: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) Capture(ctx context.Context, holdID string, amount int) (models.Hold, models.Account, error) {
+	var hold models.Hold
+	var account models.Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if hold, account, err = lockHold(ctx, tx, holdID); err != nil {
+			return err
+		}
+		if account.Deleted() {
+			return errs.AccountNotFound(account.Name)
+		}
+		if err := capture(&account, &hold, amount); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET amount = $1, updated_at = $2 WHERE id = $3", account.Amount, account.UpdatedAt, account.ID); err != nil {
+			return fmt.Errorf("failed to change amount: %w", err)
+		}
+		if err := record(ctx, tx, models.EventBalanceChanged, account); err != nil {
+			return err
+		}
+
+		return closeHold(ctx, tx, hold)
+	})
+
+	return hold, account, err
+}
+
+// Release снимает холд и у удалённого аккаунта: снятие только освобождает средства.
+func (p *Postgres) Release(ctx context.Context, holdID string) (models.Hold, error) {
+	var hold models.Hold
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if hold, _, err = lockHold(ctx, tx, holdID); err != nil {
+			return err
+		}
+		if err := release(&hold); err != nil {
+			return err
+		}
+
+		return closeHold(ctx, tx, hold)
+	})
+
+	return hold, err
+}
+
+func (p *Postgres) Hold(ctx context.Context, holdID string) (models.Hold, error) {
+	id, ok := models.ParseID(holdID)
+	if !ok {
+		return models.Hold{}, errs.HoldNotFound(holdID)
+	}
+
+	hold, err := scanHold(p.db.QueryRowContext(ctx, "SELECT "+holdColumns+" FROM account_holds WHERE id = $1", id))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Hold{}, errs.HoldNotFound(holdID)
+	case err != nil:
+		return models.Hold{}, fmt.Errorf("failed to get hold: %w", err)
+	default:
+		return hold, nil
+	}
+}
+
+// Holds доступны и для удалённых аккаунтов, пока они не очищены.
+func (p *Postgres) Holds(ctx context.Context, ref string) ([]models.Hold, error) {
+	id, err := p.accountID(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT "+holdColumns+" FROM account_holds WHERE account_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds := make([]models.Hold, 0)
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+func (p *Postgres) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	result, err := p.db.ExecContext(ctx, "UPDATE account_holds SET status = 'expired', closed_at = expires_at WHERE status = 'active' AND expires_at <= $1", now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire holds: %w", err)
+	}
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire holds: %w", err)
+	}
+
+	return int(expired), nil
+}
+
+// scheduleColumns — столбцы, которые читает scanSchedule.
+const scheduleColumns = "id, from_account, to_account, amount, cron, description, status, next_run_at, created_at, updated_at"
+
+func scanSchedule(row scanner) (models.Schedule, error) {
+	var s models.Schedule
+	var nextRunAt sql.NullTime
+	if err := row.Scan(&s.ID, &s.From, &s.To, &s.Amount, &s.Cron, &s.Description, &s.Status, &nextRunAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return models.Schedule{}, err
+	}
+	if nextRunAt.Valid {
+		s.NextRunAt = nextRunAt.Time.UTC()
+	}
+	s.CreatedAt = s.CreatedAt.UTC()
+	s.UpdatedAt = s.UpdatedAt.UTC()
+
+	return s, nil
+}
+
+// nullTime записывает нулевое время как NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (p *Postgres) CreateSchedule(ctx context.Context, schedule models.Schedule) (models.Schedule, error) {
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		from, err := lockAccount(ctx, tx, schedule.From, live)
+		if err != nil {
+			return err
+		}
+		to, err := lockAccount(ctx, tx, schedule.To, live)
+		if err != nil {
+			return err
+		}
+		schedule.From, schedule.To = from.ID, to.ID
+		if schedule, err = newSchedule(schedule, time.Now()); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO account_schedules("+scheduleColumns+") VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+			schedule.ID, schedule.From, schedule.To, schedule.Amount, schedule.Cron, schedule.Description, schedule.Status,
+			nullTime(schedule.NextRunAt), schedule.CreatedAt, schedule.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert schedule: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Schedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func (p *Postgres) Schedule(ctx context.Context, id string) (models.Schedule, error) {
+	return getSchedule(ctx, p.db, id, "")
+}
+
+// getSchedule читает расписание; lock — суффикс запроса, например « FOR UPDATE».
+func getSchedule(ctx context.Context, q querier, id, lock string) (models.Schedule, error) {
+	parsed, ok := models.ParseID(id)
+	if !ok {
+		return models.Schedule{}, errs.ScheduleNotFound(id)
+	}
+
+	schedule, err := scanSchedule(q.QueryRowContext(ctx, "SELECT "+scheduleColumns+" FROM account_schedules WHERE id = $1"+lock, parsed))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Schedule{}, errs.ScheduleNotFound(id)
+	case err != nil:
+		return models.Schedule{}, fmt.Errorf("failed to get schedule: %w", err)
+	default:
+		return schedule, nil
+	}
+}
+
+func (p *Postgres) Schedules(ctx context.Context, ref string) ([]models.Schedule, error) {
+	query, args := "SELECT "+scheduleColumns+" FROM account_schedules ORDER BY id", []any(nil)
+	if ref != "" {
+		id, err := p.accountID(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		query = "SELECT " + scheduleColumns + " FROM account_schedules WHERE from_account = $1 OR to_account = $1 ORDER BY id"
+		args = []any{id}
+	}
+
+	return p.schedules(ctx, query, args...)
+}
+
+func (p *Postgres) schedules(ctx context.Context, query string, args ...any) ([]models.Schedule, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]models.Schedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// updateSchedule записывает изменяемые поля расписания.
+func updateSchedule(ctx context.Context, tx *sql.Tx, schedule models.Schedule) error {
+	_, err := tx.ExecContext(ctx, "UPDATE account_schedules SET status = $1, next_run_at = $2, updated_at = $3 WHERE id = $4",
+		schedule.Status, nullTime(schedule.NextRunAt), schedule.UpdatedAt, schedule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) ChangeSchedule(ctx context.Context, id string, action models.ScheduleAction, now time.Time) (models.Schedule, error) {
+	var schedule models.Schedule
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if schedule, err = getSchedule(ctx, tx, id, " FOR UPDATE"); err != nil {
+			return err
+		}
+		if schedule, err = changeSchedule(schedule, action, now); err != nil {
+			return err
+		}
+
+		return updateSchedule(ctx, tx, schedule)
+	})
+
+	return schedule, err
+}
+
+func (p *Postgres) DueSchedules(ctx context.Context, now time.Time, limit int) ([]models.Schedule, error) {
+	return p.schedules(ctx, "SELECT "+scheduleColumns+" FROM account_schedules WHERE status = 'active' AND next_run_at <= $1 ORDER BY next_run_at, id LIMIT $2",
+		now, limit)
+}
+
+// RunSchedule блокирует расписание раньше аккаунтов, так что два планировщика не исполнят один срок дважды.
+func (p *Postgres) RunSchedule(ctx context.Context, id string, dueAt, now time.Time) (models.ScheduleRun, error) {
+	var run models.ScheduleRun
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		schedule, err := getSchedule(ctx, tx, id, " FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		run, err = scanScheduleRun(tx.QueryRowContext(ctx, "SELECT "+scheduleRunColumns+" FROM account_schedule_runs WHERE schedule_id = $1 AND due_at = $2",
+			schedule.ID, dueAt))
+		switch {
+		case err == nil:
+			return nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("failed to get schedule run: %w", err)
+		}
+		if err := checkDue(schedule, dueAt); err != nil {
+			return err
+		}
+
+		_, _, err = transfer(ctx, tx, schedule.From, schedule.To, schedule.Amount)
+		if run, err = finishRun(&schedule, dueAt, now, err); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO account_schedule_runs("+scheduleRunColumns+") VALUES($1, $2, $3, $4, $5)",
+			run.ScheduleID, run.DueAt, run.Status, run.Error, run.RanAt)
+		if err != nil {
+			return fmt.Errorf("failed to record schedule run: %w", err)
+		}
+
+		return updateSchedule(ctx, tx, schedule)
+	})
+	if err != nil {
+		return models.ScheduleRun{}, err
+	}
+
+	return run, nil
+}
+
+// scheduleRunColumns — столбцы, которые читает scanScheduleRun.
+const scheduleRunColumns = "schedule_id, due_at, status, error, ran_at"
+
+func scanScheduleRun(row scanner) (models.ScheduleRun, error) {
+	var r models.ScheduleRun
+	if err := row.Scan(&r.ScheduleID, &r.DueAt, &r.Status, &r.Error, &r.RanAt); err != nil {
+		return models.ScheduleRun{}, err
+	}
+	r.DueAt = r.DueAt.UTC()
+	r.RanAt = r.RanAt.UTC()
+
+	return r, nil
+}
+
+func (p *Postgres) ScheduleRuns(ctx context.Context, id string) ([]models.ScheduleRun, error) {
+	schedule, err := p.Schedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT "+scheduleRunColumns+" FROM account_schedule_runs WHERE schedule_id = $1 ORDER BY due_at", schedule.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]models.ScheduleRun, 0)
+	for rows.Next() {
+		run, err := scanScheduleRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schedule runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// accrualColumns — столбцы, которые читает scanAccrual.
+const accrualColumns = "id, compounding, period_start, status, last_account, postings, total, started_at, finished_at"
+
+func scanAccrual(row scanner) (models.Accrual, error) {
+	var a models.Accrual
+	var cursor sql.NullString
+	var finishedAt sql.NullTime
+	err := row.Scan(&a.ID, &a.Period.Compounding, &a.Period.Start, &a.Status, &cursor, &a.Postings, &a.Total, &a.StartedAt, &finishedAt)
+	if err != nil {
+		return models.Accrual{}, err
+	}
+	a.Period.Start = a.Period.Start.UTC()
+	a.Cursor = cursor.String
+	a.StartedAt = a.StartedAt.UTC()
+	if finishedAt.Valid {
+		a.FinishedAt = finishedAt.Time.UTC()
+	}
+
+	return a, nil
+}
+
+func (p *Postgres) BeginAccrual(ctx context.Context, period models.Period, now time.Time) (models.Accrual, error) {
+	accrual := newAccrual(period, now)
+	_, err := p.db.ExecContext(ctx, "INSERT INTO interest_accruals(id, compounding, period_start, status, started_at) VALUES($1, $2, $3, $4, $5) ON CONFLICT (id) DO NOTHING",
+		accrual.ID, accrual.Period.Compounding, accrual.Period.Start, accrual.Status, accrual.StartedAt)
+	if err != nil {
+		return models.Accrual{}, fmt.Errorf("failed to begin accrual: %w", err)
+	}
+
+	return p.Accrual(ctx, accrual.ID)
+}
+
+func (p *Postgres) Accrual(ctx context.Context, id string) (models.Accrual, error) {
+	return getAccrual(ctx, p.db, id, "")
+}
+
+// getAccrual читает пакет; lock — необязательный суффикс запроса, например « FOR UPDATE».
+func getAccrual(ctx context.Context, q querier, id, lock string) (models.Accrual, error) {
+	accrual, err := scanAccrual(q.QueryRowContext(ctx, "SELECT "+accrualColumns+" FROM interest_accruals WHERE id = $1"+lock, id))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Accrual{}, errs.AccrualNotFound(id)
+	case err != nil:
+		return models.Accrual{}, fmt.Errorf("failed to get accrual: %w", err)
+	default:
+		return accrual, nil
+	}
+}
+
+// updateAccrual записывает изменяемые поля пакета.
+func updateAccrual(ctx context.Context, tx *sql.Tx, accrual models.Accrual) error {
+	var cursor any
+	if accrual.Cursor != "" {
+		cursor = accrual.Cursor
+	}
+	_, err := tx.ExecContext(ctx, "UPDATE interest_accruals SET status = $1, last_account = $2, postings = $3, total = $4, finished_at = $5 WHERE id = $6",
+		accrual.Status, cursor, accrual.Postings, accrual.Total, nullTime(accrual.FinishedAt), accrual.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update accrual: %w", err)
+	}
+
+	return nil
+}
+
+// PostInterest блокирует пакет раньше аккаунта, как RunSchedule блокирует расписание.
+func (p *Postgres) PostInterest(ctx context.Context, accrualID, ref string, interest func(models.Account) int, now time.Time) (models.LedgerEntry, error) {
+	var entry models.LedgerEntry
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		accrual, err := getAccrual(ctx, tx, accrualID, " FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		account, err := lockAccount(ctx, tx, ref, live)
+		if err != nil {
+			return err
+		}
+		entry, err = scanLedgerEntry(tx.QueryRowContext(ctx, "SELECT "+ledgerColumns+" FROM account_ledger WHERE account_id = $1 AND reference = $2",
+			account.ID, accrual.ID))
+		switch {
+		case err == nil:
+			return nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("failed to get ledger entry: %w", err)
+		}
+
+		if entry, err = postInterest(&accrual, &account, interest(account), now); err != nil {
+			return err
+		}
+		if entry.ID != "" {
+			if _, err := tx.ExecContext(ctx, "UPDATE accounts SET amount = $1, updated_at = $2 WHERE id = $3", account.Amount, account.UpdatedAt, account.ID); err != nil {
+				return fmt.Errorf("failed to change amount: %w", err)
+			}
+			_, err = tx.ExecContext(ctx, "INSERT INTO account_ledger("+ledgerColumns+") VALUES($1, $2, $3, $4, $5, $6, $7)",
+				entry.ID, entry.AccountID, entry.Kind, entry.Amount, entry.Balance, entry.Reference, entry.PostedAt)
+			if err != nil {
+				return fmt.Errorf("failed to insert ledger entry: %w", err)
+			}
+			if err := record(ctx, tx, models.EventBalanceChanged, account); err != nil {
+				return err
+			}
+		}
+
+		return updateAccrual(ctx, tx, accrual)
+	})
+	if err != nil {
+		return models.LedgerEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (p *Postgres) FinishAccrual(ctx context.Context, id string, now time.Time) (models.Accrual, error) {
+	var accrual models.Accrual
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if accrual, err = getAccrual(ctx, tx, id, " FOR UPDATE"); err != nil {
+			return err
+		}
+		finishAccrual(&accrual, now)
+
+		return updateAccrual(ctx, tx, accrual)
+	})
+
+	return accrual, err
+}
+
+func (p *Postgres) Accruals(ctx context.Context) ([]models.Accrual, error) {
+	rows, err := p.db.QueryContext(ctx, "SELECT "+accrualColumns+" FROM interest_accruals ORDER BY period_start, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accruals: %w", err)
+	}
+	defer rows.Close()
+
+	accruals := make([]models.Accrual, 0)
+	for rows.Next() {
+		accrual, err := scanAccrual(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan accrual: %w", err)
+		}
+		accruals = append(accruals, accrual)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accruals: %w", err)
+	}
+
+	return accruals, nil
+}
+
+// ledgerColumns — столбцы, которые читает scanLedgerEntry.
+const ledgerColumns = "id, account_id, kind, amount, balance, reference, posted_at"
+
+func scanLedgerEntry(row scanner) (models.LedgerEntry, error) {
+	var e models.LedgerEntry
+	if err := row.Scan(&e.ID, &e.AccountID, &e.Kind, &e.Amount, &e.Balance, &e.Reference, &e.PostedAt); err != nil {
+		return models.LedgerEntry{}, err
+	}
+	e.PostedAt = e.PostedAt.UTC()
+
+	return e, nil
+}
+
+func (p *Postgres) Ledger(ctx context.Context, ref string) ([]models.LedgerEntry, error) {
+	accountID, err := p.accountID(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT "+ledgerColumns+" FROM account_ledger WHERE account_id = $1 ORDER BY posted_at, id", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.LedgerEntry, 0)
+	for rows.Next() {
+		entry, err := scanLedgerEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// subscriptionColumns — столбцы, которые читает scanSubscription.
+const subscriptionColumns = "id, url, secret, event_types, accounts, created_at"
+
+func scanSubscription(row scanner) (models.Subscription, error) {
+	var s models.Subscription
+	var eventTypes, accounts []byte
+	if err := row.Scan(&s.ID, &s.URL, &s.Secret, &eventTypes, &accounts, &s.CreatedAt); err != nil {
+		return models.Subscription{}, err
+	}
+	if err := json.Unmarshal(eventTypes, &s.EventTypes); err != nil {
+		return models.Subscription{}, fmt.Errorf("failed to decode event types: %w", err)
+	}
+	if err := json.Unmarshal(accounts, &s.Accounts); err != nil {
+		return models.Subscription{}, fmt.Errorf("failed to decode accounts: %w", err)
+	}
+	if len(s.EventTypes) == 0 {
+		s.EventTypes = nil
+	}
+	if len(s.Accounts) == 0 {
+		s.Accounts = nil
+	}
+	s.CreatedAt = s.CreatedAt.UTC()
+
+	return s, nil
+}
+
+// listJSON кодирует список для JSON столбца; nil записывается как пустой массив.
+func listJSON[T any](list []T) []byte {
+	if len(list) == 0 {
+		return []byte("[]")
+	}
+	encoded, _ := json.Marshal(list)
+
+	return encoded
+}
+
+func (p *Postgres) CreateSubscription(ctx context.Context, subscription models.Subscription) (models.Subscription, error) {
+	accounts := make([]string, 0, len(subscription.Accounts))
+	for _, ref := range subscription.Accounts {
+		account, err := p.Get(ctx, ref)
+		if err != nil {
+			return models.Subscription{}, err
+		}
+		accounts = append(accounts, account.ID)
+	}
+	subscription.Accounts = accounts
+	subscription = newSubscription(subscription)
+
+	_, err := p.db.ExecContext(ctx, "INSERT INTO webhook_subscriptions("+subscriptionColumns+") VALUES($1, $2, $3, $4, $5, $6)",
+		subscription.ID, subscription.URL, subscription.Secret, listJSON(subscription.EventTypes), listJSON(subscription.Accounts), subscription.CreatedAt)
+	if err != nil {
+		return models.Subscription{}, fmt.Errorf("failed to insert subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (p *Postgres) Subscription(ctx context.Context, id string) (models.Subscription, error) {
+	parsed, ok := models.ParseID(id)
+	if !ok {
+		return models.Subscription{}, errs.SubscriptionNotFound(id)
+	}
+
+	subscription, err := scanSubscription(p.db.QueryRowContext(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE id = $1", parsed))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Subscription{}, errs.SubscriptionNotFound(id)
+	case err != nil:
+		return models.Subscription{}, fmt.Errorf("failed to get subscription: %w", err)
+	default:
+		return subscription, nil
+	}
+}
+
+func (p *Postgres) Subscriptions(ctx context.Context) ([]models.Subscription, error) {
+	return subscriptions(ctx, p.db)
+}
+
+func subscriptions(ctx context.Context, q querier) ([]models.Subscription, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]models.Subscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (p *Postgres) DeleteSubscription(ctx context.Context, id string) error {
+	parsed, ok := models.ParseID(id)
+	if !ok {
+		return errs.SubscriptionNotFound(id)
+	}
+
+	result, err := p.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", parsed)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	} else if deleted == 0 {
+		return errs.SubscriptionNotFound(id)
+	}
+
+	return nil
+}
+
+// deliveryColumns — столбцы, которые читает scanDelivery.
+const deliveryColumns = "id, subscription_id, event_id, event_type, account_id, payload, status, attempts, next_attempt_at, " +
+	"last_error, last_status_code, created_at, delivered_at"
+
+func scanDelivery(row scanner) (models.Delivery, error) {
+	var d models.Delivery
+	var deliveredAt sql.NullTime
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.AccountID, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastError, &d.LastStatusCode, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return models.Delivery{}, err
+	}
+	d.NextAttemptAt = d.NextAttemptAt.UTC()
+	d.CreatedAt = d.CreatedAt.UTC()
+	if deliveredAt.Valid {
+		d.DeliveredAt = deliveredAt.Time.UTC()
+	}
+
+	return d, nil
+}
+
+// EnqueueEvent читает подписки и заводит доставки в одной транзакции, так что подписка,
+// удалённая параллельно, не получит доставки.
+func (p *Postgres) EnqueueEvent(ctx context.Context, event models.Event, payload []byte) ([]models.Delivery, error) {
+	var deliveries []models.Delivery
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var enqueued bool
+		if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM webhook_deliveries WHERE event_id = $1)", event.ID).Scan(&enqueued); err != nil {
+			return fmt.Errorf("failed to check event deliveries: %w", err)
+		}
+		if enqueued {
+			deliveries = nil
+			return nil
+		}
+		subscriptions, err := subscriptions(ctx, tx)
+		if err != nil {
+			return err
+		}
+		deliveries = newDeliveries(subscriptions, event, payload)
+		for _, d := range deliveries {
+			_, err := tx.ExecContext(ctx, "INSERT INTO webhook_deliveries("+deliveryColumns+") VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+				d.ID, d.SubscriptionID, d.EventID, d.EventType, d.AccountID, d.Payload, d.Status, d.Attempts,
+				d.NextAttemptAt, d.LastError, d.LastStatusCode, d.CreatedAt, nullTime(d.DeliveredAt))
+			if err != nil {
+				return fmt.Errorf("failed to insert delivery: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (p *Postgres) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error) {
+	return p.deliveries(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= $1 ORDER BY next_attempt_at, id LIMIT $2",
+		now, limit)
+}
+
+func (p *Postgres) deliveries(ctx context.Context, query string, args ...any) ([]models.Delivery, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.Delivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (p *Postgres) UpdateDelivery(ctx context.Context, delivery models.Delivery) error {
+	result, err := p.db.ExecContext(ctx, "UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, "+
+		"last_status_code = $5, delivered_at = $6 WHERE id = $7",
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError, delivery.LastStatusCode, nullTime(delivery.DeliveredAt), delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	} else if updated == 0 {
+		return errs.DeliveryNotFound(delivery.ID)
+	}
+
+	return nil
+}
+
+func (p *Postgres) Deliveries(ctx context.Context, subscriptionID string, status models.DeliveryStatus) ([]models.Delivery, error) {
+	subscription, err := p.Subscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return p.deliveries(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE subscription_id = $1 ORDER BY id", subscription.ID)
+	}
+
+	return p.deliveries(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE subscription_id = $1 AND status = $2 ORDER BY id",
+		subscription.ID, status)
+}
+
+func (p *Postgres) ReplayDelivery(ctx context.Context, subscriptionID, id string, now time.Time) (models.Delivery, error) {
+	parsed, ok := models.ParseID(id)
+	subscription, valid := models.ParseID(subscriptionID)
+	if !ok || !valid {
+		return models.Delivery{}, errs.DeliveryNotFound(id)
+	}
+
+	var delivery models.Delivery
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		delivery, err = scanDelivery(tx.QueryRowContext(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = $1 AND subscription_id = $2 FOR UPDATE",
+			parsed, subscription))
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return errs.DeliveryNotFound(id)
+		case err != nil:
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+		replayDelivery(&delivery, now)
+
+		_, err = tx.ExecContext(ctx, "UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, delivered_at = NULL WHERE id = $4",
+			delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.ID)
+		if err != nil {
+			return fmt.Errorf("failed to replay delivery: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Delivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// outboxLock — ключ advisory-блокировки, которая выстраивает записи в outbox в одну очередь.
+const outboxLock = 0x6f7574626f78
+
+// record пишет события об аккаунтах в outbox в транзакции tx: они сохраняются вместе с изменением или не сохраняются вовсе.
+// Блокировка outboxLock держится до конца транзакции, поэтому следующая транзакция получает sequence
+// только после коммита предыдущей: номера растут в порядке коммитов, и видимое событие
+// никогда не обгоняет событие, ещё не закоммиченное.
+func record(ctx context.Context, tx *sql.Tx, eventType models.EventType, accounts ...models.Account) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", outboxLock); err != nil {
+		return fmt.Errorf("failed to lock outbox: %w", err)
+	}
+	for _, account := range accounts {
+		event := models.NewEvent(eventType, account)
+		encoded, err := json.Marshal(event.Account)
+		if err != nil {
+			return fmt.Errorf("failed to encode event account: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO account_outbox(id, type, account, occurred_at) VALUES($1, $2, $3, $4)",
+			event.ID, event.Type, encoded, event.OccurredAt)
+		if err != nil {
+			return fmt.Errorf("failed to record %s event: %w", eventType, err)
+		}
+	}
+
+	return nil
+}
+
+// eventColumns — столбцы, которые читает scanEvent.
+const eventColumns = "id, sequence, type, account, occurred_at"
+
+func scanEvent(row scanner) (models.Event, error) {
+	var event models.Event
+	var account []byte
+	if err := row.Scan(&event.ID, &event.Sequence, &event.Type, &account, &event.OccurredAt); err != nil {
+		return models.Event{}, err
+	}
+	if err := json.Unmarshal(account, &event.Account); err != nil {
+		return models.Event{}, fmt.Errorf("failed to decode event account: %w", err)
+	}
+	event.OccurredAt = event.OccurredAt.UTC()
+
+	return event, nil
+}
+
+// PendingEvents читает события по Sequence; record выдаёт номера в порядке коммитов,
+// так что событие с меньшим номером не может появиться после уже прочитанного.
+func (p *Postgres) PendingEvents(ctx context.Context, limit int) ([]models.Event, error) {
+	rows, err := p.db.QueryContext(ctx, "SELECT "+eventColumns+" FROM account_outbox WHERE delivered_at IS NULL ORDER BY sequence LIMIT $1", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]models.Event, 0)
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list pending events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (p *Postgres) MarkDelivered(ctx context.Context, ids []string, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := p.db.ExecContext(ctx, "UPDATE account_outbox SET delivered_at = $1 WHERE id = ANY($2) AND delivered_at IS NULL", now, ids); err != nil {
+		return fmt.Errorf("failed to mark events delivered: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) PurgeEvents(ctx context.Context, before time.Time) (int, error) {
+	result, err := p.db.ExecContext(ctx, "DELETE FROM account_outbox WHERE delivered_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge events: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge events: %w", err)
+	}
+
+	return int(purged), nil
+}
@@ -0,0 +1,181 @@
+CREATE TABLE IF NOT EXISTS accounts (
+    name   TEXT PRIMARY KEY,
+    amount INTEGER NOT NULL
+);
+
+-- Мягкое удаление: строка остаётся до очистки по сроку хранения.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+CREATE INDEX IF NOT EXISTS accounts_deleted_at ON accounts (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Статус жизненного цикла; существующие аккаунты считаются активными.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pending', 'active', 'frozen', 'closed'));
+
+-- История статусов удаляется вместе с аккаунтом при очистке.
+CREATE TABLE IF NOT EXISTS account_transitions (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL REFERENCES accounts (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status   TEXT NOT NULL,
+    reason      TEXT NOT NULL,
+    actor       TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS account_transitions_name ON account_transitions (name, id);
+
+-- Неизменяемый ID стал первичным ключом, имя — обычным уникальным столбцом.
+-- Существующим строкам ID выдаёт gen_random_uuid (PostgreSQL 13+), новым — приложение.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS id UUID;
+UPDATE accounts SET id = gen_random_uuid() WHERE id IS NULL;
+ALTER TABLE accounts ALTER COLUMN id SET NOT NULL;
+ALTER TABLE account_transitions ADD COLUMN IF NOT EXISTS account_id UUID;
+DO $$
+BEGIN
+    -- История ссылалась на имя: переводим её на ID, пока имя ещё первичный ключ.
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_schema = current_schema() AND table_name = 'account_transitions' AND column_name = 'name') THEN
+        UPDATE account_transitions t SET account_id = a.id FROM accounts a WHERE a.name = t.name;
+        ALTER TABLE account_transitions DROP COLUMN name;
+        ALTER TABLE account_transitions ALTER COLUMN account_id SET NOT NULL;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_index i JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY (i.indkey)
+                   WHERE i.indrelid = 'accounts'::regclass AND i.indisprimary AND a.attname = 'id') THEN
+        ALTER TABLE accounts DROP CONSTRAINT accounts_pkey;
+        ALTER TABLE accounts ADD PRIMARY KEY (id);
+        ALTER TABLE accounts ADD CONSTRAINT accounts_name_key UNIQUE (name);
+        ALTER TABLE account_transitions ADD FOREIGN KEY (account_id) REFERENCES accounts (id) ON DELETE CASCADE;
+    END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS account_transitions_account ON account_transitions (account_id, id);
+
+-- История переименований; по ней же старые имена ведут к аккаунту, пока не истёк срок.
+CREATE TABLE IF NOT EXISTS account_renames (
+    id          BIGSERIAL PRIMARY KEY,
+    account_id  UUID NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    old_name    TEXT NOT NULL,
+    new_name    TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS account_renames_account ON account_renames (account_id, id);
+CREATE INDEX IF NOT EXISTS account_renames_old_name ON account_renames (old_name, id);
+
+-- Время создания и последнего изменения; существующим аккаунтам достаётся время миграции.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Описание и метки; GIN индекс обслуживает селекторы меток (операторы @> и ?).
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS accounts_labels ON accounts USING GIN (labels);
+
+-- Холды резервируют часть баланса; активный холд с истёкшим expires_at уже ничего не резервирует.
+CREATE TABLE IF NOT EXISTS account_holds (
+    id          UUID PRIMARY KEY,
+    account_id  UUID NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount      INTEGER NOT NULL CHECK (amount > 0),
+    status      TEXT NOT NULL CHECK (status IN ('active', 'captured', 'released', 'expired')),
+    captured    INTEGER NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    closed_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS account_holds_account ON account_holds (account_id, id);
+CREATE INDEX IF NOT EXISTS account_holds_active ON account_holds (account_id, expires_at) WHERE status = 'active';
+
+-- Расписания переводов; next_run_at пуст у отменённых и завершённых.
+CREATE TABLE IF NOT EXISTS account_schedules (
+    id           UUID PRIMARY KEY,
+    from_account UUID NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    to_account   UUID NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount       INTEGER NOT NULL CHECK (amount > 0),
+    cron         TEXT NOT NULL DEFAULT '',
+    description  TEXT NOT NULL DEFAULT '',
+    status       TEXT NOT NULL CHECK (status IN ('active', 'paused', 'cancelled', 'completed')),
+    next_run_at  TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS account_schedules_due ON account_schedules (next_run_at, id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS account_schedules_from ON account_schedules (from_account, id);
+CREATE INDEX IF NOT EXISTS account_schedules_to ON account_schedules (to_account, id);
+
+-- Исполнения расписаний: первичный ключ не даёт исполнить один срок дважды.
+CREATE TABLE IF NOT EXISTS account_schedule_runs (
+    schedule_id UUID NOT NULL REFERENCES account_schedules (id) ON DELETE CASCADE,
+    due_at      TIMESTAMPTZ NOT NULL,
+    status      TEXT NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    error       TEXT NOT NULL DEFAULT '',
+    ran_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (schedule_id, due_at)
+);
+
+-- Проводки по счетам; reference уникален в пределах аккаунта, так что пакет начисления
+-- не проводится дважды.
+CREATE TABLE IF NOT EXISTS account_ledger (
+    id          UUID PRIMARY KEY,
+    account_id  UUID NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    kind        TEXT NOT NULL,
+    amount      INTEGER NOT NULL,
+    balance     INTEGER NOT NULL,
+    reference   TEXT NOT NULL,
+    posted_at   TIMESTAMPTZ NOT NULL,
+    UNIQUE (account_id, reference)
+);
+
+-- Пакеты начисления процентов; last_account — курсор, с которого продолжают прерванный пакет.
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    id           TEXT PRIMARY KEY,
+    compounding  TEXT NOT NULL CHECK (compounding IN ('daily', 'monthly')),
+    period_start TIMESTAMPTZ NOT NULL,
+    status       TEXT NOT NULL CHECK (status IN ('running', 'completed')),
+    last_account UUID,
+    postings     INTEGER NOT NULL DEFAULT 0,
+    total        BIGINT NOT NULL DEFAULT 0,
+    started_at   TIMESTAMPTZ NOT NULL,
+    finished_at  TIMESTAMPTZ
+);
+
+-- Подписки вебхуков; event_types и accounts — JSON массивы, пустой массив означает «все».
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id          UUID PRIMARY KEY,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    event_types JSONB NOT NULL DEFAULT '[]',
+    accounts    JSONB NOT NULL DEFAULT '[]',
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+-- Доставки событий подпискам. Ссылки на аккаунт нет: доставка переживает очистку аккаунта.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               UUID PRIMARY KEY,
+    subscription_id  UUID NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id         UUID NOT NULL,
+    event_type       TEXT NOT NULL,
+    account_id       UUID NOT NULL,
+    payload          BYTEA NOT NULL,
+    status           TEXT NOT NULL CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL,
+    last_error       TEXT NOT NULL DEFAULT '',
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL,
+    delivered_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due ON webhook_deliveries (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription ON webhook_deliveries (subscription_id, id);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_event ON webhook_deliveries (event_id);
+
+-- Outbox событий об изменениях аккаунтов: строка пишется в одной транзакции с изменением,
+-- ретранслятор забирает недоставленные по sequence. Ссылки на аккаунт нет: событие переживает очистку.
+CREATE TABLE IF NOT EXISTS account_outbox (
+    sequence     BIGSERIAL PRIMARY KEY,
+    id           UUID NOT NULL UNIQUE,
+    type         TEXT NOT NULL,
+    account      JSONB NOT NULL,
+    occurred_at  TIMESTAMPTZ NOT NULL,
+    delivered_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS account_outbox_pending ON account_outbox (sequence) WHERE delivered_at IS NULL;
+CREATE INDEX IF NOT EXISTS account_outbox_delivered ON account_outbox (delivered_at) WHERE delivered_at IS NOT NULL;
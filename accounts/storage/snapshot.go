@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"sort"
+	"sync"
+)
+
+// Snapshot — неизменяемый вид хранилища в памяти на момент одного коммита.
+// Чтение из снимка не берёт блокировок и не мешает писателям.
+// Снимок нужно закрыть: пока он открыт, версии, которые он видит, не удаляются.
+// Снимок показывает и мягко удалённые аккаунты; отфильтровать их — дело вызывающего.
+type Snapshot struct {
+	memory *Memory
+	seq    uint64
+	once   sync.Once
+}
+
+// Snapshot открывает снимок на последнем опубликованном коммите.
+func (m *Memory) Snapshot() *Snapshot {
+	m.snapshotsGuard.Lock()
+	defer m.snapshotsGuard.Unlock()
+
+	seq := m.committed.Load()
+	m.snapshots[seq]++
+
+	return &Snapshot{memory: m, seq: seq}
+}
+
+// Seq — номер коммита, на котором открыт снимок.
+func (s *Snapshot) Seq() uint64 {
+	return s.seq
+}
+
+// Get ищет аккаунт по ID или имени, которое он носил на момент снимка.
+func (s *Snapshot) Get(ref string) (models.Account, error) {
+	if id, ok := s.memory.resolve(ref); ok {
+		if value, ok := s.memory.shardFor(id).chains.Load(id); ok {
+			if account, ok := value.(*chain).visible(s.seq); ok && account.RefersTo(ref) {
+				return account, nil
+			}
+		}
+	}
+	// Индекс имён не версионируется: после переименования старое имя ищется перебором снимка.
+	for _, account := range s.List() {
+		if account.RefersTo(ref) {
+			return account, nil
+		}
+	}
+
+	return models.Account{}, errs.AccountNotFound(ref)
+}
+
+// List возвращает все аккаунты снимка, отсортированные по имени.
+func (s *Snapshot) List() []models.Account {
+	accounts := make([]models.Account, 0)
+	for _, sh := range s.memory.shards {
+		sh.chains.Range(func(_, value any) bool {
+			if account, ok := value.(*chain).visible(s.seq); ok {
+				accounts = append(accounts, account)
+			}
+			return true
+		})
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+
+	return accounts
+}
+
+// Close освобождает снимок; если он был самым старым, старые версии собираются.
+func (s *Snapshot) Close() {
+	s.once.Do(func() {
+		m := s.memory
+		m.snapshotsGuard.Lock()
+		oldest := s.seq == m.oldestSnapshot()
+		if m.snapshots[s.seq]--; m.snapshots[s.seq] == 0 {
+			delete(m.snapshots, s.seq)
+		}
+		m.snapshotsGuard.Unlock()
+
+		if oldest {
+			m.collect()
+		}
+	})
+}
+
+// horizon — номер коммита, который видит самый старый открытый снимок;
+// версии, перекрытые более новыми версиями не старше горизонта, никому не нужны.
+func (m *Memory) horizon() uint64 {
+	m.snapshotsGuard.Lock()
+	defer m.snapshotsGuard.Unlock()
+
+	if len(m.snapshots) == 0 {
+		return m.committed.Load()
+	}
+
+	return m.oldestSnapshot()
+}
+
+// oldestSnapshot вызывается под snapshotsGuard при непустом snapshots.
+func (m *Memory) oldestSnapshot() uint64 {
+	oldest := uint64(0)
+	first := true
+	for seq := range m.snapshots {
+		if first || seq < oldest {
+			oldest, first = seq, false
+		}
+	}
+
+	return oldest
+}
+
+// collect удаляет ненужные версии и цепочки аккаунтов, очищенных до горизонта.
+func (m *Memory) collect() {
+	horizon := m.horizon()
+
+	for _, s := range m.shards {
+		s.guard.Lock()
+		s.chains.Range(func(id, value any) bool {
+			c := value.(*chain)
+			trim(c, horizon)
+			if head := c.head.Load(); head != nil && head.deleted && head.seq <= horizon {
+				s.chains.Delete(id)
+			}
+			return true
+		})
+		s.guard.Unlock()
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"awesomeProject/accounts/models"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// versions — число версий в цепочке аккаунта; 0, если цепочки нет.
+func versions(m *Memory, id string) int {
+	value, ok := m.shardFor(id).chains.Load(id)
+	if !ok {
+		return 0
+	}
+	n := 0
+	for v := value.(*chain).head.Load(); v != nil; v = v.prev.Load() {
+		n++
+	}
+
+	return n
+}
+
+// TestSnapshotStable читает снимок, пока писатели меняют, переименовывают, удаляют и заводят аккаунты;
+// запускать с -race. Снимок всё это время видит состояние на момент открытия.
+func TestSnapshotStable(t *testing.T) {
+	const accounts = 8
+	ctx := context.Background()
+	m := NewShardedMemory(4)
+	ids := fillMemory(t, m, accounts, 100)
+
+	snapshot := m.Snapshot()
+	defer snapshot.Close()
+	want := snapshot.List()
+
+	var writers sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		writers.Add(1)
+		go func(w int) {
+			defer writers.Done()
+			for i := 0; i < 500; i++ {
+				id := ids[(w+i)%accounts]
+				switch i % 5 {
+				case 0:
+					_, _ = m.ChangeAmount(ctx, id, i)
+				case 1:
+					_, _, _ = m.Transfer(ctx, id, ids[(w+i+1)%accounts], 1)
+				case 2:
+					_, _ = m.ChangeName(ctx, id, fmt.Sprintf("renamed-%d-%d", w, i))
+				case 3:
+					_, _ = m.Create(ctx, models.Account{Name: fmt.Sprintf("new-%d-%d", w, i)})
+				default:
+					if m.Delete(ctx, id, "test") == nil {
+						_, _ = m.Restore(ctx, id)
+					}
+				}
+			}
+		}(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		writers.Wait()
+		close(done)
+	}()
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		got := snapshot.List()
+		if len(got) != len(want) {
+			t.Fatalf("snapshot lists %d accounts, want %d", len(got), len(want))
+		}
+		for i, account := range got {
+			if account.ID != want[i].ID || account.Name != want[i].Name || account.Amount != want[i].Amount || account.Deleted() {
+				t.Fatalf("snapshot account %d = %+v, want %+v", i, account, want[i])
+			}
+			if byID, err := snapshot.Get(account.ID); err != nil || byID.Amount != account.Amount {
+				t.Fatalf("snapshot get %s = %+v, %v; want amount %d", account.ID, byID, err, account.Amount)
+			}
+		}
+	}
+
+	current, err := m.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(current) == len(want) {
+		t.Fatalf("writers created no accounts: %d listed", len(current))
+	}
+}
+
+func TestSnapshotCollectsVersionsOnClose(t *testing.T) {
+	const writes = 10
+	ctx := context.Background()
+	m := NewMemory()
+	ids := fillMemory(t, m, 1, 0)
+	id := ids[0]
+
+	snapshot := m.Snapshot()
+	for i := 1; i <= writes; i++ {
+		if _, err := m.ChangeAmount(ctx, id, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Открытый снимок держит свою версию и все более новые.
+	if n := versions(m, id); n != writes+1 {
+		t.Fatalf("%d versions with a snapshot open, want %d", n, writes+1)
+	}
+	if account, err := snapshot.Get(id); err != nil || account.Amount != 0 {
+		t.Fatalf("snapshot get = %+v, %v; want amount 0", account, err)
+	}
+
+	snapshot.Close()
+	if n := versions(m, id); n != 1 {
+		t.Fatalf("%d versions after close, want 1", n)
+	}
+	if account, err := m.Get(ctx, id); err != nil || account.Amount != writes {
+		t.Fatalf("get = %+v, %v; want amount %d", account, err, writes)
+	}
+}
+
+func TestSnapshotKeepsPurgedAccountUntilClose(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	id := fillMemory(t, m, 1, 5)[0]
+	if err := m.Delete(ctx, id, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := m.Snapshot()
+	if n, err := m.Purge(ctx, time.Now().Add(time.Minute)); err != nil || n != 1 {
+		t.Fatalf("purge = %d, %v; want 1", n, err)
+	}
+	if _, err := m.Get(ctx, id, IncludeDeleted()); err == nil {
+		t.Fatal("purged account is still readable")
+	}
+	if account, err := snapshot.Get(id); err != nil || !account.Deleted() {
+		t.Fatalf("snapshot get = %+v, %v; want the deleted account", account, err)
+	}
+
+	snapshot.Close()
+	if n := versions(m, id); n != 0 {
+		t.Fatalf("%d versions of a purged account after close, want its chain collected", n)
+	}
+}
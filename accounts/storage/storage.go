@@ -0,0 +1,537 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/validation"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Storage хранит аккаунты; его разделяют REST и gRPC серверы.
+// Ошибки возвращаются из каталога errs: errs.AccountNotFound, errs.AccountAlreadyExists.
+//
+// Аккаунт определяется неизменяемым ID, который выдаёт Create. Операции принимают ссылку ref:
+// ID или текущее имя аккаунта; строка, разбираемая как ID, сначала ищется как ID.
+// ChangeName записывает переименование в историю; Get с FollowRenames находит аккаунт
+// и по недавнему старому имени, если оно с тех пор никому не досталось.
+//
+// Delete удаляет аккаунт мягко: он скрыт от чтения без IncludeDeleted, но занимает имя,
+// может быть восстановлен через Restore и удаляется окончательно только Purge.
+// Изменять удалённый аккаунт нельзя — для операций записи его нет.
+//
+// Статус аккаунта (models.Status) ограничивает запись: запрещённая статусом операция
+// или смена статуса возвращает errs.AccountStatus.
+//
+// Холды резервируют часть баланса: Held у прочитанного аккаунта — сумма активных холдов,
+// и списания (уменьшение баланса, перевод, списание другого холда) не могут затронуть её.
+// Истёкший холд перестаёт резервировать средства сразу, ExpireHolds лишь отмечает это в хранилище.
+//
+// Расписания хранят поручения на переводы; исполняет их планировщик (пакет scheduler) через RunSchedule.
+// Каждый срок расписания исполняется не больше одного раза: перевод, запись об исполнении
+// и переход к следующему сроку сохраняются вместе, а повтор того же срока возвращает прежнюю запись.
+//
+// Проценты начисляются пакетами (models.Accrual) за период; считает их движок из пакета interest.
+// Проводка процентов, запись в журнал и сдвиг курсора пакета сохраняются вместе,
+// поэтому пакет, прерванный сбоем, продолжают с курсора и ни одному аккаунту не начисляют дважды.
+//
+// Подписки на события хранят адреса вебхуков; EnqueueEvent заводит по доставке события на каждую
+// подписку, которая его ждёт, а отправляет их диспетчер из пакета webhook.
+//
+// Каждое изменение аккаунта записывает событие (models.Event) в outbox вместе с самим изменением:
+// событие есть тогда и только тогда, когда изменение сохранено. Ретранслятор из пакета events
+// забирает недоставленные события по порядку Sequence, отмечает доставленные через MarkDelivered,
+// а PurgeEvents удаляет их по сроку хранения.
+type Storage interface {
+	Get(ctx context.Context, ref string, opts ...ReadOption) (models.Account, error)
+	// List возвращает все аккаунты, отсортированные по имени.
+	List(ctx context.Context, opts ...ReadOption) ([]models.Account, error)
+	// Export вызывает fn для каждого аккаунта в порядке имён; все аккаунты берутся на один момент времени.
+	Export(ctx context.Context, fn func(models.Account) error, opts ...ReadOption) error
+	// Create выдаёт аккаунту ID и возвращает созданный аккаунт.
+	Create(ctx context.Context, account models.Account) (models.Account, error)
+	ChangeAmount(ctx context.Context, ref string, amount int) (models.Account, error)
+	ChangeName(ctx context.Context, ref, newName string) (models.Account, error)
+	// ChangeMetadata меняет описание и метки аккаунта.
+	ChangeMetadata(ctx context.Context, ref string, change models.MetadataChange) (models.Account, error)
+	// Delete помечает аккаунт удалённым от имени actor; аккаунт с активными холдами не удаляется.
+	Delete(ctx context.Context, ref, actor string) error
+	// Restore снимает пометку об удалении.
+	Restore(ctx context.Context, ref string) (models.Account, error)
+	// Purge окончательно удаляет аккаунты, удалённые раньше before, и возвращает их число.
+	Purge(ctx context.Context, before time.Time) (int, error)
+	// SetStatus меняет статус аккаунта и записывает переход с причиной и автором в историю.
+	// Аккаунт с активными холдами не закрывается: иначе зарезервированную сумму уже нельзя было бы списать.
+	SetStatus(ctx context.Context, ref string, change models.Change, reason, actor string) (models.Account, error)
+	// StatusHistory возвращает переходы статусов аккаунта от старых к новым.
+	StatusHistory(ctx context.Context, ref string) ([]models.Transition, error)
+	// RenameHistory возвращает переименования аккаунта от старых к новым.
+	RenameHistory(ctx context.Context, ref string) ([]models.Rename, error)
+	// Transfer атомарно переводит amount со счёта from на счёт to и возвращает оба счёта после перевода.
+	Transfer(ctx context.Context, from, to string, amount int) (models.Account, models.Account, error)
+
+	// Authorize резервирует amount доступного баланса на время ttl.
+	Authorize(ctx context.Context, ref string, amount int, ttl time.Duration) (models.Hold, error)
+	// Capture списывает с баланса amount из активного холда, 0 — весь холд; остаток освобождается.
+	// Возвращает закрытый холд и аккаунт после списания.
+	Capture(ctx context.Context, holdID string, amount int) (models.Hold, models.Account, error)
+	// Release снимает активный холд, не меняя баланс.
+	Release(ctx context.Context, holdID string) (models.Hold, error)
+	Hold(ctx context.Context, holdID string) (models.Hold, error)
+	// Holds возвращает все холды аккаунта, включая закрытые, от старых к новым.
+	Holds(ctx context.Context, ref string) ([]models.Hold, error)
+	// ExpireHolds отмечает истёкшими холды со сроком до now и возвращает их число.
+	ExpireHolds(ctx context.Context, now time.Time) (int, error)
+
+	// CreateSchedule сохраняет активное расписание; From и To — ссылки на живые аккаунты,
+	// в сохранённом расписании они заменены на ID. NextRunAt — срок разового перевода
+	// или начало повторяющегося: первый срок Cron не раньше него, нулевое — от текущего момента.
+	CreateSchedule(ctx context.Context, schedule models.Schedule) (models.Schedule, error)
+	Schedule(ctx context.Context, id string) (models.Schedule, error)
+	// Schedules возвращает расписания, где аккаунт ref — источник или получатель, от старых к новым;
+	// пустой ref — все расписания.
+	Schedules(ctx context.Context, ref string) ([]models.Schedule, error)
+	// ChangeSchedule приостанавливает, возобновляет или отменяет расписание.
+	// Возобновлённое повторяющееся расписание пропускает сроки, пропущенные за время паузы.
+	ChangeSchedule(ctx context.Context, id string, action models.ScheduleAction, now time.Time) (models.Schedule, error)
+	// DueSchedules возвращает не больше limit активных расписаний со сроком не позже now, от давних сроков к новым.
+	DueSchedules(ctx context.Context, now time.Time, limit int) ([]models.Schedule, error)
+	// RunSchedule исполняет срок dueAt: переводит деньги, записывает исполнение и переходит к следующему сроку.
+	// Отказ перевода (нехватка средств, статус аккаунта) записывается как неуспешное исполнение, а не ошибка.
+	// Если срок уже исполнен, возвращает прежнюю запись; если расписание больше не ждёт dueAt — errs.ScheduleStatus.
+	RunSchedule(ctx context.Context, id string, dueAt, now time.Time) (models.ScheduleRun, error)
+	// ScheduleRuns возвращает исполнения расписания от старых к новым.
+	ScheduleRuns(ctx context.Context, id string) ([]models.ScheduleRun, error)
+
+	// BeginAccrual начинает пакет начисления за период или возвращает уже начатый, чтобы его продолжить.
+	BeginAccrual(ctx context.Context, period models.Period, now time.Time) (models.Accrual, error)
+	// PostInterest зачисляет живому аккаунту ref проценты пакета accrualID и сдвигает курсор пакета на аккаунт.
+	// Сумму считает interest по заблокированному аккаунту; ноль ничего не зачисляет и только сдвигает курсор.
+	// Если аккаунт уже получил проценты этого пакета, возвращает прежнюю проводку.
+	PostInterest(ctx context.Context, accrualID, ref string, interest func(models.Account) int, now time.Time) (models.LedgerEntry, error)
+	// FinishAccrual отмечает пакет завершённым.
+	FinishAccrual(ctx context.Context, id string, now time.Time) (models.Accrual, error)
+	Accrual(ctx context.Context, id string) (models.Accrual, error)
+	// Accruals возвращает пакеты начисления от старых периодов к новым.
+	Accruals(ctx context.Context) ([]models.Accrual, error)
+	// Ledger возвращает проводки аккаунта от старых к новым; доступен и для удалённого аккаунта до очистки.
+	Ledger(ctx context.Context, ref string) ([]models.LedgerEntry, error)
+
+	// CreateSubscription сохраняет подписку; Accounts — ссылки на аккаунты, в сохранённой подписке они заменены на ID.
+	CreateSubscription(ctx context.Context, subscription models.Subscription) (models.Subscription, error)
+	Subscription(ctx context.Context, id string) (models.Subscription, error)
+	// Subscriptions возвращает подписки от старых к новым.
+	Subscriptions(ctx context.Context) ([]models.Subscription, error)
+	// DeleteSubscription удаляет подписку вместе с её доставками.
+	DeleteSubscription(ctx context.Context, id string) error
+	// EnqueueEvent заводит доставку тела payload каждой подписке, которая ждёт событие.
+	// Повтор уже заведённого события ничего не заводит: ретранслятор outbox может передать событие дважды.
+	EnqueueEvent(ctx context.Context, event models.Event, payload []byte) ([]models.Delivery, error)
+	// DueDeliveries возвращает не больше limit ждущих доставок со сроком попытки не позже now, от давних сроков к новым.
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error)
+	// UpdateDelivery сохраняет итог попытки доставки.
+	UpdateDelivery(ctx context.Context, delivery models.Delivery) error
+	// Deliveries возвращает доставки подписки от старых к новым; непустой status оставляет доставки в этом состоянии.
+	Deliveries(ctx context.Context, subscriptionID string, status models.DeliveryStatus) ([]models.Delivery, error)
+	// ReplayDelivery ставит доставку подписки в очередь заново: попытка сразу и полный запас попыток.
+	ReplayDelivery(ctx context.Context, subscriptionID, id string, now time.Time) (models.Delivery, error)
+
+	// PendingEvents возвращает не больше limit недоставленных событий outbox по возрастанию Sequence.
+	PendingEvents(ctx context.Context, limit int) ([]models.Event, error)
+	// MarkDelivered отмечает события outbox доставленными в момент now; неизвестные ID пропускаются.
+	MarkDelivered(ctx context.Context, ids []string, now time.Time) error
+	// PurgeEvents удаляет события outbox, доставленные раньше before, и возвращает их число.
+	PurgeEvents(ctx context.Context, before time.Time) (int, error)
+}
+
+// checkAction возвращает ошибку, если статус аккаунта запрещает операцию.
+func checkAction(account models.Account, action models.Action) error {
+	if !account.Status.Allows(action) {
+		return errs.AccountStatus(account.Name, string(account.Status), string(action))
+	}
+
+	return nil
+}
+
+// checkDelete проверяет удаление: удалённый аккаунт очищается вместе с холдами,
+// поэтому активные холды нужно сначала списать или снять, как и перед закрытием.
+func checkDelete(account models.Account) error {
+	if err := checkAction(account, models.ActionDelete); err != nil {
+		return err
+	}
+	if account.Held > 0 {
+		return errs.AccountHasHolds(account.Name, account.Held, string(models.ActionDelete))
+	}
+
+	return nil
+}
+
+// checkAmount проверяет установку баланса amount: списание должно быть разрешено статусом
+// и не может затронуть средства, зарезервированные холдами.
+func checkAmount(account models.Account, amount int) error {
+	if err := checkAction(account, amountAction(account, amount)); err != nil {
+		return err
+	}
+	if amount < account.Held {
+		return errs.InsufficientFunds(account.Name, account.Available(), account.Amount-amount)
+	}
+
+	return nil
+}
+
+// amountAction — зачисление или списание при установке баланса amount.
+func amountAction(account models.Account, amount int) models.Action {
+	if amount < account.Amount {
+		return models.ActionDebit
+	}
+
+	return models.ActionCredit
+}
+
+// transition проверяет смену статуса и возвращает запись для истории.
+// Held аккаунта должен быть прочитан под той же блокировкой, что и смена статуса.
+func transition(account models.Account, change models.Change, reason, actor string) (models.Transition, error) {
+	if !change.AllowedFrom(account.Status) {
+		return models.Transition{}, errs.AccountStatus(account.Name, string(account.Status), change.Name)
+	}
+	if change.To == models.StatusClosed && account.Held > 0 {
+		return models.Transition{}, errs.AccountHasHolds(account.Name, account.Held, change.Name)
+	}
+
+	return models.Transition{
+		From:   account.Status,
+		To:     change.To,
+		Reason: reason,
+		Actor:  actor,
+		At:     time.Now().UTC(),
+	}, nil
+}
+
+// initialStatus — статус нового аккаунта; по умолчанию он сразу активен.
+func initialStatus(status models.Status) models.Status {
+	if status == "" {
+		return models.StatusActive
+	}
+
+	return status
+}
+
+// checkTransfer — проверки перевода, общие для всех хранилищ.
+func checkTransfer(from, to string, amount int) error {
+	if from == to {
+		return errs.InvalidField("to", "must differ from the source account")
+	}
+	if amount <= 0 {
+		return errs.InvalidField("amount", "must be positive")
+	}
+
+	return nil
+}
+
+// applyTransfer меняет балансы после всех проверок, общих для хранилищ.
+func applyTransfer(from, to *models.Account, amount int) error {
+	if err := checkAction(*from, models.ActionDebit); err != nil {
+		return err
+	}
+	if err := checkAction(*to, models.ActionCredit); err != nil {
+		return err
+	}
+	if from.Available() < amount {
+		return errs.InsufficientFunds(from.Name, from.Available(), amount)
+	}
+	if to.Amount > validation.MaxAmount-amount {
+		return errs.InvalidField("amount", fmt.Sprintf("balance of %q would exceed %d", to.Name, validation.MaxAmount))
+	}
+
+	from.Amount -= amount
+	to.Amount += amount
+	touch(from)
+	touch(to)
+
+	return nil
+}
+
+// Update применяет изменение по маске полей: баланс, описание и метки, затем имя.
+// Имя меняется последним, чтобы ref оставалась верной для предыдущих шагов.
+// Шаги выполняются по очереди, а не одной транзакцией.
+func Update(ctx context.Context, s Storage, ref string, update models.Update) (models.Account, error) {
+	var account models.Account
+	var err error
+	changed := false
+
+	if update.Amount != nil {
+		if account, err = s.ChangeAmount(ctx, ref, *update.Amount); err != nil {
+			return models.Account{}, err
+		}
+		changed = true
+	}
+	if !update.Metadata.Empty() {
+		if account, err = s.ChangeMetadata(ctx, ref, update.Metadata); err != nil {
+			return models.Account{}, err
+		}
+		changed = true
+	}
+	// Переименование в то же имя ничего не меняет и не проверяет статус, как и раньше.
+	if update.Name != nil && *update.Name != ref {
+		if account, err = s.ChangeName(ctx, ref, *update.Name); err != nil {
+			return models.Account{}, err
+		}
+		changed = true
+	}
+	if !changed {
+		return s.Get(ctx, ref)
+	}
+
+	return account, nil
+}
+
+// annotate проверяет и применяет изменение описания и меток.
+func annotate(account *models.Account, change models.MetadataChange) error {
+	if err := checkAction(*account, models.ActionAnnotate); err != nil {
+		return err
+	}
+
+	labels := change.Apply(account.Labels)
+	if len(labels) > validation.MaxLabels {
+		return errs.InvalidField("labels", fmt.Sprintf("must have at most %d labels", validation.MaxLabels))
+	}
+	account.Labels = labels
+	if change.Description != nil {
+		account.Description = *change.Description
+	}
+
+	return nil
+}
+
+// touch отмечает момент изменения аккаунта.
+func touch(account *models.Account) {
+	account.UpdatedAt = time.Now().UTC()
+}
+
+// authorize проверяет резерв amount на аккаунте и возвращает новый холд.
+func authorize(account models.Account, amount int, ttl time.Duration) (models.Hold, error) {
+	if err := checkAction(account, models.ActionDebit); err != nil {
+		return models.Hold{}, err
+	}
+	if account.Available() < amount {
+		return models.Hold{}, errs.InsufficientFunds(account.Name, account.Available(), amount)
+	}
+
+	now := time.Now().UTC()
+
+	return models.Hold{
+		ID:        models.NewID(),
+		AccountID: account.ID,
+		Amount:    amount,
+		Status:    models.HoldActive,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// capture списывает amount из холда с баланса аккаунта и закрывает холд.
+func capture(account *models.Account, hold *models.Hold, amount int) error {
+	now := time.Now().UTC()
+	*hold = hold.At(now)
+	if hold.Status != models.HoldActive {
+		return errs.HoldClosed(hold.ID, string(hold.Status))
+	}
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if amount > hold.Amount {
+		return errs.Invalid([]errs.FieldViolation{{Field: "amount", Rule: "max", Description: fmt.Sprintf("must not exceed the held amount %d", hold.Amount)}})
+	}
+	if err := checkAction(*account, models.ActionDebit); err != nil {
+		return err
+	}
+
+	account.Amount -= amount
+	account.Held -= hold.Amount
+	account.UpdatedAt = now
+	hold.Status = models.HoldCaptured
+	hold.Captured = amount
+	hold.ClosedAt = now
+
+	return nil
+}
+
+// release снимает активный холд.
+func release(hold *models.Hold) error {
+	now := time.Now().UTC()
+	*hold = hold.At(now)
+	if hold.Status != models.HoldActive {
+		return errs.HoldClosed(hold.ID, string(hold.Status))
+	}
+
+	hold.Status = models.HoldReleased
+	hold.ClosedAt = now
+
+	return nil
+}
+
+// newSchedule заполняет новое расписание: ID, статус и первый срок не раньше NextRunAt, по умолчанию — now.
+func newSchedule(schedule models.Schedule, now time.Time) (models.Schedule, error) {
+	if schedule.From == schedule.To {
+		return models.Schedule{}, errs.InvalidField("to", "must differ from the source account")
+	}
+
+	schedule.ID = models.NewID()
+	schedule.Status = models.ScheduleActive
+	schedule.CreatedAt = now.UTC()
+	schedule.UpdatedAt = schedule.CreatedAt
+	if schedule.Recurring() {
+		start := schedule.NextRunAt
+		if start.IsZero() {
+			start = now
+		}
+		// Cron.Next ищет строго после момента, а срок, совпадающий с началом, тоже подходит.
+		schedule.NextRunAt = schedule.After(start.Add(-time.Nanosecond))
+		if schedule.NextRunAt.IsZero() {
+			return models.Schedule{}, errs.InvalidField("cron", "never fires")
+		}
+	}
+	// Сроки хранятся с точностью до секунды: так они одинаково сравниваются во всех хранилищах.
+	schedule.NextRunAt = schedule.NextRunAt.UTC().Truncate(time.Second)
+
+	return schedule, nil
+}
+
+// changeSchedule применяет действие к расписанию или возвращает errs.ScheduleStatus.
+func changeSchedule(schedule models.Schedule, action models.ScheduleAction, now time.Time) (models.Schedule, error) {
+	switch {
+	case action == models.SchedulePause && schedule.Status == models.ScheduleActive:
+		schedule.Status = models.SchedulePaused
+	case action == models.ScheduleResume && schedule.Status == models.SchedulePaused:
+		schedule.Status = models.ScheduleActive
+		if schedule.Recurring() && schedule.NextRunAt.Before(now) {
+			schedule.NextRunAt = schedule.After(now.Add(-time.Nanosecond)).Truncate(time.Second)
+		}
+	case action == models.ScheduleCancel && (schedule.Status == models.ScheduleActive || schedule.Status == models.SchedulePaused):
+		schedule.Status = models.ScheduleCancelled
+		schedule.NextRunAt = time.Time{}
+	default:
+		return models.Schedule{}, errs.ScheduleStatus(schedule.ID, string(schedule.Status), string(action))
+	}
+	schedule.UpdatedAt = now.UTC()
+
+	return schedule, nil
+}
+
+// checkDue проверяет, что расписание всё ещё ждёт срок dueAt.
+func checkDue(schedule models.Schedule, dueAt time.Time) error {
+	if schedule.Status != models.ScheduleActive || !schedule.NextRunAt.Equal(dueAt) {
+		return errs.ScheduleStatus(schedule.ID, string(schedule.Status), "run at "+dueAt.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// finishRun превращает итог перевода в запись об исполнении и переводит расписание к следующему сроку.
+// Ошибки, после которых срок стоит повторить (недоступность хранилища, конфликт), возвращаются как есть.
+func finishRun(schedule *models.Schedule, dueAt, now time.Time, transferErr error) (models.ScheduleRun, error) {
+	run := models.ScheduleRun{ScheduleID: schedule.ID, DueAt: dueAt, Status: models.RunSucceeded, RanAt: now.UTC()}
+	if transferErr != nil {
+		var e *errs.Error
+		if !errors.As(transferErr, &e) || (e.Code != errs.InvalidArgument && e.Code != errs.NotFound && e.Code != errs.FailedPrecondition) {
+			return models.ScheduleRun{}, transferErr
+		}
+		run.Status = models.RunFailed
+		run.Error = e.Message
+	}
+
+	schedule.NextRunAt = schedule.After(dueAt).Truncate(time.Second)
+	if schedule.NextRunAt.IsZero() {
+		schedule.Status = models.ScheduleCompleted
+	}
+	schedule.UpdatedAt = run.RanAt
+
+	return run, nil
+}
+
+func newAccrual(period models.Period, now time.Time) models.Accrual {
+	return models.Accrual{ID: period.ID(), Period: period, Status: models.AccrualRunning, StartedAt: now.UTC()}
+}
+
+// postInterest зачисляет amount на аккаунт в пакете accrual и возвращает проводку;
+// нулевая сумма только сдвигает курсор и возвращает пустую проводку.
+func postInterest(accrual *models.Accrual, account *models.Account, amount int, now time.Time) (models.LedgerEntry, error) {
+	if accrual.Status != models.AccrualRunning {
+		return models.LedgerEntry{}, errs.AccrualCompleted(accrual.ID)
+	}
+	if amount > 0 {
+		if err := checkAction(*account, models.ActionCredit); err != nil {
+			return models.LedgerEntry{}, err
+		}
+		if account.Amount > validation.MaxAmount-amount {
+			return models.LedgerEntry{}, errs.InvalidField("amount", fmt.Sprintf("balance of %q would exceed %d", account.Name, validation.MaxAmount))
+		}
+	}
+	accrual.Cursor = max(accrual.Cursor, account.ID)
+	if amount <= 0 {
+		return models.LedgerEntry{}, nil
+	}
+
+	account.Amount += amount
+	account.UpdatedAt = now.UTC()
+	accrual.Postings++
+	accrual.Total += amount
+
+	return models.LedgerEntry{
+		ID:        models.NewID(),
+		AccountID: account.ID,
+		Kind:      models.EntryInterest,
+		Amount:    amount,
+		Balance:   account.Amount,
+		Reference: accrual.ID,
+		PostedAt:  account.UpdatedAt,
+	}, nil
+}
+
+// finishAccrual завершает пакет; завершённый пакет возвращается как есть.
+func finishAccrual(accrual *models.Accrual, now time.Time) {
+	if accrual.Status == models.AccrualCompleted {
+		return
+	}
+	accrual.Status = models.AccrualCompleted
+	accrual.FinishedAt = now.UTC()
+}
+
+func newSubscription(subscription models.Subscription) models.Subscription {
+	subscription.ID = models.NewID()
+	subscription.CreatedAt = time.Now().UTC()
+
+	return subscription
+}
+
+// newDeliveries заводит доставки события подпискам, которые его ждут.
+func newDeliveries(subscriptions []models.Subscription, event models.Event, payload []byte) []models.Delivery {
+	var deliveries []models.Delivery
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event) {
+			continue
+		}
+		deliveries = append(deliveries, models.Delivery{
+			ID:             models.NewID(),
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			AccountID:      event.Account.ID,
+			Payload:        payload,
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  event.OccurredAt,
+			CreatedAt:      event.OccurredAt,
+		})
+	}
+
+	return deliveries
+}
+
+func replayDelivery(delivery *models.Delivery, now time.Time) {
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now.UTC()
+	delivery.DeliveredAt = time.Time{}
+}
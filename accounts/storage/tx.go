@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Коды SQLSTATE, которые обрабатываются особо.
+const (
+	sqlStateUniqueViolation      = "23505"
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+const (
+	// txAttempts — сколько раз выполняется транзакция при конфликтах сериализации.
+	txAttempts       = 5
+	txInitialBackoff = 10 * time.Millisecond
+	txMaxBackoff     = 200 * time.Millisecond
+)
+
+// withTx выполняет fn в транзакции SERIALIZABLE. Если Postgres отменил транзакцию
+// из-за конфликта с параллельной (40001) или взаимоблокировки (40P01), она повторяется
+// целиком с экспоненциальной задержкой; после txAttempts попыток возвращается errs.Aborted.
+// fn может вызываться несколько раз и не должна иметь побочных эффектов вне tx.
+func (p *Postgres) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	backoff := txInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := p.runTx(ctx, fn)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt == txAttempts {
+			return errs.New(errs.Aborted, fmt.Sprintf("transaction aborted after %d attempts due to concurrent changes", attempt), nil)
+		}
+
+		// Полный джиттер разводит повторы конкурирующих транзакций во времени.
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff)) + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, txMaxBackoff)
+	}
+}
+
+func (p *Postgres) runTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func sqlState(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	return ""
+}
+
+func isRetryable(err error) bool {
+	state := sqlState(err)
+
+	return state == sqlStateSerializationFailure || state == sqlStateDeadlockDetected
+}
+
+func isUniqueViolation(err error) bool {
+	return sqlState(err) == sqlStateUniqueViolation
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"awesomeProject/accounts/errs"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// txDriver — драйвер database/sql, у которого есть только транзакции: withTx проверяется без Postgres.
+type txDriver struct{}
+
+func (txDriver) Open(string) (driver.Conn, error) {
+	return txConn{}, nil
+}
+
+type txConn struct{}
+
+func (txConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not supported")
+}
+
+func (txConn) Close() error {
+	return nil
+}
+
+func (txConn) Begin() (driver.Tx, error) {
+	return txConn{}, nil
+}
+
+func (txConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return txConn{}, nil
+}
+
+func (txConn) Commit() error {
+	return nil
+}
+
+func (txConn) Rollback() error {
+	return nil
+}
+
+func init() {
+	sql.Register("storage-tx-test", txDriver{})
+}
+
+func newTxPostgres(t *testing.T) *Postgres {
+	t.Helper()
+	db, err := sql.Open("storage-tx-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return NewPostgres(db)
+}
+
+func pgError(code string) error {
+	return fmt.Errorf("failed to change amount: %w", &pgconn.PgError{Code: code})
+}
+
+func TestRetryClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		unique    bool
+	}{
+		{name: "serialization failure", err: pgError(sqlStateSerializationFailure), retryable: true},
+		{name: "deadlock", err: pgError(sqlStateDeadlockDetected), retryable: true},
+		{name: "unique violation", err: pgError(sqlStateUniqueViolation), unique: true},
+		{name: "other sql state", err: pgError("23503")},
+		{name: "not a postgres error", err: errors.New("connection refused")},
+		{name: "catalog error", err: errs.AccountNotFound("alice")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.retryable {
+				t.Errorf("isRetryable() = %t, want %t", got, tt.retryable)
+			}
+			if got := isUniqueViolation(tt.err); got != tt.unique {
+				t.Errorf("isUniqueViolation() = %t, want %t", got, tt.unique)
+			}
+		})
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	conflict, deadlock, unique := pgError(sqlStateSerializationFailure), pgError(sqlStateDeadlockDetected), pgError(sqlStateUniqueViolation)
+	tests := []struct {
+		name  string
+		fails int
+		err   error
+		calls int
+		want  error
+	}{
+		{name: "succeeds at once", calls: 1},
+		{name: "succeeds after conflicts", fails: txAttempts - 1, err: conflict, calls: txAttempts},
+		{name: "aborted after attempts", fails: txAttempts + 1, err: deadlock, calls: txAttempts, want: errs.ErrAborted},
+		{name: "not retried", fails: txAttempts, err: errs.AccountNotFound("alice"), calls: 1, want: errs.ErrNotFound},
+		{name: "unique violation not retried", fails: txAttempts, err: unique, calls: 1, want: unique},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTxPostgres(t)
+			calls := 0
+			err := p.withTx(context.Background(), func(*sql.Tx) error {
+				calls++
+				if calls <= tt.fails {
+					return tt.err
+				}
+				return nil
+			})
+
+			if calls != tt.calls {
+				t.Errorf("fn called %d times, want %d", calls, tt.calls)
+			}
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("withTx() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTxStopsOnCancel(t *testing.T) {
+	p := newTxPostgres(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := p.withTx(ctx, func(*sql.Tx) error {
+		calls++
+		cancel()
+		return pgError(sqlStateSerializationFailure)
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatalf("withTx() = %v after %d calls, want context.Canceled after 1", err, calls)
+	}
+}
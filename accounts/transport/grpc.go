@@ -0,0 +1,534 @@
+package transport
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/rpc"
+	"awesomeProject/proto"
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type grpcAccounts struct {
+	conn   *grpc.ClientConn
+	client proto.AccountClient
+}
+
+// DialGRPC создаёт соединение без TLS; оно переиспользуется всеми вызовами до Close.
+func DialGRPC(address string, opts ...DialOption) (Accounts, error) {
+	var o dialOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	grpcOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if o.actor != "" {
+		grpcOpts = append(grpcOpts, grpc.WithUnaryInterceptor(actorInterceptor(o.actor)))
+	}
+
+	conn, err := grpc.NewClient(address, grpcOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc dial failed: %w", err)
+	}
+
+	return &grpcAccounts{conn: conn, client: proto.NewAccountClient(conn)}, nil
+}
+
+func (g *grpcAccounts) List(ctx context.Context, opts ...ReadOption) ([]models.Account, error) {
+	o := readOptionsOf(opts)
+	reply, err := g.client.List(ctx, &proto.ListAccountsRequest{IncludeDeleted: o.includeDeleted, LabelSelector: o.labelSelector})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	accounts := make([]models.Account, 0, len(reply.GetAccounts()))
+	for _, account := range reply.GetAccounts() {
+		accounts = append(accounts, fromGRPC(account))
+	}
+
+	return accounts, nil
+}
+
+func (g *grpcAccounts) Get(ctx context.Context, name string, opts ...ReadOption) (Account, error) {
+	o := readOptionsOf(opts)
+	reply, err := g.client.Get(ctx, &proto.GetAccountRequest{Name: name, IncludeDeleted: o.includeDeleted, LabelSelector: o.labelSelector})
+	if err != nil {
+		return Account{}, errs.FromStatus(err)
+	}
+
+	return Account{Account: fromGRPC(reply), RedirectedFrom: reply.GetRedirectedFrom()}, nil
+}
+
+func (g *grpcAccounts) Create(ctx context.Context, name string, amount int) (models.Account, error) {
+	reply, err := g.client.Create(ctx, &proto.CreateAccountRequest{Name: name, Amount: int32(amount)})
+	if err != nil {
+		return models.Account{}, errs.FromStatus(err)
+	}
+
+	return fromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) SetAmount(ctx context.Context, name string, amount int) (models.Account, error) {
+	reply, err := g.client.ChangeAmount(ctx, &proto.PatchAccountRequest{Name: name, Amount: int32(amount)})
+	if err != nil {
+		return models.Account{}, errs.FromStatus(err)
+	}
+
+	return fromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) Rename(ctx context.Context, name, newName string) (models.Account, error) {
+	reply, err := g.client.ChangeName(ctx, &proto.ChangeAccountRequest{Name: name, NewName: newName})
+	if err != nil {
+		return models.Account{}, errs.FromStatus(err)
+	}
+
+	return fromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) Delete(ctx context.Context, name string) error {
+	_, err := g.client.Delete(ctx, &proto.DeleteAccountRequest{Name: name})
+
+	return errs.FromStatus(err)
+}
+
+func (g *grpcAccounts) Restore(ctx context.Context, name string) (models.Account, error) {
+	reply, err := g.client.Restore(ctx, &proto.RestoreAccountRequest{Name: name})
+	if err != nil {
+		return models.Account{}, errs.FromStatus(err)
+	}
+
+	return fromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) ChangeStatus(ctx context.Context, name string, change models.Change, reason string) (models.Account, error) {
+	var call func(ctx context.Context, in *proto.ChangeStatusRequest, opts ...grpc.CallOption) (*proto.GetAccountReply, error)
+	switch change.Name {
+	case models.Activate.Name:
+		call = g.client.Activate
+	case models.Freeze.Name:
+		call = g.client.Freeze
+	case models.Unfreeze.Name:
+		call = g.client.Unfreeze
+	case models.Close.Name:
+		call = g.client.Close
+	default:
+		return models.Account{}, fmt.Errorf("unknown status change %q", change.Name)
+	}
+
+	reply, err := call(ctx, &proto.ChangeStatusRequest{Name: name, Reason: reason})
+	if err != nil {
+		return models.Account{}, errs.FromStatus(err)
+	}
+
+	return fromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) StatusHistory(ctx context.Context, name string) ([]models.Transition, error) {
+	reply, err := g.client.StatusHistory(ctx, &proto.StatusHistoryRequest{Name: name})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.Transition, 0, len(reply.GetTransitions()))
+	for _, t := range reply.GetTransitions() {
+		result = append(result, models.Transition{
+			From:   models.Status(t.GetFrom()),
+			To:     models.Status(t.GetTo()),
+			Reason: t.GetReason(),
+			Actor:  t.GetActor(),
+			At:     t.GetAt().AsTime(),
+		})
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) RenameHistory(ctx context.Context, name string) ([]models.Rename, error) {
+	reply, err := g.client.RenameHistory(ctx, &proto.RenameHistoryRequest{Name: name})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.Rename, 0, len(reply.GetRenames()))
+	for _, r := range reply.GetRenames() {
+		result = append(result, models.Rename{From: r.GetFrom(), To: r.GetTo(), At: r.GetAt().AsTime()})
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) Edit(ctx context.Context, name string, change models.MetadataChange) (models.Account, error) {
+	mask, description, labels := editMask(change)
+	reply, err := g.client.Update(ctx, &proto.UpdateAccountRequest{
+		Name:        name,
+		Description: description,
+		Labels:      labels,
+		UpdateMask:  &fieldmaskpb.FieldMask{Paths: mask},
+	})
+	if err != nil {
+		return models.Account{}, errs.FromStatus(err)
+	}
+
+	return fromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) Authorize(ctx context.Context, name string, amount int, ttl time.Duration) (models.Hold, error) {
+	reply, err := g.client.Authorize(ctx, &proto.AuthorizeRequest{Name: name, Amount: int32(amount), TtlSeconds: int32(ttl / time.Second)})
+	if err != nil {
+		return models.Hold{}, errs.FromStatus(err)
+	}
+
+	return holdFromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) Capture(ctx context.Context, holdID string, amount int) (models.Hold, models.Account, error) {
+	reply, err := g.client.Capture(ctx, &proto.CaptureRequest{HoldId: holdID, Amount: int32(amount)})
+	if err != nil {
+		return models.Hold{}, models.Account{}, errs.FromStatus(err)
+	}
+
+	return holdFromGRPC(reply.GetHold()), fromGRPC(reply.GetAccount()), nil
+}
+
+func (g *grpcAccounts) Release(ctx context.Context, holdID string) (models.Hold, error) {
+	reply, err := g.client.Release(ctx, &proto.ReleaseRequest{HoldId: holdID})
+	if err != nil {
+		return models.Hold{}, errs.FromStatus(err)
+	}
+
+	return holdFromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) Holds(ctx context.Context, name string) ([]models.Hold, error) {
+	reply, err := g.client.ListHolds(ctx, &proto.ListHoldsRequest{Name: name})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.Hold, 0, len(reply.GetHolds()))
+	for _, hold := range reply.GetHolds() {
+		result = append(result, holdFromGRPC(hold))
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) CreateSchedule(ctx context.Context, schedule models.Schedule) (models.Schedule, error) {
+	req := &proto.CreateScheduleRequest{
+		From:        schedule.From,
+		To:          schedule.To,
+		Amount:      int32(schedule.Amount),
+		Cron:        schedule.Cron,
+		Description: schedule.Description,
+	}
+	if !schedule.NextRunAt.IsZero() {
+		req.RunAt = timestamppb.New(schedule.NextRunAt)
+	}
+	reply, err := g.client.CreateSchedule(ctx, req)
+	if err != nil {
+		return models.Schedule{}, errs.FromStatus(err)
+	}
+
+	return scheduleFromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) Schedules(ctx context.Context, name string) ([]models.Schedule, error) {
+	reply, err := g.client.ListSchedules(ctx, &proto.ListSchedulesRequest{Account: name})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.Schedule, 0, len(reply.GetSchedules()))
+	for _, schedule := range reply.GetSchedules() {
+		result = append(result, scheduleFromGRPC(schedule))
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) ChangeSchedule(ctx context.Context, id string, action models.ScheduleAction) (models.Schedule, error) {
+	var call func(ctx context.Context, in *proto.ScheduleRequest, opts ...grpc.CallOption) (*proto.Schedule, error)
+	switch action {
+	case models.SchedulePause:
+		call = g.client.PauseSchedule
+	case models.ScheduleResume:
+		call = g.client.ResumeSchedule
+	case models.ScheduleCancel:
+		call = g.client.CancelSchedule
+	default:
+		return models.Schedule{}, fmt.Errorf("unknown schedule action %q", action)
+	}
+
+	reply, err := call(ctx, &proto.ScheduleRequest{ScheduleId: id})
+	if err != nil {
+		return models.Schedule{}, errs.FromStatus(err)
+	}
+
+	return scheduleFromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) ScheduleRuns(ctx context.Context, id string) ([]models.ScheduleRun, error) {
+	reply, err := g.client.ScheduleRuns(ctx, &proto.ScheduleRequest{ScheduleId: id})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.ScheduleRun, 0, len(reply.GetRuns()))
+	for _, r := range reply.GetRuns() {
+		result = append(result, models.ScheduleRun{
+			ScheduleID: id,
+			DueAt:      r.GetDueAt().AsTime(),
+			Status:     models.RunStatus(r.GetStatus()),
+			Error:      r.GetError(),
+			RanAt:      r.GetRanAt().AsTime(),
+		})
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) AccrueInterest(ctx context.Context, period models.Period, dryRun bool) (models.Accrual, []models.Posting, error) {
+	reply, err := g.client.AccrueInterest(ctx, &proto.AccrueInterestRequest{Period: period.ID(), DryRun: dryRun})
+	if err != nil {
+		return models.Accrual{}, nil, errs.FromStatus(err)
+	}
+
+	postings := make([]models.Posting, 0, len(reply.GetProjected()))
+	for _, p := range reply.GetProjected() {
+		postings = append(postings, models.Posting{
+			AccountID: p.GetAccountId(),
+			Name:      p.GetName(),
+			Type:      p.GetType(),
+			Balance:   int(p.GetBalance()),
+			Rate:      int(p.GetRateBps()),
+			Amount:    int(p.GetAmount()),
+		})
+	}
+
+	return accrualFromGRPC(reply), postings, nil
+}
+
+func (g *grpcAccounts) Accruals(ctx context.Context) ([]models.Accrual, error) {
+	reply, err := g.client.ListAccruals(ctx, &proto.Empty{})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.Accrual, 0, len(reply.GetAccruals()))
+	for _, accrual := range reply.GetAccruals() {
+		result = append(result, accrualFromGRPC(accrual))
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) Ledger(ctx context.Context, name string) ([]models.LedgerEntry, error) {
+	reply, err := g.client.Ledger(ctx, &proto.LedgerRequest{Name: name})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.LedgerEntry, 0, len(reply.GetEntries()))
+	for _, e := range reply.GetEntries() {
+		result = append(result, models.LedgerEntry{
+			ID:        e.GetId(),
+			Kind:      models.EntryKind(e.GetKind()),
+			Amount:    int(e.GetAmount()),
+			Balance:   int(e.GetBalance()),
+			Reference: e.GetReference(),
+			PostedAt:  e.GetPostedAt().AsTime(),
+		})
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) CreateWebhook(ctx context.Context, subscription models.Subscription) (models.Subscription, error) {
+	request := &proto.CreateWebhookRequest{Url: subscription.URL, Accounts: subscription.Accounts, Secret: subscription.Secret}
+	for _, eventType := range subscription.EventTypes {
+		request.EventTypes = append(request.EventTypes, string(eventType))
+	}
+	reply, err := g.client.CreateWebhook(ctx, request)
+	if err != nil {
+		return models.Subscription{}, errs.FromStatus(err)
+	}
+
+	return webhookFromGRPC(reply), nil
+}
+
+func (g *grpcAccounts) Webhooks(ctx context.Context) ([]models.Subscription, error) {
+	reply, err := g.client.ListWebhooks(ctx, &proto.Empty{})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.Subscription, 0, len(reply.GetWebhooks()))
+	for _, webhook := range reply.GetWebhooks() {
+		result = append(result, webhookFromGRPC(webhook))
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) DeleteWebhook(ctx context.Context, id string) error {
+	if _, err := g.client.DeleteWebhook(ctx, &proto.WebhookRequest{WebhookId: id}); err != nil {
+		return errs.FromStatus(err)
+	}
+
+	return nil
+}
+
+func (g *grpcAccounts) Deliveries(ctx context.Context, id string, status models.DeliveryStatus) ([]models.Delivery, error) {
+	reply, err := g.client.ListDeliveries(ctx, &proto.ListDeliveriesRequest{WebhookId: id, Status: string(status)})
+	if err != nil {
+		return nil, errs.FromStatus(err)
+	}
+
+	result := make([]models.Delivery, 0, len(reply.GetDeliveries()))
+	for _, delivery := range reply.GetDeliveries() {
+		result = append(result, deliveryFromGRPC(id, delivery))
+	}
+
+	return result, nil
+}
+
+func (g *grpcAccounts) ReplayDelivery(ctx context.Context, id, deliveryID string) (models.Delivery, error) {
+	reply, err := g.client.ReplayDelivery(ctx, &proto.ReplayDeliveryRequest{WebhookId: id, DeliveryId: deliveryID})
+	if err != nil {
+		return models.Delivery{}, errs.FromStatus(err)
+	}
+
+	return deliveryFromGRPC(id, reply), nil
+}
+
+func (g *grpcAccounts) Close() error {
+	return g.conn.Close()
+}
+
+// actorInterceptor добавляет к каждому вызову метаданные rpc.ActorMetadata.
+func actorInterceptor(actor string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, rpc.ActorMetadata, actor)
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func fromGRPC(account *proto.GetAccountReply) models.Account {
+	result := models.Account{
+		ID:          account.GetId(),
+		Name:        account.GetName(),
+		Amount:      int(account.GetAmount()),
+		Held:        int(account.GetHeld()),
+		Status:      models.Status(account.GetStatus()),
+		DeletedBy:   account.GetDeletedBy(),
+		Description: account.GetDescription(),
+		Labels:      account.GetLabels(),
+		CreatedAt:   account.GetCreatedAt().AsTime(),
+		UpdatedAt:   account.GetUpdatedAt().AsTime(),
+	}
+	if account.GetDeletedAt() != nil {
+		result.DeletedAt = account.GetDeletedAt().AsTime()
+	}
+
+	return result
+}
+
+func holdFromGRPC(hold *proto.Hold) models.Hold {
+	result := models.Hold{
+		ID:        hold.GetId(),
+		AccountID: hold.GetAccountId(),
+		Amount:    int(hold.GetAmount()),
+		Status:    models.HoldStatus(hold.GetStatus()),
+		Captured:  int(hold.GetCaptured()),
+		CreatedAt: hold.GetCreatedAt().AsTime(),
+		ExpiresAt: hold.GetExpiresAt().AsTime(),
+	}
+	if hold.GetClosedAt() != nil {
+		result.ClosedAt = hold.GetClosedAt().AsTime()
+	}
+
+	return result
+}
+
+func scheduleFromGRPC(schedule *proto.Schedule) models.Schedule {
+	result := models.Schedule{
+		ID:          schedule.GetId(),
+		From:        schedule.GetFrom(),
+		To:          schedule.GetTo(),
+		Amount:      int(schedule.GetAmount()),
+		Cron:        schedule.GetCron(),
+		Description: schedule.GetDescription(),
+		Status:      models.ScheduleStatus(schedule.GetStatus()),
+		CreatedAt:   schedule.GetCreatedAt().AsTime(),
+		UpdatedAt:   schedule.GetUpdatedAt().AsTime(),
+	}
+	if schedule.GetNextRunAt() != nil {
+		result.NextRunAt = schedule.GetNextRunAt().AsTime()
+	}
+
+	return result
+}
+
+func accrualFromGRPC(accrual *proto.Accrual) models.Accrual {
+	result := models.Accrual{
+		ID:       accrual.GetId(),
+		Period:   models.Period{Compounding: models.Compounding(accrual.GetCompounding()), Start: accrual.GetPeriodStart().AsTime()},
+		Status:   models.AccrualStatus(accrual.GetStatus()),
+		Cursor:   accrual.GetCursor(),
+		Postings: int(accrual.GetPostings()),
+		Total:    int(accrual.GetTotal()),
+	}
+	if accrual.GetStartedAt() != nil {
+		result.StartedAt = accrual.GetStartedAt().AsTime()
+	}
+	if accrual.GetFinishedAt() != nil {
+		result.FinishedAt = accrual.GetFinishedAt().AsTime()
+	}
+
+	return result
+}
+
+func webhookFromGRPC(webhook *proto.Webhook) models.Subscription {
+	result := models.Subscription{
+		ID:        webhook.GetId(),
+		URL:       webhook.GetUrl(),
+		Secret:    webhook.GetSecret(),
+		Accounts:  webhook.GetAccounts(),
+		CreatedAt: webhook.GetCreatedAt().AsTime(),
+	}
+	for _, eventType := range webhook.GetEventTypes() {
+		result.EventTypes = append(result.EventTypes, models.EventType(eventType))
+	}
+
+	return result
+}
+
+func deliveryFromGRPC(subscriptionID string, delivery *proto.Delivery) models.Delivery {
+	result := models.Delivery{
+		ID:             delivery.GetId(),
+		SubscriptionID: subscriptionID,
+		EventID:        delivery.GetEventId(),
+		EventType:      models.EventType(delivery.GetEventType()),
+		AccountID:      delivery.GetAccountId(),
+		Status:         models.DeliveryStatus(delivery.GetStatus()),
+		Attempts:       int(delivery.GetAttempts()),
+		NextAttemptAt:  delivery.GetNextAttemptAt().AsTime(),
+		LastError:      delivery.GetLastError(),
+		LastStatusCode: int(delivery.GetLastStatusCode()),
+		CreatedAt:      delivery.GetCreatedAt().AsTime(),
+	}
+	if delivery.GetDeliveredAt() != nil {
+		result.DeliveredAt = delivery.GetDeliveredAt().AsTime()
+	}
+
+	return result
+}
@@ -0,0 +1,465 @@
+package transport
+
+import (
+	"awesomeProject/accounts/client"
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/models"
+	"context"
+	"time"
+)
+
+type httpAccounts struct {
+	client *client.Client
+}
+
+func NewHTTP(c *client.Client) Accounts {
+	return &httpAccounts{client: c}
+}
+
+func (h *httpAccounts) List(ctx context.Context, opts ...ReadOption) ([]models.Account, error) {
+	accounts, err := h.client.List(ctx, clientReadOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Account, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, fromHTTP(account))
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) Get(ctx context.Context, name string, opts ...ReadOption) (Account, error) {
+	account, err := h.client.Get(ctx, name, clientReadOptions(opts)...)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return Account{Account: fromHTTP(account), RedirectedFrom: account.RedirectedFrom}, nil
+}
+
+func (h *httpAccounts) Create(ctx context.Context, name string, amount int) (models.Account, error) {
+	account, err := h.client.Create(ctx, name, amount)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return fromHTTP(account), nil
+}
+
+func (h *httpAccounts) SetAmount(ctx context.Context, name string, amount int) (models.Account, error) {
+	account, err := h.client.SetAmount(ctx, name, amount)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return fromHTTP(account), nil
+}
+
+func (h *httpAccounts) Rename(ctx context.Context, name, newName string) (models.Account, error) {
+	account, err := h.client.Rename(ctx, name, newName)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return fromHTTP(account), nil
+}
+
+func (h *httpAccounts) Delete(ctx context.Context, name string) error {
+	return h.client.Delete(ctx, name)
+}
+
+func (h *httpAccounts) Restore(ctx context.Context, name string) (models.Account, error) {
+	account, err := h.client.Restore(ctx, name)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return fromHTTP(account), nil
+}
+
+func (h *httpAccounts) ChangeStatus(ctx context.Context, name string, change models.Change, reason string) (models.Account, error) {
+	account, err := h.client.ChangeStatus(ctx, name, change.Name, reason)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return fromHTTP(account), nil
+}
+
+func (h *httpAccounts) StatusHistory(ctx context.Context, name string) ([]models.Transition, error) {
+	transitions, err := h.client.StatusHistory(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Transition, 0, len(transitions))
+	for _, t := range transitions {
+		result = append(result, models.Transition{
+			From:   models.Status(t.From),
+			To:     models.Status(t.To),
+			Reason: t.Reason,
+			Actor:  t.Actor,
+			At:     t.At,
+		})
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) RenameHistory(ctx context.Context, name string) ([]models.Rename, error) {
+	renames, err := h.client.RenameHistory(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Rename, 0, len(renames))
+	for _, r := range renames {
+		result = append(result, models.Rename{From: r.From, To: r.To, At: r.At})
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) Edit(ctx context.Context, name string, change models.MetadataChange) (models.Account, error) {
+	mask, description, labels := editMask(change)
+	request := dto.UpdateAccountRequest{Labels: labels}
+	if change.Description != nil {
+		request.Description = &description
+	}
+	account, err := h.client.Update(ctx, name, request, mask...)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return fromHTTP(account), nil
+}
+
+func (h *httpAccounts) Authorize(ctx context.Context, name string, amount int, ttl time.Duration) (models.Hold, error) {
+	hold, err := h.client.Authorize(ctx, name, amount, ttl)
+	if err != nil {
+		return models.Hold{}, err
+	}
+
+	return holdFromHTTP(hold), nil
+}
+
+func (h *httpAccounts) Capture(ctx context.Context, holdID string, amount int) (models.Hold, models.Account, error) {
+	hold, account, err := h.client.Capture(ctx, holdID, amount)
+	if err != nil {
+		return models.Hold{}, models.Account{}, err
+	}
+
+	return holdFromHTTP(hold), fromHTTP(account), nil
+}
+
+func (h *httpAccounts) Release(ctx context.Context, holdID string) (models.Hold, error) {
+	hold, err := h.client.Release(ctx, holdID)
+	if err != nil {
+		return models.Hold{}, err
+	}
+
+	return holdFromHTTP(hold), nil
+}
+
+func (h *httpAccounts) Holds(ctx context.Context, name string) ([]models.Hold, error) {
+	holds, err := h.client.Holds(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Hold, 0, len(holds))
+	for _, hold := range holds {
+		result = append(result, holdFromHTTP(hold))
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) CreateSchedule(ctx context.Context, schedule models.Schedule) (models.Schedule, error) {
+	request := dto.CreateScheduleRequest{
+		From:        schedule.From,
+		To:          schedule.To,
+		Amount:      schedule.Amount,
+		Cron:        schedule.Cron,
+		Description: schedule.Description,
+	}
+	if !schedule.NextRunAt.IsZero() {
+		request.RunAt = &schedule.NextRunAt
+	}
+	created, err := h.client.CreateSchedule(ctx, request)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+
+	return scheduleFromHTTP(created), nil
+}
+
+func (h *httpAccounts) Schedules(ctx context.Context, name string) ([]models.Schedule, error) {
+	schedules, err := h.client.Schedules(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		result = append(result, scheduleFromHTTP(schedule))
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) ChangeSchedule(ctx context.Context, id string, action models.ScheduleAction) (models.Schedule, error) {
+	schedule, err := h.client.ChangeSchedule(ctx, id, string(action))
+	if err != nil {
+		return models.Schedule{}, err
+	}
+
+	return scheduleFromHTTP(schedule), nil
+}
+
+func (h *httpAccounts) ScheduleRuns(ctx context.Context, id string) ([]models.ScheduleRun, error) {
+	runs, err := h.client.ScheduleRuns(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ScheduleRun, 0, len(runs))
+	for _, r := range runs {
+		result = append(result, models.ScheduleRun{ScheduleID: id, DueAt: r.DueAt, Status: models.RunStatus(r.Status), Error: r.Error, RanAt: r.RanAt})
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) AccrueInterest(ctx context.Context, period models.Period, dryRun bool) (models.Accrual, []models.Posting, error) {
+	accrual, err := h.client.AccrueInterest(ctx, period.ID(), dryRun)
+	if err != nil {
+		return models.Accrual{}, nil, err
+	}
+
+	postings := make([]models.Posting, 0, len(accrual.Projected))
+	for _, p := range accrual.Projected {
+		postings = append(postings, models.Posting{AccountID: p.AccountID, Name: p.Name, Type: p.Type, Balance: p.Balance, Rate: p.RateBPS, Amount: p.Amount})
+	}
+
+	return accrualFromHTTP(accrual), postings, nil
+}
+
+func (h *httpAccounts) Accruals(ctx context.Context) ([]models.Accrual, error) {
+	accruals, err := h.client.Accruals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Accrual, 0, len(accruals))
+	for _, accrual := range accruals {
+		result = append(result, accrualFromHTTP(accrual))
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) Ledger(ctx context.Context, name string) ([]models.LedgerEntry, error) {
+	entries, err := h.client.Ledger(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.LedgerEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, models.LedgerEntry{
+			ID:        e.ID,
+			Kind:      models.EntryKind(e.Kind),
+			Amount:    e.Amount,
+			Balance:   e.Balance,
+			Reference: e.Reference,
+			PostedAt:  e.PostedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) CreateWebhook(ctx context.Context, subscription models.Subscription) (models.Subscription, error) {
+	request := dto.CreateWebhookRequest{URL: subscription.URL, Accounts: subscription.Accounts, Secret: subscription.Secret}
+	for _, eventType := range subscription.EventTypes {
+		request.EventTypes = append(request.EventTypes, string(eventType))
+	}
+	webhook, err := h.client.CreateWebhook(ctx, request)
+	if err != nil {
+		return models.Subscription{}, err
+	}
+
+	return webhookFromHTTP(webhook), nil
+}
+
+func (h *httpAccounts) Webhooks(ctx context.Context) ([]models.Subscription, error) {
+	webhooks, err := h.client.Webhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Subscription, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		result = append(result, webhookFromHTTP(webhook))
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) DeleteWebhook(ctx context.Context, id string) error {
+	return h.client.DeleteWebhook(ctx, id)
+}
+
+func (h *httpAccounts) Deliveries(ctx context.Context, id string, status models.DeliveryStatus) ([]models.Delivery, error) {
+	deliveries, err := h.client.Deliveries(ctx, id, string(status))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, deliveryFromHTTP(id, delivery))
+	}
+
+	return result, nil
+}
+
+func (h *httpAccounts) ReplayDelivery(ctx context.Context, id, deliveryID string) (models.Delivery, error) {
+	delivery, err := h.client.ReplayDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return models.Delivery{}, err
+	}
+
+	return deliveryFromHTTP(id, delivery), nil
+}
+
+func (h *httpAccounts) Close() error {
+	return nil
+}
+
+func clientReadOptions(opts []ReadOption) []client.ReadOption {
+	o := readOptionsOf(opts)
+	var result []client.ReadOption
+	if o.includeDeleted {
+		result = append(result, client.IncludeDeleted())
+	}
+	if o.labelSelector != "" {
+		result = append(result, client.LabelSelector(o.labelSelector))
+	}
+
+	return result
+}
+
+func fromHTTP(account client.Account) models.Account {
+	result := models.Account{
+		ID:          account.ID,
+		Name:        account.Name,
+		Amount:      account.Amount,
+		Held:        account.Held,
+		Status:      models.Status(account.Status),
+		DeletedBy:   account.DeletedBy,
+		Description: account.Description,
+		Labels:      account.Labels,
+		CreatedAt:   account.CreatedAt,
+		UpdatedAt:   account.UpdatedAt,
+	}
+	if account.DeletedAt != nil {
+		result.DeletedAt = *account.DeletedAt
+	}
+
+	return result
+}
+
+func holdFromHTTP(hold client.Hold) models.Hold {
+	result := models.Hold{
+		ID:        hold.ID,
+		AccountID: hold.AccountID,
+		Amount:    hold.Amount,
+		Status:    models.HoldStatus(hold.Status),
+		Captured:  hold.Captured,
+		CreatedAt: hold.CreatedAt,
+		ExpiresAt: hold.ExpiresAt,
+	}
+	if hold.ClosedAt != nil {
+		result.ClosedAt = *hold.ClosedAt
+	}
+
+	return result
+}
+
+func scheduleFromHTTP(schedule client.Schedule) models.Schedule {
+	result := models.Schedule{
+		ID:          schedule.ID,
+		From:        schedule.From,
+		To:          schedule.To,
+		Amount:      schedule.Amount,
+		Cron:        schedule.Cron,
+		Description: schedule.Description,
+		Status:      models.ScheduleStatus(schedule.Status),
+		CreatedAt:   schedule.CreatedAt,
+		UpdatedAt:   schedule.UpdatedAt,
+	}
+	if schedule.NextRunAt != nil {
+		result.NextRunAt = *schedule.NextRunAt
+	}
+
+	return result
+}
+
+func accrualFromHTTP(accrual client.Accrual) models.Accrual {
+	result := models.Accrual{
+		ID:       accrual.ID,
+		Period:   models.Period{Compounding: models.Compounding(accrual.Compounding), Start: accrual.PeriodStart},
+		Status:   models.AccrualStatus(accrual.Status),
+		Cursor:   accrual.Cursor,
+		Postings: accrual.Postings,
+		Total:    accrual.Total,
+	}
+	if accrual.StartedAt != nil {
+		result.StartedAt = *accrual.StartedAt
+	}
+	if accrual.FinishedAt != nil {
+		result.FinishedAt = *accrual.FinishedAt
+	}
+
+	return result
+}
+
+func webhookFromHTTP(webhook client.Webhook) models.Subscription {
+	result := models.Subscription{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Accounts:  webhook.Accounts,
+		CreatedAt: webhook.CreatedAt,
+	}
+	for _, eventType := range webhook.EventTypes {
+		result.EventTypes = append(result.EventTypes, models.EventType(eventType))
+	}
+
+	return result
+}
+
+func deliveryFromHTTP(subscriptionID string, delivery client.Delivery) models.Delivery {
+	result := models.Delivery{
+		ID:             delivery.ID,
+		SubscriptionID: subscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      models.EventType(delivery.EventType),
+		AccountID:      delivery.AccountID,
+		Status:         models.DeliveryStatus(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastError:      delivery.LastError,
+		LastStatusCode: delivery.LastStatusCode,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = *delivery.DeliveredAt
+	}
+
+	return result
+}
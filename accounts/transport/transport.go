@@ -0,0 +1,161 @@
+package transport
+
+import (
+	"awesomeProject/accounts/client"
+	"awesomeProject/accounts/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	HTTP = "http"
+	GRPC = "grpc"
+)
+
+// Accounts — операции над аккаунтами, одинаковые для HTTP и gRPC.
+// Ошибки сервера возвращаются как *errs.Error независимо от транспорта.
+// Существующий аккаунт можно указать в name его ID или именем.
+type Accounts interface {
+	List(ctx context.Context, opts ...ReadOption) ([]models.Account, error)
+	Get(ctx context.Context, name string, opts ...ReadOption) (Account, error)
+	Create(ctx context.Context, name string, amount int) (models.Account, error)
+	SetAmount(ctx context.Context, name string, amount int) (models.Account, error)
+	Rename(ctx context.Context, name, newName string) (models.Account, error)
+	Delete(ctx context.Context, name string) error
+	Restore(ctx context.Context, name string) (models.Account, error)
+	ChangeStatus(ctx context.Context, name string, change models.Change, reason string) (models.Account, error)
+	StatusHistory(ctx context.Context, name string) ([]models.Transition, error)
+	RenameHistory(ctx context.Context, name string) ([]models.Rename, error)
+	// Edit меняет описание и метки аккаунта.
+	Edit(ctx context.Context, name string, change models.MetadataChange) (models.Account, error)
+	// Authorize резервирует amount на время ttl; ttl 0 — срок сервера по умолчанию.
+	Authorize(ctx context.Context, name string, amount int, ttl time.Duration) (models.Hold, error)
+	// Capture списывает холд; amount 0 списывает его целиком.
+	Capture(ctx context.Context, holdID string, amount int) (models.Hold, models.Account, error)
+	Release(ctx context.Context, holdID string) (models.Hold, error)
+	Holds(ctx context.Context, name string) ([]models.Hold, error)
+	// CreateSchedule заводит перевод по расписанию; From и To принимают ID или имена.
+	CreateSchedule(ctx context.Context, schedule models.Schedule) (models.Schedule, error)
+	// Schedules возвращает расписания аккаунта name; пустое name — все расписания.
+	Schedules(ctx context.Context, name string) ([]models.Schedule, error)
+	ChangeSchedule(ctx context.Context, id string, action models.ScheduleAction) (models.Schedule, error)
+	ScheduleRuns(ctx context.Context, id string) ([]models.ScheduleRun, error)
+	// AccrueInterest начисляет проценты за период; с dryRun только возвращает расчётные проводки.
+	AccrueInterest(ctx context.Context, period models.Period, dryRun bool) (models.Accrual, []models.Posting, error)
+	Accruals(ctx context.Context) ([]models.Accrual, error)
+	Ledger(ctx context.Context, name string) ([]models.LedgerEntry, error)
+	// CreateWebhook подписывает URL на события; Accounts принимает ID или имена, Secret пустой — сгенерирует сервер.
+	// Секрет есть только у подписки, которую вернул CreateWebhook.
+	CreateWebhook(ctx context.Context, subscription models.Subscription) (models.Subscription, error)
+	Webhooks(ctx context.Context) ([]models.Subscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	// Deliveries возвращает доставки подписки; пустой status — все.
+	Deliveries(ctx context.Context, id string, status models.DeliveryStatus) ([]models.Delivery, error)
+	ReplayDelivery(ctx context.Context, id, deliveryID string) (models.Delivery, error)
+	Close() error
+}
+
+// Account — аккаунт, который вернул Get.
+type Account struct {
+	models.Account
+	// RedirectedFrom — старое имя из запроса, если аккаунт найден по нему после переименования.
+	RedirectedFrom string
+}
+
+// ReadOption настраивает чтение аккаунтов.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	includeDeleted bool
+	labelSelector  string
+}
+
+// IncludeDeleted показывает удалённые, но ещё не очищенные аккаунты.
+func IncludeDeleted() ReadOption {
+	return func(o *readOptions) {
+		o.includeDeleted = true
+	}
+}
+
+// LabelSelector оставляет аккаунты, метки которых удовлетворяют селектору, например "tier=gold,!legacy".
+func LabelSelector(selector string) ReadOption {
+	return func(o *readOptions) {
+		o.labelSelector = selector
+	}
+}
+
+func readOptionsOf(opts []ReadOption) readOptions {
+	var o readOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// editMask переводит изменение описания и меток в маску полей и значения запроса на изменение.
+// Удаляемая метка указывается в маске как labels.<key> и не передаётся в значениях.
+func editMask(change models.MetadataChange) (mask []string, description string, labels map[string]string) {
+	if change.Description != nil {
+		mask = append(mask, "description")
+		description = *change.Description
+	}
+	labels = change.SetLabels
+	if change.ReplaceLabels {
+		return append(mask, "labels"), description, labels
+	}
+	for key := range change.SetLabels {
+		mask = append(mask, "labels."+key)
+	}
+	for _, key := range change.RemoveLabels {
+		mask = append(mask, "labels."+key)
+	}
+
+	return mask, description, labels
+}
+
+// DialOption настраивает соединение.
+type DialOption func(*dialOptions)
+
+type dialOptions struct {
+	actor string
+}
+
+// WithActor представляет клиента серверу: имя записывается, например, как автор удаления.
+func WithActor(actor string) DialOption {
+	return func(o *dialOptions) {
+		o.actor = actor
+	}
+}
+
+// Dial открывает соединение выбранного транспорта.
+// Для http адрес может быть как host:port, так и полным URL.
+func Dial(kind, address string, opts ...DialOption) (Accounts, error) {
+	var o dialOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch kind {
+	case HTTP:
+		if !strings.Contains(address, "://") {
+			address = "http://" + address
+		}
+		var clientOpts []client.Option
+		if o.actor != "" {
+			clientOpts = append(clientOpts, client.WithActor(o.actor))
+		}
+		c, err := client.New(address, clientOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewHTTP(c), nil
+	case GRPC:
+		return DialGRPC(address, opts...)
+	default:
+		return nil, fmt.Errorf("unknown transport %q, expected %s or %s", kind, HTTP, GRPC)
+	}
+}
@@ -0,0 +1,85 @@
+package transport_test
+
+import (
+	"awesomeProject/accounts"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/rpc"
+	"awesomeProject/accounts/storage"
+	"awesomeProject/accounts/transport"
+	"awesomeProject/proto"
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+)
+
+const aliasTTL = time.Hour
+
+// serve поднимает сервер выбранного транспорта поверх store и возвращает соединение с ним.
+func serve(t *testing.T, kind string, store storage.Storage) transport.Accounts {
+	t.Helper()
+	var address string
+	switch kind {
+	case transport.HTTP:
+		e := echo.New()
+		e.HTTPErrorHandler = accounts.ErrorHandler
+		accounts.New(store, accounts.WithRenameAliases(aliasTTL)).Register(e)
+		server := httptest.NewServer(e)
+		t.Cleanup(server.Close)
+		address = server.URL
+	case transport.GRPC:
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := grpc.NewServer(grpc.UnaryInterceptor(errs.UnaryServerInterceptor))
+		proto.RegisterAccountServer(server, rpc.New(store, rpc.WithRenameAliases(aliasTTL)))
+		go func() { _ = server.Serve(listener) }()
+		t.Cleanup(server.Stop)
+		address = listener.Addr().String()
+	}
+
+	conn, err := transport.Dial(kind, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestGetRedirectedFrom(t *testing.T) {
+	for _, kind := range []string{transport.HTTP, transport.GRPC} {
+		t.Run(kind, func(t *testing.T) {
+			ctx := context.Background()
+			conn := serve(t, kind, storage.NewMemory())
+			created, err := conn.Create(ctx, "alice", 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := conn.Rename(ctx, "alice", "bob"); err != nil {
+				t.Fatal(err)
+			}
+
+			account, err := conn.Get(ctx, "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if account.ID != created.ID || account.Name != "bob" || account.RedirectedFrom != "alice" {
+				t.Fatalf("get by old name = %+v, want bob redirected from alice", account)
+			}
+
+			account, err = conn.Get(ctx, "bob")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if account.RedirectedFrom != "" {
+				t.Fatalf("get by current name redirected from %q", account.RedirectedFrom)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"strings"
+)
+
+// Пути маски полей при изменении аккаунта. labels заменяет все метки,
+// labels.<key> — одну метку: ставит значение из запроса или удаляет метку, если в запросе её нет.
+const (
+	PathName        = "name"
+	PathAmount      = "amount"
+	PathDescription = "description"
+	PathLabels      = "labels"
+)
+
+// UpdateFields — новые значения из запроса на изменение; nil — поле не передано.
+type UpdateFields struct {
+	Name        *string
+	Amount      *int
+	Description *string
+	Labels      map[string]string
+}
+
+// PresentPaths — маска из переданных полей: так PATCH без update_mask меняет всё, что передано.
+func (f UpdateFields) PresentPaths() []string {
+	var paths []string
+	if f.Name != nil {
+		paths = append(paths, PathName)
+	}
+	if f.Amount != nil {
+		paths = append(paths, PathAmount)
+	}
+	if f.Description != nil {
+		paths = append(paths, PathDescription)
+	}
+	if f.Labels != nil {
+		paths = append(paths, PathLabels)
+	}
+
+	return paths
+}
+
+// Update проверяет маску field и значения полей и собирает изменение.
+// Поле из маски, не переданное в запросе, очищается; имя и баланс очистить нельзя.
+func (v *Validator) Update(field string, mask []string, fields UpdateFields) models.Update {
+	var update models.Update
+	metadata := &update.Metadata
+
+	for _, path := range mask {
+		switch {
+		case path == PathName:
+			if fields.Name == nil {
+				v.missing(PathName)
+				continue
+			}
+			name := v.Name(PathName, *fields.Name)
+			update.Name = &name
+		case path == PathAmount:
+			if fields.Amount == nil {
+				v.missing(PathAmount)
+				continue
+			}
+			v.Amount(PathAmount, *fields.Amount)
+			update.Amount = fields.Amount
+		case path == PathDescription:
+			var description string
+			if fields.Description != nil {
+				description = v.Description(PathDescription, *fields.Description)
+			}
+			metadata.Description = &description
+		case path == PathLabels:
+			metadata.ReplaceLabels = true
+			for key, value := range v.Labels(PathLabels, fields.Labels) {
+				setLabel(metadata, key, value)
+			}
+		case strings.HasPrefix(path, PathLabels+"."):
+			key := strings.TrimPrefix(path, PathLabels+".")
+			v.LabelKey(field, key)
+			if value, ok := fields.Labels[key]; ok {
+				Check(v, path, value, LabelValue...)
+				setLabel(metadata, key, value)
+			} else {
+				metadata.RemoveLabels = append(metadata.RemoveLabels, key)
+			}
+		default:
+			Check(v, field, path, OneOf(PathName, PathAmount, PathDescription, PathLabels, PathLabels+".<key>"))
+		}
+	}
+
+	return update
+}
+
+func setLabel(metadata *models.MetadataChange, key, value string) {
+	if metadata.SetLabels == nil {
+		metadata.SetLabels = make(map[string]string)
+	}
+	metadata.SetLabels[key] = value
+}
+
+func (v *Validator) missing(field string) {
+	v.violations = append(v.violations, errs.FieldViolation{
+		Field:       field,
+		Rule:        "required",
+		Description: "must be set when listed in the update mask",
+	})
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"awesomeProject/accounts/models"
+	"reflect"
+	"testing"
+)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestUpdateMask(t *testing.T) {
+	fields := UpdateFields{
+		Name:        ptr("bob"),
+		Amount:      ptr(10),
+		Description: ptr("  savings  "),
+		Labels:      map[string]string{"tier": "gold"},
+	}
+	tests := []struct {
+		name       string
+		mask       []string
+		fields     UpdateFields
+		want       models.Update
+		violations []string
+	}{
+		{name: "name and amount", mask: []string{PathName, PathAmount}, fields: fields,
+			want: models.Update{Name: ptr("bob"), Amount: ptr(10)}},
+		{name: "description is trimmed", mask: []string{PathDescription}, fields: fields,
+			want: models.Update{Metadata: models.MetadataChange{Description: ptr("savings")}}},
+		{name: "description without a value is cleared", mask: []string{PathDescription},
+			want: models.Update{Metadata: models.MetadataChange{Description: ptr("")}}},
+		{name: "labels replace all", mask: []string{PathLabels}, fields: fields,
+			want: models.Update{Metadata: models.MetadataChange{ReplaceLabels: true, SetLabels: map[string]string{"tier": "gold"}}}},
+		{name: "labels without a value clear all", mask: []string{PathLabels},
+			want: models.Update{Metadata: models.MetadataChange{ReplaceLabels: true}}},
+		{name: "label key with a value is set", mask: []string{"labels.tier"}, fields: fields,
+			want: models.Update{Metadata: models.MetadataChange{SetLabels: map[string]string{"tier": "gold"}}}},
+		{name: "label key without a value is removed", mask: []string{"labels.tier", "labels.legacy"}, fields: fields,
+			want: models.Update{Metadata: models.MetadataChange{SetLabels: map[string]string{"tier": "gold"}, RemoveLabels: []string{"legacy"}}}},
+		{name: "name without a value", mask: []string{PathName}, violations: []string{"name:required"}},
+		{name: "amount without a value", mask: []string{PathAmount}, violations: []string{"amount:required"}},
+		{name: "unknown path", mask: []string{"owner"}, violations: []string{"update_mask:one_of"}},
+		{name: "bad label key", mask: []string{"labels.Tier"}, violations: []string{"update_mask:starts_with", "update_mask:charset"}},
+		{name: "empty label key", mask: []string{"labels."}, violations: []string{"update_mask:required"}},
+		{name: "bad label value", mask: []string{"labels.tier"}, fields: UpdateFields{Labels: map[string]string{"tier": "gold tier"}},
+			violations: []string{"labels.tier:charset"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			got := v.Update("update_mask", tt.mask, tt.fields)
+			checkViolations(t, v, tt.violations...)
+			if tt.violations == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Update() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresentPaths(t *testing.T) {
+	got := UpdateFields{Amount: ptr(0), Labels: map[string]string{}}.PresentPaths()
+	if !reflect.DeepEqual(got, []string{PathAmount, PathLabels}) {
+		t.Fatalf("PresentPaths() = %v", got)
+	}
+	if got := (UpdateFields{}).PresentPaths(); got != nil {
+		t.Fatalf("PresentPaths() of nothing = %v", got)
+	}
+}
@@ -0,0 +1,221 @@
+package validation
+
+import (
+	"awesomeProject/accounts/models"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rule — декларативное правило для значения типа T.
+// Name попадает в errs.FieldViolation.Rule, Message — в описание нарушения.
+type Rule[T any] struct {
+	Name    string
+	Message string
+	Valid   func(value T) bool
+}
+
+func Required() Rule[string] {
+	return Rule[string]{
+		Name:    "required",
+		Message: "must not be empty",
+		Valid:   func(value string) bool { return len(value) > 0 },
+	}
+}
+
+// MaxLength ограничивает длину в символах (рунах), а не в байтах.
+func MaxLength(n int) Rule[string] {
+	return Rule[string]{
+		Name:    "max_length",
+		Message: fmt.Sprintf("must be at most %d characters long", n),
+		Valid:   func(value string) bool { return utf8.RuneCountInString(value) <= n },
+	}
+}
+
+// Charset разрешает только символы, для которых allowed возвращает true.
+func Charset(description string, allowed func(r rune) bool) Rule[string] {
+	return Rule[string]{
+		Name:    "charset",
+		Message: "may contain only " + description,
+		Valid: func(value string) bool {
+			for _, r := range value {
+				if !allowed(r) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// StartsWith требует, чтобы первый символ удовлетворял allowed.
+func StartsWith(description string, allowed func(r rune) bool) Rule[string] {
+	return Rule[string]{
+		Name:    "starts_with",
+		Message: "must start with " + description,
+		Valid: func(value string) bool {
+			r, _ := utf8.DecodeRuneInString(value)
+			return len(value) == 0 || allowed(r)
+		},
+	}
+}
+
+// NotReserved запрещает служебные слова без учёта регистра.
+func NotReserved(words ...string) Rule[string] {
+	return Rule[string]{
+		Name:    "reserved",
+		Message: "must not be one of reserved words: " + strings.Join(words, ", "),
+		Valid: func(value string) bool {
+			for _, word := range words {
+				if strings.EqualFold(value, word) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// NotID запрещает значения, которые models.ParseID принимает за идентификатор: ссылка сначала
+// ищется как ID, поэтому такое имя было бы недоступно, а совпав с чужим ID, подменяло бы тот аккаунт.
+func NotID() Rule[string] {
+	return Rule[string]{
+		Name:    "not_id",
+		Message: "must not look like an account ID",
+		Valid: func(value string) bool {
+			_, ok := models.ParseID(value)
+			return !ok
+		},
+	}
+}
+
+// OneOf разрешает только перечисленные значения.
+func OneOf(values ...string) Rule[string] {
+	return Rule[string]{
+		Name:    "one_of",
+		Message: "must be one of: " + strings.Join(values, ", "),
+		Valid: func(value string) bool {
+			for _, v := range values {
+				if value == v {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func Min(n int) Rule[int] {
+	return Rule[int]{
+		Name:    "min",
+		Message: fmt.Sprintf("must be at least %d", n),
+		Valid:   func(value int) bool { return value >= n },
+	}
+}
+
+func Max(n int) Rule[int] {
+	return Rule[int]{
+		Name:    "max",
+		Message: fmt.Sprintf("must be at most %d", n),
+		Valid:   func(value int) bool { return value <= n },
+	}
+}
+
+const (
+	MaxNameLength = 64
+	// MaxReasonLength ограничивает причину смены статуса.
+	MaxReasonLength = 500
+	MinAmount       = 0
+	// MaxAmount совпадает с пределом int32 в proto схеме.
+	MaxAmount = math.MaxInt32
+	// MaxDescriptionLength ограничивает описание аккаунта.
+	MaxDescriptionLength = 500
+	// MaxLabels — сколько меток может быть у аккаунта.
+	MaxLabels           = 64
+	MaxLabelKeyLength   = 63
+	MaxLabelValueLength = 63
+	// MaxURLLength ограничивает адрес вебхука.
+	MaxURLLength = 2048
+	// MinSecretLength и MaxSecretLength ограничивают секрет подписи вебхука.
+	MinSecretLength = 16
+	MaxSecretLength = 256
+)
+
+// ReservedNames нельзя использовать как имя аккаунта.
+var ReservedNames = []string{"admin", "root", "system", "null", "undefined"}
+
+func nameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// AccountName — правила для имени аккаунта; проверяются после нормализации NFC.
+var AccountName = []Rule[string]{
+	Required(),
+	MaxLength(MaxNameLength),
+	StartsWith("a letter or a digit", func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }),
+	Charset("letters, digits, '_', '-' and '.'", nameRune),
+	NotReserved(ReservedNames...),
+	NotID(),
+}
+
+// InitialStatuses — статусы, с которыми можно создать аккаунт.
+var InitialStatuses = []string{string(models.StatusPending), string(models.StatusActive)}
+
+// Reason — правила для причины смены статуса; она нужна для истории, поэтому обязательна.
+var Reason = []Rule[string]{
+	Required(),
+	MaxLength(MaxReasonLength),
+}
+
+// Amount — правила для баланса аккаунта.
+var Amount = []Rule[int]{
+	Min(MinAmount),
+	Max(MaxAmount),
+}
+
+// HoldAmount — правила для суммы холда и его списания.
+var HoldAmount = []Rule[int]{
+	Min(1),
+	Max(MaxAmount),
+}
+
+// TransferAmount — правила для суммы перевода по расписанию.
+var TransferAmount = []Rule[int]{
+	Min(1),
+	Max(MaxAmount),
+}
+
+const (
+	// DefaultHoldTTL — срок холда, если клиент его не указал.
+	DefaultHoldTTL = 15 * time.Minute
+	MaxHoldTTL     = 7 * 24 * time.Hour
+)
+
+func labelRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.'
+}
+
+// LabelKey — правила для ключа метки. Ключ входит в маску полей (labels.<key>)
+// и в селектор меток, поэтому в нём нет запятых, пробелов и знаков сравнения.
+var LabelKey = []Rule[string]{
+	Required(),
+	MaxLength(MaxLabelKeyLength),
+	StartsWith("a lowercase letter or a digit", func(r rune) bool { return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' }),
+	Charset("lowercase letters, digits, '_', '-' and '.'", labelRune),
+}
+
+// LabelValue — правила для значения метки; пустое значение допустимо.
+var LabelValue = []Rule[string]{
+	MaxLength(MaxLabelValueLength),
+	Charset("letters, digits, '_', '-' and '.'", func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+	}),
+}
+
+// Description — правила для описания аккаунта.
+var Description = []Rule[string]{
+	MaxLength(MaxDescriptionLength),
+}
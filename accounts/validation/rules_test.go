@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// violations возвращает правила, которые нарушил валидатор, в порядке проверки.
+func violations(t *testing.T, v *Validator) []string {
+	t.Helper()
+	err := v.Err()
+	if err == nil {
+		return nil
+	}
+	var e *errs.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Err() = %T, want *errs.Error", err)
+	}
+	rules := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		rules = append(rules, violation.Field+":"+violation.Rule)
+	}
+
+	return rules
+}
+
+func checkViolations(t *testing.T, v *Validator, want ...string) {
+	t.Helper()
+	got := violations(t, v)
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("violations = %v, want %v", got, want)
+	}
+}
+
+// TestNameRejectsIDs: имя, похожее на ID, недоступно по имени и подменяет аккаунт с таким ID,
+// поэтому его нельзя ни задать при создании, ни получить переименованием, ни через маску PATCH.
+func TestNameRejectsIDs(t *testing.T) {
+	id := models.NewID()
+	for _, name := range []string{id, strings.ToUpper(id)} {
+		v := New()
+		v.Name("name", name)
+		checkViolations(t, v, "name:not_id")
+
+		name := name
+		v = New()
+		v.Update("update_mask", []string{PathName}, UpdateFields{Name: &name})
+		checkViolations(t, v, "name:not_id")
+	}
+
+	for _, name := range []string{strings.ReplaceAll(id, "-", ""), id[:35], id + "0", "alice-" + id[:8]} {
+		v := New()
+		v.Name("name", name)
+		checkViolations(t, v)
+	}
+}
+
+func TestStringRules(t *testing.T) {
+	digit := func(r rune) bool { return r >= '0' && r <= '9' }
+	tests := []struct {
+		name  string
+		rule  Rule[string]
+		value string
+		want  bool
+	}{
+		{name: "required", rule: Required(), value: "a", want: true},
+		{name: "required empty", rule: Required(), value: "", want: false},
+		{name: "max length", rule: MaxLength(3), value: "abc", want: true},
+		{name: "max length exceeded", rule: MaxLength(3), value: "abcd", want: false},
+		{name: "max length counts runes", rule: MaxLength(3), value: "жук", want: true},
+		{name: "starts with", rule: StartsWith("a digit", digit), value: "1a", want: true},
+		{name: "starts with other", rule: StartsWith("a digit", digit), value: "a1", want: false},
+		{name: "starts with leaves empty to required", rule: StartsWith("a digit", digit), value: "", want: true},
+		{name: "charset", rule: Charset("digits", digit), value: "123", want: true},
+		{name: "charset other", rule: Charset("digits", digit), value: "12a", want: false},
+		{name: "not reserved", rule: NotReserved("admin"), value: "administrator", want: true},
+		{name: "reserved", rule: NotReserved("admin"), value: "admin", want: false},
+		{name: "reserved ignores case", rule: NotReserved("admin"), value: "AdMiN", want: false},
+		{name: "one of", rule: OneOf("a", "b"), value: "b", want: true},
+		{name: "one of other", rule: OneOf("a", "b"), value: "B", want: false},
+		{name: "not id", rule: NotID(), value: "alice", want: true},
+		{name: "id", rule: NotID(), value: models.NewID(), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Valid(tt.value); got != tt.want {
+				t.Fatalf("%s(%q) = %t, want %t", tt.rule.Name, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntRules(t *testing.T) {
+	tests := []struct {
+		rule  Rule[int]
+		value int
+		want  bool
+	}{
+		{rule: Min(0), value: 0, want: true},
+		{rule: Min(0), value: -1, want: false},
+		{rule: Max(10), value: 10, want: true},
+		{rule: Max(10), value: 11, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Valid(tt.value); got != tt.want {
+			t.Errorf("%s(%d) = %t, want %t", tt.rule.Name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		want       string
+		violations []string
+	}{
+		{name: "plain", value: "alice_1.b-c", want: "alice_1.b-c"},
+		{name: "unicode letters and marks", value: "жёлудь", want: "жёлудь"},
+		{name: "decomposed is normalized to nfc", value: "cafe\u0301", want: "caf\u00e9"},
+		{name: "starts with a digit", value: "1alice", want: "1alice"},
+		{name: "empty", value: "", violations: []string{"name:required"}},
+		{name: "starts with a dot", value: ".alice", violations: []string{"name:starts_with"}},
+		{name: "space", value: "alice smith", violations: []string{"name:charset"}},
+		{name: "reserved in another case", value: "Root", violations: []string{"name:reserved"}},
+		{name: "too long", value: strings.Repeat("a", MaxNameLength+1), violations: []string{"name:max_length"}},
+		// 64 буквы é в разложенной форме — 128 рун, но после NFC их 64.
+		{name: "length after nfc", value: strings.Repeat("e\u0301", MaxNameLength), want: strings.Repeat("\u00e9", MaxNameLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			got := v.Name("name", tt.value)
+			checkViolations(t, v, tt.violations...)
+			if tt.violations == nil && got != tt.want {
+				t.Fatalf("Name() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Поиск нормализует так же, поэтому разложенная запись находит аккаунт, созданный в NFC.
+	if v := New(); v.Lookup("name", "cafe\u0301") != "caf\u00e9" {
+		t.Fatal("Lookup does not normalize to NFC")
+	}
+}
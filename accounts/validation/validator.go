@@ -0,0 +1,228 @@
+package validation
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Validator копит нарушения по всем полям, чтобы вернуть их одной ошибкой.
+type Validator struct {
+	violations []errs.FieldViolation
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Check проверяет значение всеми правилами и запоминает каждое нарушение.
+func Check[T any](v *Validator, field string, value T, rules ...Rule[T]) {
+	for _, rule := range rules {
+		if !rule.Valid(value) {
+			v.violations = append(v.violations, errs.FieldViolation{
+				Field:       field,
+				Rule:        rule.Name,
+				Description: rule.Message,
+			})
+		}
+	}
+}
+
+// Name нормализует имя аккаунта и проверяет его правилами AccountName.
+func (v *Validator) Name(field, value string) string {
+	value = NormalizeName(value)
+	Check(v, field, value, AccountName...)
+
+	return value
+}
+
+func (v *Validator) Amount(field string, value int) {
+	Check(v, field, value, Amount...)
+}
+
+// Reason проверяет причину смены статуса без пробелов по краям.
+func (v *Validator) Reason(field, value string) string {
+	value = strings.TrimSpace(value)
+	Check(v, field, value, Reason...)
+
+	return value
+}
+
+// InitialStatus проверяет статус нового аккаунта; пустой статус означает active.
+func (v *Validator) InitialStatus(field, value string) models.Status {
+	if value == "" {
+		return models.StatusActive
+	}
+	Check(v, field, value, OneOf(InitialStatuses...))
+
+	return models.Status(value)
+}
+
+// Err возвращает errs.Invalid со всеми нарушениями или nil.
+func (v *Validator) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	return errs.Invalid(v.violations)
+}
+
+// NormalizeName приводит имя к NFC, чтобы визуально одинаковые имена совпадали.
+// Используется и для поиска, поэтому аккаунты со старыми именами остаются доступны.
+func NormalizeName(name string) string {
+	return norm.NFC.String(name)
+}
+
+// Lookup нормализует ссылку на существующий аккаунт — ID или имя — и проверяет только, что она не пустая:
+// аккаунты, созданные до появления правил, должны оставаться доступны. ID нормализация не меняет.
+func (v *Validator) Lookup(field, value string) string {
+	value = NormalizeName(value)
+	Check(v, field, value, Required())
+
+	return value
+}
+
+// HoldTTL проверяет срок холда в секундах; 0 означает DefaultHoldTTL.
+func (v *Validator) HoldTTL(field string, seconds int) time.Duration {
+	if seconds == 0 {
+		return DefaultHoldTTL
+	}
+	Check(v, field, seconds, Min(1), Max(int(MaxHoldTTL/time.Second)))
+
+	return time.Duration(seconds) * time.Second
+}
+
+// Description убирает пробелы по краям описания и проверяет его правилами Description.
+func (v *Validator) Description(field, value string) string {
+	value = strings.TrimSpace(value)
+	Check(v, field, value, Description...)
+
+	return value
+}
+
+// Labels проверяет метки; нарушения относятся к полям field.<key>.
+func (v *Validator) Labels(field string, labels map[string]string) map[string]string {
+	if len(labels) > MaxLabels {
+		v.violations = append(v.violations, errs.FieldViolation{
+			Field:       field,
+			Rule:        "max_items",
+			Description: fmt.Sprintf("must have at most %d labels", MaxLabels),
+		})
+	}
+	for key, value := range labels {
+		v.LabelKey(field+"."+key, key)
+		Check(v, field+"."+key, value, LabelValue...)
+	}
+
+	return labels
+}
+
+func (v *Validator) LabelKey(field, key string) {
+	Check(v, field, key, LabelKey...)
+}
+
+// Selector разбирает селектор меток; пустая строка выбирает всё.
+func (v *Validator) Selector(field, value string) models.Selector {
+	selector, err := models.ParseSelector(value)
+	if err != nil {
+		v.violations = append(v.violations, errs.FieldViolation{Field: field, Rule: "selector", Description: err.Error()})
+		return nil
+	}
+
+	return selector
+}
+
+// Cron проверяет выражение расписания; пустое выражение означает разовый перевод.
+func (v *Validator) Cron(field, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return value
+	}
+	if _, err := models.ParseCron(value); err != nil {
+		v.violations = append(v.violations, errs.FieldViolation{Field: field, Rule: "cron", Description: err.Error()})
+	}
+
+	return value
+}
+
+// RunAt проверяет срок разового перевода: без cron он обязателен. Нулевое время — срок не задан.
+func (v *Validator) RunAt(field string, runAt *time.Time, recurring bool) time.Time {
+	if runAt == nil || runAt.IsZero() {
+		if !recurring {
+			v.violations = append(v.violations, errs.FieldViolation{Field: field, Rule: "required", Description: "is required for a one-off transfer without cron"})
+		}
+		return time.Time{}
+	}
+
+	return *runAt
+}
+
+// Period разбирает период начисления процентов вида daily-2026-10-18 или monthly-2026-10.
+func (v *Validator) Period(field, value string) models.Period {
+	period, err := models.ParsePeriod(strings.TrimSpace(value))
+	if err != nil {
+		v.violations = append(v.violations, errs.FieldViolation{Field: field, Rule: "period", Description: err.Error()})
+	}
+
+	return period
+}
+
+// WebhookURL проверяет адрес вебхука: абсолютный http или https URL.
+func (v *Validator) WebhookURL(field, value string) string {
+	value = strings.TrimSpace(value)
+	Check(v, field, value, Required(), MaxLength(MaxURLLength))
+	if value == "" {
+		return value
+	}
+	if parsed, err := url.Parse(value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		v.violations = append(v.violations, errs.FieldViolation{Field: field, Rule: "url", Description: "must be an absolute http or https URL"})
+	}
+
+	return value
+}
+
+// Secret проверяет секрет подписи; пустой секрет сгенерирует сервер.
+func (v *Validator) Secret(field, value string) string {
+	if value != "" && (len(value) < MinSecretLength || len(value) > MaxSecretLength) {
+		v.violations = append(v.violations, errs.FieldViolation{
+			Field:       field,
+			Rule:        "length",
+			Description: fmt.Sprintf("must be %d to %d bytes long", MinSecretLength, MaxSecretLength),
+		})
+	}
+
+	return value
+}
+
+// EventTypes проверяет виды событий подписки и убирает повторы; пустой список означает все события.
+func (v *Validator) EventTypes(field string, values []string) []models.EventType {
+	known := make([]string, 0, len(models.EventTypes))
+	for _, eventType := range models.EventTypes {
+		known = append(known, string(eventType))
+	}
+
+	var eventTypes []models.EventType
+	for i, value := range values {
+		Check(v, fmt.Sprintf("%s.%d", field, i), value, OneOf(known...))
+		if !slices.Contains(eventTypes, models.EventType(value)) {
+			eventTypes = append(eventTypes, models.EventType(value))
+		}
+	}
+
+	return eventTypes
+}
+
+// DeliveryStatus проверяет фильтр доставок по состоянию; пустая строка — все доставки.
+func (v *Validator) DeliveryStatus(field, value string) models.DeliveryStatus {
+	if value != "" {
+		Check(v, field, value, OneOf(string(models.DeliveryPending), string(models.DeliveryDelivered), string(models.DeliveryDead)))
+	}
+
+	return models.DeliveryStatus(value)
+}
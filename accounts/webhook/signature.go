@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки запроса вебхука.
+const (
+	// SignatureHeader — подпись тела: «t=<unix-время>,v1=<hex HMAC-SHA256>».
+	SignatureHeader = "X-Accounts-Signature"
+	EventHeader     = "X-Accounts-Event"
+	DeliveryHeader  = "X-Accounts-Delivery"
+)
+
+// DefaultTolerance — насколько подпись может быть старше момента проверки.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrMalformedSignature = errors.New("malformed signature header")
+	ErrInvalidSignature   = errors.New("signature does not match")
+	ErrExpiredSignature   = errors.New("signature timestamp is outside the tolerance")
+)
+
+// Sign подписывает тело секретом подписки: HMAC-SHA256 от «<unix-время>.<тело>».
+// Время входит в подпись, чтобы перехваченный запрос нельзя было повторить позже.
+func Sign(secret string, body []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	return "t=" + timestamp + ",v1=" + digest(secret, timestamp, body)
+}
+
+// Verify проверяет заголовок SignatureHeader получателем вебхука; подпись старше tolerance отклоняется.
+func Verify(secret string, body []byte, header string, now time.Time, tolerance time.Duration) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	at, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return ErrMalformedSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(digest(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(at, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredSignature
+	}
+
+	return nil
+}
+
+func digest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,215 @@
+// Package webhook доставляет события аккаунтов подписчикам по HTTP.
+//
+// Dispatcher — events.Publisher: событие превращается в доставки каждой подписке, которая его ждёт,
+// и сохраняется в хранилище, а Run отправляет их POST запросами с подписью (см. Sign).
+// Ответ 2xx завершает доставку; иначе попытка повторяется с экспоненциальной задержкой.
+// После MaxAttempts неудач доставка становится dead — лежит в очереди недоставленных,
+// пока её не повторят через storage.Storage.ReplayDelivery.
+//
+// Доставка гарантируется хотя бы один раз: получатель отличает повторы по заголовку DeliveryHeader.
+package webhook
+
+import (
+	"awesomeProject/accounts"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultInterval    = 5 * time.Second
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 8
+	DefaultBaseBackoff = 10 * time.Second
+	DefaultMaxBackoff  = time.Hour
+	// DefaultTimeout ограничивает одну попытку доставки.
+	DefaultTimeout = 10 * time.Second
+)
+
+// maxErrorBody — сколько байт ответа с ошибкой сохраняется в LastError.
+const maxErrorBody = 256
+
+type Dispatcher struct {
+	store       storage.Storage
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+type Option func(d *Dispatcher)
+
+// WithInterval задаёт период проходов Run.
+func WithInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.interval = interval
+	}
+}
+
+// WithMaxAttempts задаёт число попыток, после которого доставка уходит в очередь недоставленных.
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithBackoff задаёт задержку перед второй попыткой; каждая следующая вдвое дольше, но не больше max.
+func WithBackoff(base, max time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.baseBackoff = base
+		d.maxBackoff = max
+	}
+}
+
+// WithHTTPClient заменяет клиент, которым отправляются запросы.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+func New(store storage.Storage, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:       store,
+		client:      &http.Client{Timeout: DefaultTimeout},
+		interval:    DefaultInterval,
+		batchSize:   DefaultBatchSize,
+		maxAttempts: DefaultMaxAttempts,
+		baseBackoff: DefaultBaseBackoff,
+		maxBackoff:  DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Publish заводит доставки события подпискам, которые его ждут.
+func (d *Dispatcher) Publish(ctx context.Context, event models.Event) error {
+	payload, err := accounts.EventPayload(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	_, err = d.store.EnqueueEvent(ctx, event, payload)
+
+	return err
+}
+
+// Run отправляет наступившие доставки раз в интервал, пока не отменён ctx.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("deliver webhooks failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue делает по попытке каждой доставки, срок которой наступил, и возвращает их итоги.
+func (d *Dispatcher) DeliverDue(ctx context.Context) ([]models.Delivery, error) {
+	due, err := d.store.DueDeliveries(ctx, time.Now(), d.batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Подписки читаются один раз на проход; доставки удалённой подписки удалены вместе с ней.
+	subscriptions := make(map[string]models.Subscription)
+	attempted := make([]models.Delivery, 0, len(due))
+	for _, delivery := range due {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			if subscription, err = d.store.Subscription(ctx, delivery.SubscriptionID); err != nil {
+				log.Printf("deliver %s skipped: %v", delivery.ID, err)
+				continue
+			}
+			subscriptions[subscription.ID] = subscription
+		}
+
+		delivery = d.attempt(ctx, subscription, delivery)
+		if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+			return attempted, err
+		}
+		attempted = append(attempted, delivery)
+	}
+
+	return attempted, nil
+}
+
+// attempt отправляет доставку и записывает в неё итог попытки.
+func (d *Dispatcher) attempt(ctx context.Context, subscription models.Subscription, delivery models.Delivery) models.Delivery {
+	delivery.Attempts++
+	status, err := d.send(ctx, subscription, delivery)
+	now := time.Now().UTC()
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = now
+		delivery.LastError, delivery.LastStatusCode = "", status
+		return delivery
+	}
+
+	delivery.LastError, delivery.LastStatusCode = err.Error(), status
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = models.DeliveryDead
+		log.Printf("delivery %s to %s is dead after %d attempts: %v", delivery.ID, subscription.URL, delivery.Attempts, err)
+		return delivery
+	}
+	delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+
+	return delivery
+}
+
+// backoff — задержка после attempts неудачных попыток.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.baseBackoff
+	for i := 1; i < attempts && backoff < d.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, d.maxBackoff)
+}
+
+// send возвращает код ответа (0 — ответа не было) и ошибку, если доставка не удалась.
+func (d *Dispatcher) send(ctx context.Context, subscription models.Subscription, delivery models.Delivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(subscription.Secret, delivery.Payload, time.Now()))
+	request.Header.Set(EventHeader, string(delivery.EventType))
+	request.Header.Set(DeliveryHeader, delivery.ID)
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+		if body = bytes.TrimSpace(body); len(body) == 0 {
+			return response.StatusCode, fmt.Errorf("unexpected response %s", response.Status)
+		}
+		return response.StatusCode, fmt.Errorf("unexpected response %s: %s", response.Status, body)
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return response.StatusCode, nil
+}
@@ -0,0 +1,269 @@
+package webhook
+
+import (
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/storage"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test_secret_value"
+
+// received — запрос, который принял тестовый получатель.
+type received struct {
+	delivery string
+	event    string
+	body     []byte
+	verified error
+}
+
+// receiver — локальный получатель вебхуков; fail решает по номеру запроса (с 1), отвечать ли 500.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []received
+	fail     func(n int) bool
+}
+
+func newReceiver(t *testing.T, fail func(n int) bool) *receiver {
+	t.Helper()
+	r := &receiver{fail: fail}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		r.requests = append(r.requests, received{
+			delivery: req.Header.Get(DeliveryHeader),
+			event:    req.Header.Get(EventHeader),
+			body:     body,
+			verified: Verify(testSecret, body, req.Header.Get(SignatureHeader), time.Now(), DefaultTolerance),
+		})
+		failed := r.fail != nil && r.fail(len(r.requests))
+		r.mu.Unlock()
+
+		if failed {
+			http.Error(w, "try later", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *receiver) received() []received {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]received(nil), r.requests...)
+}
+
+// setup заводит хранилище с подпиской на получателя и публикует одно событие.
+func setup(t *testing.T, r *receiver, opts ...Option) (*storage.Memory, *Dispatcher, models.Subscription) {
+	t.Helper()
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	subscription, err := store.CreateSubscription(ctx, models.Subscription{URL: r.URL, Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := store.Create(ctx, models.Account{Name: "alice", Amount: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := New(store, opts...)
+	if err := d.Publish(ctx, models.NewEvent(models.EventAccountCreated, account)); err != nil {
+		t.Fatal(err)
+	}
+
+	return store, d, subscription
+}
+
+// deliverOnce делает один проход и возвращает единственную попытку.
+func deliverOnce(t *testing.T, d *Dispatcher) models.Delivery {
+	t.Helper()
+	attempted, err := d.DeliverDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempted) != 1 {
+		t.Fatalf("attempted %d deliveries, want 1", len(attempted))
+	}
+
+	return attempted[0]
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	now := time.Now()
+	header := Sign(testSecret, body, now)
+
+	tests := []struct {
+		name   string
+		secret string
+		body   []byte
+		header string
+		now    time.Time
+		want   error
+	}{
+		{name: "valid", secret: testSecret, body: body, header: header, now: now},
+		{name: "tampered body", secret: testSecret, body: []byte(`{"id":"2"}`), header: header, now: now, want: ErrInvalidSignature},
+		{name: "wrong secret", secret: "another_secret_value", body: body, header: header, now: now, want: ErrInvalidSignature},
+		{name: "expired", secret: testSecret, body: body, header: header, now: now.Add(DefaultTolerance + time.Minute), want: ErrExpiredSignature},
+		{name: "from the future", secret: testSecret, body: body, header: header, now: now.Add(-DefaultTolerance - time.Minute), want: ErrExpiredSignature},
+		{name: "malformed", secret: testSecret, body: body, header: "v1=abc", now: now, want: ErrMalformedSignature},
+		{name: "empty", secret: testSecret, body: body, header: "", now: now, want: ErrMalformedSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.secret, tt.body, tt.header, tt.now, DefaultTolerance); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliverSigned(t *testing.T) {
+	r := newReceiver(t, nil)
+	store, d, subscription := setup(t, r)
+
+	delivery := deliverOnce(t, d)
+	if delivery.Status != models.DeliveryDelivered || delivery.Attempts != 1 || delivery.LastStatusCode != http.StatusNoContent {
+		t.Fatalf("delivery = %+v, want delivered on the first attempt", delivery)
+	}
+
+	requests := r.received()
+	if len(requests) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(requests))
+	}
+	if err := requests[0].verified; err != nil {
+		t.Fatalf("signature: %v", err)
+	}
+	if requests[0].delivery != delivery.ID || requests[0].event != string(models.EventAccountCreated) {
+		t.Fatalf("headers = %q, %q; want %q, %q", requests[0].delivery, requests[0].event, delivery.ID, models.EventAccountCreated)
+	}
+
+	stored, err := store.Deliveries(context.Background(), subscription.ID, models.DeliveryDelivered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("stored %d delivered deliveries, want 1", len(stored))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := New(storage.NewMemory(), WithBackoff(time.Second, 5*time.Second))
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := d.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	const backoff = 20 * time.Millisecond
+	r := newReceiver(t, func(n int) bool { return n <= 2 })
+	_, d, _ := setup(t, r, WithBackoff(backoff, time.Second), WithMaxAttempts(5))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		before := time.Now()
+		delivery := deliverOnce(t, d)
+		if delivery.Status != models.DeliveryPending || delivery.Attempts != attempt || delivery.LastStatusCode != http.StatusInternalServerError {
+			t.Fatalf("attempt %d: delivery = %+v, want pending after a 500", attempt, delivery)
+		}
+		wait := backoff << (attempt - 1)
+		if delivery.NextAttemptAt.Before(before.Add(wait)) {
+			t.Fatalf("attempt %d: next attempt at %s, want at least %s later", attempt, delivery.NextAttemptAt, wait)
+		}
+
+		// До срока повтора доставка не отправляется.
+		if attempted, err := d.DeliverDue(context.Background()); err != nil || len(attempted) != 0 {
+			t.Fatalf("attempt %d: DeliverDue before the backoff = %d, %v; want nothing", attempt, len(attempted), err)
+		}
+		time.Sleep(time.Until(delivery.NextAttemptAt))
+	}
+
+	delivery := deliverOnce(t, d)
+	if delivery.Status != models.DeliveryDelivered || delivery.Attempts != 3 || delivery.LastError != "" {
+		t.Fatalf("delivery = %+v, want delivered on the third attempt", delivery)
+	}
+	if n := len(r.received()); n != 3 {
+		t.Fatalf("receiver got %d requests, want 3", n)
+	}
+}
+
+func TestDeadAfterMaxAttempts(t *testing.T) {
+	const maxAttempts = 3
+	r := newReceiver(t, func(int) bool { return true })
+	store, d, subscription := setup(t, r, WithBackoff(time.Millisecond, time.Millisecond), WithMaxAttempts(maxAttempts))
+
+	var delivery models.Delivery
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		time.Sleep(2 * time.Millisecond)
+		delivery = deliverOnce(t, d)
+	}
+	if delivery.Status != models.DeliveryDead || delivery.Attempts != maxAttempts || delivery.LastError == "" {
+		t.Fatalf("delivery = %+v, want dead after %d attempts", delivery, maxAttempts)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if attempted, err := d.DeliverDue(context.Background()); err != nil || len(attempted) != 0 {
+		t.Fatalf("DeliverDue after dead = %d, %v; want nothing", len(attempted), err)
+	}
+	dead, err := store.Deliveries(context.Background(), subscription.ID, models.DeliveryDead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].ID != delivery.ID {
+		t.Fatalf("dead deliveries = %+v, want %s", dead, delivery.ID)
+	}
+}
+
+func TestReplayDelivery(t *testing.T) {
+	var mu sync.Mutex
+	down := true
+	r := newReceiver(t, func(int) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return down
+	})
+	store, d, subscription := setup(t, r, WithMaxAttempts(1))
+
+	dead := deliverOnce(t, d)
+	if dead.Status != models.DeliveryDead {
+		t.Fatalf("delivery = %+v, want dead", dead)
+	}
+
+	mu.Lock()
+	down = false
+	mu.Unlock()
+	replayed, err := store.ReplayDelivery(context.Background(), subscription.ID, dead.ID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Status != models.DeliveryPending || replayed.Attempts != 0 {
+		t.Fatalf("replayed = %+v, want pending with no attempts", replayed)
+	}
+
+	delivery := deliverOnce(t, d)
+	if delivery.Status != models.DeliveryDelivered || delivery.Attempts != 1 {
+		t.Fatalf("delivery = %+v, want delivered after replay", delivery)
+	}
+	requests := r.received()
+	if len(requests) != 2 {
+		t.Fatalf("receiver got %d requests, want 2", len(requests))
+	}
+	if requests[1].delivery != dead.ID || string(requests[1].body) != string(requests[0].body) || requests[1].verified != nil {
+		t.Fatalf("replay = %+v, want the same signed delivery as %+v", requests[1], requests[0])
+	}
+}
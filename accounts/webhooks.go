@@ -0,0 +1,192 @@
+package accounts
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/validation"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Подписывает URL на события аккаунтов; секрет подписи виден только в этом ответе
+func (h *Handler) CreateWebhook(c echo.Context) error {
+	var request dto.CreateWebhookRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Error(err)
+		return writeError(c, errs.New(errs.InvalidArgument, "invalid request", nil))
+	}
+
+	v := validation.New()
+	subscription := models.Subscription{
+		URL:        v.WebhookURL("url", request.URL),
+		EventTypes: v.EventTypes("event_types", request.EventTypes),
+		Secret:     v.Secret("secret", request.Secret),
+	}
+	for i, account := range request.Accounts {
+		subscription.Accounts = append(subscription.Accounts, v.Lookup(fmt.Sprintf("accounts.%d", i), account))
+	}
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+	if subscription.Secret == "" {
+		subscription.Secret = models.NewSecret()
+	}
+
+	subscription, err := h.storage.CreateSubscription(c.Request().Context(), subscription)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := webhookResponse(subscription)
+	response.Secret = subscription.Secret
+	c.Response().Header().Set(echo.HeaderLocation, "/v1/webhooks/"+url.PathEscape(subscription.ID))
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+func (h *Handler) ListWebhooks(c echo.Context) error {
+	subscriptions, err := h.storage.Subscriptions(c.Request().Context())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.ListWebhooksResponse{Webhooks: make([]dto.WebhookResponse, 0, len(subscriptions))}
+	for _, subscription := range subscriptions {
+		response.Webhooks = append(response.Webhooks, webhookResponse(subscription))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetWebhook(c echo.Context) error {
+	id, err := webhookParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	subscription, err := h.storage.Subscription(c.Request().Context(), id)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, webhookResponse(subscription))
+}
+
+// Удаляет подписку вместе с её доставками
+func (h *Handler) DeleteWebhook(c echo.Context) error {
+	id, err := webhookParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	if err := h.storage.DeleteSubscription(c.Request().Context(), id); err != nil {
+		return writeError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Доставки подписки от старых к новым; параметр status оставляет pending, delivered или dead
+func (h *Handler) ListDeliveries(c echo.Context) error {
+	id, err := webhookParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+	v := validation.New()
+	status := v.DeliveryStatus("status", c.QueryParam("status"))
+	if err := v.Err(); err != nil {
+		return writeError(c, err)
+	}
+
+	deliveries, err := h.storage.Deliveries(c.Request().Context(), id, status)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	response := dto.ListDeliveriesResponse{Deliveries: make([]dto.DeliveryResponse, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, deliveryResponse(delivery))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Ставит доставку заново в очередь, в том числе из очереди недоставленных; отправит её диспетчер
+func (h *Handler) ReplayDelivery(c echo.Context) error {
+	id, err := webhookParam(c)
+	if err != nil {
+		return writeError(c, err)
+	}
+	deliveryID, err := url.PathUnescape(c.Param("delivery_id"))
+	if err != nil || len(deliveryID) == 0 {
+		return writeError(c, errs.InvalidField("delivery_id", "invalid delivery ID in path"))
+	}
+
+	delivery, err := h.storage.ReplayDelivery(c.Request().Context(), id, deliveryID, time.Now())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, deliveryResponse(delivery))
+}
+
+func webhookParam(c echo.Context) (string, error) {
+	id, err := url.PathUnescape(c.Param("webhook_id"))
+	if err != nil || len(id) == 0 {
+		return "", errs.InvalidField("webhook_id", "invalid webhook ID in path")
+	}
+
+	return id, nil
+}
+
+func webhookResponse(subscription models.Subscription) dto.WebhookResponse {
+	response := dto.WebhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: make([]string, 0, len(subscription.EventTypes)),
+		Accounts:   append(make([]string, 0, len(subscription.Accounts)), subscription.Accounts...),
+		CreatedAt:  subscription.CreatedAt,
+	}
+	for _, eventType := range subscription.EventTypes {
+		response.EventTypes = append(response.EventTypes, string(eventType))
+	}
+
+	return response
+}
+
+func deliveryResponse(delivery models.Delivery) dto.DeliveryResponse {
+	response := dto.DeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		AccountID:      delivery.AccountID,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastError:      delivery.LastError,
+		LastStatusCode: delivery.LastStatusCode,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if !delivery.DeliveredAt.IsZero() {
+		deliveredAt := delivery.DeliveredAt
+		response.DeliveredAt = &deliveredAt
+	}
+
+	return response
+}
+
+// EventPayload кодирует событие телом запроса вебхука (dto.EventResponse).
+func EventPayload(event models.Event) ([]byte, error) {
+	return json.Marshal(dto.EventResponse{
+		ID:         event.ID,
+		Sequence:   event.Sequence,
+		Type:       string(event.Type),
+		OccurredAt: event.OccurredAt,
+		Account:    accountResponse(event.Account),
+	})
+}
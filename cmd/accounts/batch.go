@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	statusOK      = "ok"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+)
+
+// batchLine — одна команда скрипта.
+type batchLine struct {
+	number int
+	words  []string
+	cmd    *command
+}
+
+// lineResult — итог выполнения строки скрипта.
+type lineResult struct {
+	Line    int    `json:"line" yaml:"line"`
+	Command string `json:"command" yaml:"command"`
+	Status  string `json:"status" yaml:"status"`
+	Result  string `json:"result,omitempty" yaml:"result,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	err error
+}
+
+// batchError — часть строк скрипта завершилась ошибкой; код выхода берётся по первой из них.
+type batchError struct {
+	failed int
+	total  int
+	line   int
+	first  error
+}
+
+func (e *batchError) Error() string {
+	return fmt.Sprintf("%d of %d lines failed, first at line %d: %v", e.failed, e.total, e.line, e.first)
+}
+
+func (e *batchError) Unwrap() error {
+	// Ошибки аргументов внутри скрипта не должны выглядеть как неверный вызов самого batch.
+	var usage *usageError
+	if errors.As(e.first, &usage) {
+		return nil
+	}
+
+	return e.first
+}
+
+func setupBatch(fs *flag.FlagSet) runFunc {
+	file := fs.String("f", "-", "file with commands, one per line; - reads stdin")
+	continueOnError := fs.Bool("continue-on-error", false, "run the remaining lines after a failure instead of stopping")
+	parallel := fs.Int("parallel", 1, "number of lines executed concurrently; order between lines is then not guaranteed")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 0, "no arguments"); err != nil {
+			return nil, err
+		}
+		if *parallel < 1 {
+			return nil, usageErrorf("--parallel must be at least 1")
+		}
+		if *file == "-" && a.inShell {
+			return nil, usageErrorf("in shell, batch needs -f FILE")
+		}
+
+		lines, err := a.readBatch(*file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := a.conn(); err != nil {
+			return nil, err
+		}
+
+		results := a.runBatch(ctx, lines, *parallel, *continueOnError)
+		if err := a.output.print(a.out, results); err != nil {
+			return nil, err
+		}
+
+		return nil, summarize(a.errOut, results)
+	}
+}
+
+// readBatch читает и проверяет весь скрипт до выполнения, чтобы опечатка не оставила его применённым наполовину.
+func (a *app) readBatch(file string) ([]batchLine, error) {
+	in := a.in
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("open batch file failed: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		in = f
+	}
+
+	var lines []batchLine
+	scanner := bufio.NewScanner(in)
+	for number := 1; scanner.Scan(); number++ {
+		words, err := splitWords(scanner.Text())
+		if err != nil {
+			return nil, usageErrorf("line %d: %v", number, err)
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		cmd := findCommand(words[0])
+		if cmd == nil || !cmd.remote {
+			return nil, usageErrorf("line %d: unknown command %q, available in batch: %s", number, words[0], remoteCommandNames())
+		}
+		fs, _ := cmd.flagSet(a, &outputOptions{})
+		fs.SetOutput(io.Discard)
+		if _, err := parseInterspersed(fs, words[1:]); err != nil {
+			return nil, usageErrorf("line %d: %v", number, err)
+		}
+
+		lines = append(lines, batchLine{number: number, words: words, cmd: cmd})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read batch failed: %w", err)
+	}
+
+	return lines, nil
+}
+
+// runBatch выполняет строки через parallel воркеров. Без --continue-on-error после первой ошибки
+// новые строки не запускаются и помечаются как skipped; уже запущенные доводятся до конца.
+func (a *app) runBatch(ctx context.Context, lines []batchLine, parallel int, continueOnError bool) []lineResult {
+	results := make([]lineResult, len(lines))
+	jobs := make(chan int)
+	var stopped atomic.Bool
+
+	var wg sync.WaitGroup
+	for range min(parallel, max(len(lines), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				line := lines[i]
+				results[i] = lineResult{Line: line.number, Command: joinWords(line.words)}
+				if stopped.Load() {
+					results[i].Status = statusSkipped
+					continue
+				}
+
+				result, err := a.execLine(ctx, line)
+				if err != nil {
+					results[i].Status = statusFailed
+					results[i].Error = err.Error()
+					results[i].err = err
+					if !continueOnError {
+						stopped.Store(true)
+					}
+					continue
+				}
+				results[i].Status = statusOK
+				results[i].Result = describe(result)
+			}
+		}()
+	}
+
+	for i := range lines {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func (a *app) execLine(ctx context.Context, line batchLine) (any, error) {
+	fs, run := line.cmd.flagSet(a, &outputOptions{})
+	fs.SetOutput(io.Discard)
+	positional, err := parseInterspersed(fs, line.words[1:])
+	if err != nil {
+		return nil, &usageError{message: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	return run(ctx, a, positional)
+}
+
+// summarize печатает итог по статусам и возвращает batchError, если были ошибки.
+func summarize(w io.Writer, results []lineResult) error {
+	counts := map[string]int{}
+	var failed *lineResult
+	for i, result := range results {
+		counts[result.Status]++
+		if result.Status == statusFailed && failed == nil {
+			failed = &results[i]
+		}
+	}
+
+	fmt.Fprintf(w, "%d lines: %d ok, %d failed, %d skipped\n", len(results), counts[statusOK], counts[statusFailed], counts[statusSkipped])
+	if failed == nil {
+		return nil
+	}
+
+	return &batchError{failed: counts[statusFailed], total: len(results), line: failed.Line, first: failed.err}
+}
+
+// describe — краткое описание результата команды для одной ячейки отчёта.
+func describe(result any) string {
+	v := reflect.ValueOf(result)
+	switch v.Kind() {
+	case reflect.Slice:
+		return fmt.Sprintf("%d items", v.Len())
+	case reflect.Struct:
+		columns := columnsOf(v.Type())
+		fields := make([]string, 0, len(columns))
+		for _, c := range columns {
+			fields = append(fields, fmt.Sprintf("%s=%v", c.name, v.Field(c.index).Interface()))
+		}
+		return strings.Join(fields, " ")
+	default:
+		return fmt.Sprint(result)
+	}
+}
+
+func remoteCommandNames() string {
+	var names []string
+	for _, cmd := range commands {
+		if cmd.remote {
+			names = append(names, cmd.name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
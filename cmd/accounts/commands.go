@@ -0,0 +1,731 @@
+package main
+
+import (
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/transport"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runFunc выполняет команду и возвращает результат для вывода (nil — выводить нечего).
+type runFunc func(ctx context.Context, a *app, args []string) (any, error)
+
+// command — подкоманда CLI. setup объявляет флаги команды и возвращает функцию запуска.
+type command struct {
+	name    string
+	args    string
+	summary string
+	setup   func(fs *flag.FlagSet) runFunc
+
+	// mutating — команда меняет данные; в сессии shell такие команды ставятся в очередь.
+	mutating bool
+	// completesNames — первый аргумент команды — имя аккаунта, shell дополняет его.
+	completesNames bool
+	// remote — команда работает с сервером; только такие команды допустимы в batch.
+	remote bool
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{name: "get", args: "NAME [--deleted] [--selector S]", summary: "show an account", setup: setupGet, remote: true, completesNames: true},
+		{name: "list", args: "[--deleted] [--selector S]", summary: "list all accounts or those whose labels match --selector", setup: setupList, remote: true},
+		{name: "create", args: "NAME [AMOUNT] [--amount N]", summary: "create an account", setup: setupCreate, remote: true, mutating: true},
+		{name: "delete", args: "NAME", summary: "delete an account; it can be restored until purged; capture or release its holds first", setup: setupDelete, remote: true, mutating: true, completesNames: true},
+		{name: "restore", args: "NAME", summary: "restore a deleted account", setup: setupRestore, remote: true, mutating: true},
+		{name: "activate", args: "NAME --reason R", summary: "activate a pending account", setup: setupChangeStatus(models.Activate), remote: true, mutating: true, completesNames: true},
+		{name: "freeze", args: "NAME --reason R", summary: "freeze an account: only reads and credits are allowed", setup: setupChangeStatus(models.Freeze), remote: true, mutating: true, completesNames: true},
+		{name: "unfreeze", args: "NAME --reason R", summary: "unfreeze a frozen account", setup: setupChangeStatus(models.Unfreeze), remote: true, mutating: true, completesNames: true},
+		{name: "close", args: "NAME --reason R", summary: "close an account for good: only reads are allowed; capture or release its holds first", setup: setupChangeStatus(models.Close), remote: true, mutating: true, completesNames: true},
+		{name: "history", args: "NAME", summary: "show status history of an account", setup: setupHistory, remote: true, completesNames: true},
+		{name: "renames", args: "NAME", summary: "show rename history of an account", setup: setupRenames, remote: true, completesNames: true},
+		{name: "set-amount", args: "NAME AMOUNT", summary: "set the balance of an account", setup: setupSetAmount, remote: true, mutating: true, completesNames: true},
+		{name: "rename", args: "NAME NEW_NAME", summary: "rename an account", setup: setupRename, remote: true, mutating: true, completesNames: true},
+		{name: "edit", args: "NAME [--description D] [--replace-labels] [KEY=VALUE | KEY-]...", summary: "change the description and labels of an account", setup: setupEdit, remote: true, mutating: true, completesNames: true},
+		{name: "hold", args: "NAME AMOUNT [--ttl D]", summary: "reserve part of the available balance", setup: setupHold, remote: true, mutating: true, completesNames: true},
+		{name: "holds", args: "NAME", summary: "list holds of an account, including closed ones", setup: setupHolds, remote: true, completesNames: true},
+		{name: "capture", args: "HOLD_ID [AMOUNT]", summary: "debit a hold, in full or in part; the rest becomes available", setup: setupCapture, remote: true, mutating: true},
+		{name: "release", args: "HOLD_ID", summary: "release a hold without changing the balance", setup: setupRelease, remote: true, mutating: true},
+		{name: "schedule", args: "FROM TO AMOUNT [--at TIME] [--cron EXPR] [--description D]", summary: "schedule a one-off transfer at --at or a recurring one by --cron", setup: setupSchedule, remote: true, mutating: true, completesNames: true},
+		{name: "schedules", args: "[NAME]", summary: "list transfer schedules, optionally only those of an account", setup: setupSchedules, remote: true, completesNames: true},
+		{name: "pause-schedule", args: "SCHEDULE_ID", summary: "pause a transfer schedule", setup: setupChangeSchedule(models.SchedulePause), remote: true, mutating: true},
+		{name: "resume-schedule", args: "SCHEDULE_ID", summary: "resume a paused schedule; runs missed while paused are skipped", setup: setupChangeSchedule(models.ScheduleResume), remote: true, mutating: true},
+		{name: "cancel-schedule", args: "SCHEDULE_ID", summary: "cancel a transfer schedule for good", setup: setupChangeSchedule(models.ScheduleCancel), remote: true, mutating: true},
+		{name: "schedule-runs", args: "SCHEDULE_ID", summary: "show runs of a transfer schedule", setup: setupScheduleRuns, remote: true},
+		{name: "accrue", args: "PERIOD [--dry-run]", summary: "accrue interest for an ended period, e.g. daily-2026-10-18 or monthly-2026-10", setup: setupAccrue, remote: true, mutating: true},
+		{name: "accruals", summary: "list interest accrual batches", setup: setupAccruals, remote: true},
+		{name: "ledger", args: "NAME", summary: "show ledger entries of an account", setup: setupLedger, remote: true, completesNames: true},
+		{name: "webhook-create", args: "URL [--event TYPE]... [--account NAME]... [--secret S]", summary: "subscribe a URL to account events; the signing secret is shown only now", setup: setupWebhookCreate, remote: true, mutating: true},
+		{name: "webhooks", summary: "list webhook subscriptions", setup: setupWebhooks, remote: true},
+		{name: "webhook-delete", args: "WEBHOOK_ID", summary: "delete a webhook subscription with its deliveries", setup: setupWebhookDelete, remote: true, mutating: true},
+		{name: "deliveries", args: "WEBHOOK_ID [--status S]", summary: "list deliveries of a webhook; --status dead shows the dead-letter queue", setup: setupDeliveries, remote: true},
+		{name: "replay", args: "WEBHOOK_ID DELIVERY_ID", summary: "queue a webhook delivery again with a full set of attempts", setup: setupReplay, remote: true, mutating: true},
+		{name: "webhook-listen", args: "[--addr ADDR] [--secret S] [--fail-status CODE] [--count N]", summary: "receive webhooks locally, verify their signatures and print the events", setup: setupWebhookListen},
+		{name: "plan", args: "-f FILE [--prune]", summary: "show the changes needed to match a desired-state file", setup: setupPlan},
+		{name: "apply", args: "-f FILE [--prune]", summary: "change the server to match a desired-state file", setup: setupApply},
+		{name: "batch", args: "[-f FILE] [--continue-on-error] [--parallel N]", summary: "run commands from a file or stdin, one per line, over one connection", setup: setupBatch},
+		{name: "shell", summary: "interactive shell with history, completion and confirmed sessions", setup: setupShell},
+		{name: "profile", args: "list | show [NAME] | use NAME | set NAME [--transport T] [--address A] | delete NAME", summary: "manage connection profiles", setup: setupProfile},
+		{name: "help", args: "[COMMAND]", summary: "show help for a command", setup: setupHelp},
+	}
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+func (cmd *command) flagSet(a *app, output *outputOptions) (*flag.FlagSet, runFunc) {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	run := cmd.setup(fs)
+	output.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(a.errOut, "usage: accounts %s %s\n\n%s\n", cmd.name, cmd.args, cmd.summary)
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(a.errOut, "\nflags:\n")
+			fs.PrintDefaults()
+			fmt.Fprintf(a.errOut, "\n%s\n", argumentsNote)
+		}
+	}
+
+	return fs, run
+}
+
+// runCommand разбирает аргументы подкоманды и выполняет её с таймаутом из --timeout.
+func (a *app) runCommand(ctx context.Context, args []string) error {
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		return usageErrorf("unknown command %q, available: %s", args[0], commandNames())
+	}
+
+	output := &outputOptions{}
+	a.output = output
+
+	fs, run := cmd.flagSet(a, output)
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{message: err.Error()}
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	// batch и shell выполняют много команд и ставят таймаут на каждую сами.
+	if cmd.remote {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
+	result, err := run(ctx, a, positional)
+	if err != nil {
+		var usage *usageError
+		if errors.As(err, &usage) && output.format != outputJSON {
+			fs.Usage()
+		}
+		return err
+	}
+
+	return output.print(a.out, result)
+}
+
+func expectArgs(args []string, n int, names string) error {
+	if len(args) != n {
+		return usageErrorf("expected %s, got %d arguments", names, len(args))
+	}
+
+	return nil
+}
+
+func parseAmount(value string) (int, error) {
+	amount, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, usageErrorf("invalid amount %q", value)
+	}
+
+	return amount, nil
+}
+
+// readOptions — флаги --deleted и --selector команд чтения.
+func readOptions(fs *flag.FlagSet) func() []transport.ReadOption {
+	deleted := fs.Bool("deleted", false, "include deleted accounts that are not purged yet")
+	selector := fs.String("selector", "", "only accounts whose labels match, e.g. tier=gold,region!=us,!legacy")
+
+	return func() []transport.ReadOption {
+		var opts []transport.ReadOption
+		if *deleted {
+			opts = append(opts, transport.IncludeDeleted())
+		}
+		if *selector != "" {
+			opts = append(opts, transport.LabelSelector(*selector))
+		}
+		return opts
+	}
+}
+
+func setupGet(fs *flag.FlagSet) runFunc {
+	opts := readOptions(fs)
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := conn.Get(ctx, args[0], opts()...)
+		if err != nil {
+			return nil, err
+		}
+		view := viewOf(account.Account)
+		view.RedirectedFrom = account.RedirectedFrom
+
+		return view, nil
+	}
+}
+
+func setupList(fs *flag.FlagSet) runFunc {
+	opts := readOptions(fs)
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 0, "no arguments"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		accounts, err := conn.List(ctx, opts()...)
+		if err != nil {
+			return nil, err
+		}
+
+		return viewsOf(accounts), nil
+	}
+}
+
+func setupCreate(fs *flag.FlagSet) runFunc {
+	amount := fs.Int("amount", 0, "initial balance")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, usageErrorf("expected NAME [AMOUNT], got %d arguments", len(args))
+		}
+		initial := *amount
+		if len(args) == 2 {
+			var err error
+			if initial, err = parseAmount(args[1]); err != nil {
+				return nil, err
+			}
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := conn.Create(ctx, args[0], initial)
+		if err != nil {
+			return nil, err
+		}
+
+		return viewOf(account), nil
+	}
+}
+
+func setupDelete(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := conn.Delete(ctx, args[0]); err != nil {
+			return nil, err
+		}
+
+		return deletedView{Name: args[0], Deleted: true}, nil
+	}
+}
+
+func setupRestore(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := conn.Restore(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return viewOf(account), nil
+	}
+}
+
+func setupChangeStatus(change models.Change) func(fs *flag.FlagSet) runFunc {
+	return func(fs *flag.FlagSet) runFunc {
+		reason := fs.String("reason", "", "why the status changes; stored in the status history")
+
+		return func(ctx context.Context, a *app, args []string) (any, error) {
+			if err := expectArgs(args, 1, "NAME"); err != nil {
+				return nil, err
+			}
+			if *reason == "" {
+				return nil, usageErrorf("--reason is required")
+			}
+			conn, err := a.conn()
+			if err != nil {
+				return nil, err
+			}
+
+			account, err := conn.ChangeStatus(ctx, args[0], change, *reason)
+			if err != nil {
+				return nil, err
+			}
+
+			return viewOf(account), nil
+		}
+	}
+}
+
+func setupHistory(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		history, err := conn.StatusHistory(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return transitionViewsOf(history), nil
+	}
+}
+
+func setupRenames(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		history, err := conn.RenameHistory(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return renameViewsOf(history), nil
+	}
+}
+
+func setupSetAmount(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 2, "NAME AMOUNT"); err != nil {
+			return nil, err
+		}
+		amount, err := parseAmount(args[1])
+		if err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := conn.SetAmount(ctx, args[0], amount)
+		if err != nil {
+			return nil, err
+		}
+
+		return viewOf(account), nil
+	}
+}
+
+func setupRename(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 2, "NAME NEW_NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := conn.Rename(ctx, args[0], args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return viewOf(account), nil
+	}
+}
+
+// setupEdit: KEY=VALUE ставит метку, KEY- удаляет её; --replace-labels заменяет все метки на перечисленные.
+func setupEdit(fs *flag.FlagSet) runFunc {
+	var description *string
+	fs.Func("description", "new description; an empty value clears it", func(value string) error {
+		description = &value
+		return nil
+	})
+	replace := fs.Bool("replace-labels", false, "replace all labels with the given KEY=VALUE pairs")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if len(args) == 0 {
+			return nil, usageErrorf("expected NAME [KEY=VALUE | KEY-]..., got no arguments")
+		}
+		change := models.MetadataChange{Description: description, ReplaceLabels: *replace}
+		for _, arg := range args[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			switch {
+			case ok:
+				if change.SetLabels == nil {
+					change.SetLabels = make(map[string]string)
+				}
+				change.SetLabels[key] = value
+			case strings.HasSuffix(arg, "-") && !*replace:
+				change.RemoveLabels = append(change.RemoveLabels, strings.TrimSuffix(arg, "-"))
+			default:
+				return nil, usageErrorf("invalid label change %q, expected KEY=VALUE or KEY-", arg)
+			}
+		}
+		if change.Empty() {
+			return nil, usageErrorf("nothing to change: pass --description, --replace-labels or label changes")
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := conn.Edit(ctx, args[0], change)
+		if err != nil {
+			return nil, err
+		}
+
+		return viewOf(account), nil
+	}
+}
+
+func setupHold(fs *flag.FlagSet) runFunc {
+	ttl := fs.Duration("ttl", 0, "how long the hold lives unless captured or released; 0 uses the server default")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 2, "NAME AMOUNT"); err != nil {
+			return nil, err
+		}
+		amount, err := parseAmount(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if *ttl < 0 || *ttl%time.Second != 0 {
+			return nil, usageErrorf("invalid --ttl %s, expected whole seconds", *ttl)
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		hold, err := conn.Authorize(ctx, args[0], amount, *ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		return holdViewOf(hold), nil
+	}
+}
+
+func setupHolds(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		holds, err := conn.Holds(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return holdViewsOf(holds), nil
+	}
+}
+
+// setupCapture: без AMOUNT холд списывается целиком.
+func setupCapture(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, usageErrorf("expected HOLD_ID [AMOUNT], got %d arguments", len(args))
+		}
+		var amount int
+		if len(args) == 2 {
+			var err error
+			if amount, err = parseAmount(args[1]); err != nil {
+				return nil, err
+			}
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		hold, account, err := conn.Capture(ctx, args[0], amount)
+		if err != nil {
+			return nil, err
+		}
+
+		return captureView{Hold: hold.ID, Captured: hold.Captured, Account: account.Name, Amount: account.Amount, Held: account.Held}, nil
+	}
+}
+
+func setupRelease(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "HOLD_ID"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		hold, err := conn.Release(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return holdViewOf(hold), nil
+	}
+}
+
+// setupSchedule: с --cron перевод повторяется, а --at задаёт начало расписания; без --cron --at обязателен.
+func setupSchedule(fs *flag.FlagSet) runFunc {
+	var at time.Time
+	fs.Func("at", "when a one-off transfer runs or a recurring one starts, RFC 3339", func(value string) error {
+		var err error
+		at, err = time.Parse(time.RFC3339, value)
+		return err
+	})
+	cron := fs.String("cron", "", `repeat by a crontab expression in UTC, e.g. "0 9 1 * *" or @monthly`)
+	description := fs.String("description", "", "what the transfer is for")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 3, "FROM TO AMOUNT"); err != nil {
+			return nil, err
+		}
+		amount, err := parseAmount(args[2])
+		if err != nil {
+			return nil, err
+		}
+		if *cron == "" && at.IsZero() {
+			return nil, usageErrorf("pass --at for a one-off transfer or --cron for a recurring one")
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		schedule, err := conn.CreateSchedule(ctx, models.Schedule{
+			From:        args[0],
+			To:          args[1],
+			Amount:      amount,
+			Cron:        *cron,
+			Description: *description,
+			NextRunAt:   at,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return scheduleViewOf(schedule), nil
+	}
+}
+
+func setupSchedules(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if len(args) > 1 {
+			return nil, usageErrorf("expected [NAME], got %d arguments", len(args))
+		}
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		schedules, err := conn.Schedules(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return scheduleViewsOf(schedules), nil
+	}
+}
+
+func setupChangeSchedule(action models.ScheduleAction) func(fs *flag.FlagSet) runFunc {
+	return func(_ *flag.FlagSet) runFunc {
+		return func(ctx context.Context, a *app, args []string) (any, error) {
+			if err := expectArgs(args, 1, "SCHEDULE_ID"); err != nil {
+				return nil, err
+			}
+			conn, err := a.conn()
+			if err != nil {
+				return nil, err
+			}
+
+			schedule, err := conn.ChangeSchedule(ctx, args[0], action)
+			if err != nil {
+				return nil, err
+			}
+
+			return scheduleViewOf(schedule), nil
+		}
+	}
+}
+
+func setupScheduleRuns(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "SCHEDULE_ID"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		runs, err := conn.ScheduleRuns(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return runViewsOf(runs), nil
+	}
+}
+
+// setupAccrue с --dry-run выводит расчётные проводки вместо итогов пакета.
+func setupAccrue(fs *flag.FlagSet) runFunc {
+	dryRun := fs.Bool("dry-run", false, "only show the projected postings, accrue nothing")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "PERIOD"); err != nil {
+			return nil, err
+		}
+		period, err := models.ParsePeriod(args[0])
+		if err != nil {
+			return nil, usageErrorf("%v", err)
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		accrual, postings, err := conn.AccrueInterest(ctx, period, *dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if *dryRun {
+			return postingViewsOf(postings), nil
+		}
+
+		return accrualViewOf(accrual), nil
+	}
+}
+
+func setupAccruals(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 0, "no arguments"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		accruals, err := conn.Accruals(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		views := make([]accrualView, 0, len(accruals))
+		for _, accrual := range accruals {
+			views = append(views, accrualViewOf(accrual))
+		}
+
+		return views, nil
+	}
+}
+
+func setupLedger(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "NAME"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := conn.Ledger(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return ledgerViewsOf(entries), nil
+	}
+}
+
+func setupHelp(_ *flag.FlagSet) runFunc {
+	return func(_ context.Context, a *app, args []string) (any, error) {
+		if len(args) == 0 {
+			a.printUsage(flag.NewFlagSet("accounts", flag.ContinueOnError))
+			return nil, nil
+		}
+
+		cmd := findCommand(args[0])
+		if cmd == nil {
+			return nil, usageErrorf("unknown command %q, available: %s", args[0], commandNames())
+		}
+
+		fs, _ := cmd.flagSet(a, &outputOptions{})
+		fs.Usage()
+
+		return nil, nil
+	}
+}
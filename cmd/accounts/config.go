@@ -0,0 +1,97 @@
+package main
+
+import (
+	"awesomeProject/accounts/transport"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const defaultProfile = "default"
+
+// Profile — параметры подключения к серверу.
+type Profile struct {
+	Transport string `json:"transport"`
+	Address   string `json:"address"`
+}
+
+// Config хранится в $XDG_CONFIG_HOME/accounts/config.json (или по пути из --config / ACCOUNTS_CONFIG).
+type Config struct {
+	CurrentProfile string             `json:"current_profile"`
+	Profiles       map[string]Profile `json:"profiles"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		CurrentProfile: defaultProfile,
+		Profiles: map[string]Profile{
+			defaultProfile: {Transport: transport.HTTP, Address: "localhost:7777"},
+		},
+	}
+}
+
+func defaultConfigPath() string {
+	if path := os.Getenv("ACCOUNTS_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "accounts.json"
+	}
+
+	return filepath.Join(dir, "accounts", "config.json")
+}
+
+// loadConfig читает конфиг; если файла ещё нет, возвращает конфиг по умолчанию.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return defaultConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config failed: %w", err)
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parse config %s failed: %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+	if config.CurrentProfile == "" {
+		config.CurrentProfile = defaultProfile
+	}
+
+	return config, nil
+}
+
+func saveConfig(path string, config *Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json marshal failed: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create config dir failed: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write config failed: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"awesomeProject/accounts/errs"
+	"errors"
+	"fmt"
+)
+
+// Коды выхода по категориям ошибок, чтобы скрипты могли отличать «нет аккаунта» от «сервер недоступен».
+const (
+	exitOK                 = 0
+	exitFailure            = 1
+	exitUsage              = 2
+	exitNotFound           = 3
+	exitAlreadyExists      = 4
+	exitInvalidArgument    = 5
+	exitUnavailable        = 6
+	exitAborted            = 7
+	exitFailedPrecondition = 8
+)
+
+// usageError — неверные аргументы командной строки.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return exitUsage
+	}
+
+	var e *errs.Error
+	if !errors.As(err, &e) {
+		return exitFailure
+	}
+
+	switch e.Code {
+	case errs.NotFound:
+		return exitNotFound
+	case errs.AlreadyExists:
+		return exitAlreadyExists
+	case errs.InvalidArgument:
+		return exitInvalidArgument
+	case errs.Unavailable:
+		return exitUnavailable
+	case errs.Aborted:
+		return exitAborted
+	case errs.FailedPrecondition:
+		return exitFailedPrecondition
+	default:
+		return exitFailure
+	}
+}
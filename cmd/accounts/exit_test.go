@@ -0,0 +1,57 @@
+package main
+
+import (
+	"awesomeProject/accounts/errs"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestExitCodes проверяет код выхода для каждого кода каталога в том виде, в каком ошибку
+// получает CLI: разобранной из Problem по HTTP и из статуса по gRPC.
+func TestExitCodes(t *testing.T) {
+	want := map[errs.Code]int{
+		errs.InvalidArgument:    exitInvalidArgument,
+		errs.NotFound:           exitNotFound,
+		errs.AlreadyExists:      exitAlreadyExists,
+		errs.Unavailable:        exitUnavailable,
+		errs.Aborted:            exitAborted,
+		errs.FailedPrecondition: exitFailedPrecondition,
+		errs.Internal:           exitFailure,
+	}
+	for _, code := range errs.Codes {
+		t.Run(string(code), func(t *testing.T) {
+			exit, ok := want[code]
+			if !ok {
+				t.Fatalf("no exit code for %s", code)
+			}
+			sent := errs.New(code, "failed", nil)
+
+			status, problem := errs.ToProblem(sent)
+			body, err := json.Marshal(problem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := exitCode(errs.FromHTTP(status, body)); got != exit {
+				t.Errorf("http: exit code %d, want %d", got, exit)
+			}
+			if got := exitCode(errs.FromStatus(errs.ToStatus(sent))); got != exit {
+				t.Errorf("grpc: exit code %d, want %d", got, exit)
+			}
+			if got := exitCode(fmt.Errorf("get alice: %w", sent)); got != exit {
+				t.Errorf("wrapped: exit code %d, want %d", got, exit)
+			}
+		})
+	}
+
+	if got := exitCode(nil); got != exitOK {
+		t.Errorf("nil: exit code %d, want %d", got, exitOK)
+	}
+	if got := exitCode(usageErrorf("expected NAME")); got != exitUsage {
+		t.Errorf("usage: exit code %d, want %d", got, exitUsage)
+	}
+	if got := exitCode(errors.New("config is unreadable")); got != exitFailure {
+		t.Errorf("other: exit code %d, want %d", got, exitFailure)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"awesomeProject/accounts/transport"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// app — состояние CLI, общее для всех команд одного запуска.
+type app struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+
+	configPath string
+	config     *Config
+
+	profileName string
+	profile     Profile
+	timeout     time.Duration
+	actor       string
+
+	accounts transport.Accounts
+	// output — флаги вывода последней команды; по ним же печатается ошибка.
+	output *outputOptions
+	// inShell — команды выполняются из интерактивного shell.
+	inShell bool
+}
+
+func main() {
+	a := &app{in: os.Stdin, out: os.Stdout, errOut: os.Stderr}
+
+	err := a.run(os.Args[1:])
+	if err != nil {
+		a.output.printError(a.errOut, err)
+	}
+
+	os.Exit(exitCode(err))
+}
+
+func (a *app) run(args []string) error {
+	global := flag.NewFlagSet("accounts", flag.ContinueOnError)
+	global.SetOutput(a.errOut)
+	configVal := global.String("config", defaultConfigPath(), "path to the config file with connection profiles")
+	profileVal := global.String("profile", os.Getenv("ACCOUNTS_PROFILE"), "connection profile from the config file")
+	transportVal := global.String("transport", "", "transport to use: http or grpc (overrides the profile)")
+	addressVal := global.String("address", "", "server address, host:port (overrides the profile)")
+	timeoutVal := global.Duration("timeout", 5*time.Second, "timeout of a single command")
+	actorVal := global.String("actor", defaultActor(), "name recorded as the author of changes, e.g. deletions")
+	global.Usage = func() { a.printUsage(global) }
+
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{message: err.Error()}
+	}
+
+	config, err := loadConfig(*configVal)
+	if err != nil {
+		return err
+	}
+	a.configPath = *configVal
+	a.config = config
+	a.timeout = *timeoutVal
+	a.actor = *actorVal
+
+	a.profileName = *profileVal
+	if a.profileName == "" {
+		a.profileName = config.CurrentProfile
+	}
+	a.profile = config.Profiles[a.profileName]
+	if *transportVal != "" {
+		a.profile.Transport = *transportVal
+	}
+	if *addressVal != "" {
+		a.profile.Address = *addressVal
+	}
+
+	defer a.close()
+
+	if global.NArg() == 0 {
+		a.printUsage(global)
+		return usageErrorf("no command given")
+	}
+
+	return a.runCommand(context.Background(), global.Args())
+}
+
+// conn лениво открывает соединение, чтобы команды profile и help работали без сервера.
+func (a *app) conn() (transport.Accounts, error) {
+	if a.accounts != nil {
+		return a.accounts, nil
+	}
+
+	if a.profile.Transport == "" || a.profile.Address == "" {
+		return nil, usageErrorf("profile %q has no transport or address; use --transport/--address or 'accounts profile set'", a.profileName)
+	}
+
+	var opts []transport.DialOption
+	if a.actor != "" {
+		opts = append(opts, transport.WithActor(a.actor))
+	}
+	accounts, err := transport.Dial(a.profile.Transport, a.profile.Address, opts...)
+	if err != nil {
+		return nil, &usageError{message: err.Error()}
+	}
+	a.accounts = accounts
+
+	return accounts, nil
+}
+
+// defaultActor — ACCOUNTS_ACTOR или, если он не задан, имя пользователя ОС.
+func defaultActor() string {
+	if actor := os.Getenv("ACCOUNTS_ACTOR"); actor != "" {
+		return actor
+	}
+
+	return os.Getenv("USER")
+}
+
+func (a *app) close() {
+	if a.accounts != nil {
+		_ = a.accounts.Close()
+		a.accounts = nil
+	}
+}
+
+func (a *app) printUsage(global *flag.FlagSet) {
+	fmt.Fprintf(a.errOut, "usage: accounts [global flags] <command> [flags] [args]\n\ncommands:\n")
+
+	width := 0
+	for _, cmd := range commands {
+		width = max(width, len(cmd.name))
+	}
+	for _, cmd := range commands {
+		fmt.Fprintf(a.errOut, "  %-*s  %s\n", width, cmd.name, cmd.summary)
+	}
+
+	fmt.Fprintf(a.errOut, "\n%s\n\nglobal flags:\n", argumentsNote)
+	global.PrintDefaults()
+	fmt.Fprintf(a.errOut, "\nrun 'accounts help <command>' for details about a command\n")
+}
+
+// argumentsNote объясняет в справке, как parseInterspersed отделяет флаги от аргументов.
+const argumentsNote = "flags may go before or after arguments; a number such as -5 is an argument, not a flag, and -- ends the flags"
+
+// parseInterspersed разбирает флаги вперемешку с позиционными аргументами: create alice --amount 5.
+// Числа вроде -5 остаются аргументами (set-amount alice -5), после -- всё считается аргументами.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case !strings.HasPrefix(arg, "-") || arg == "-" || isNumber(arg):
+			positional = append(positional, arg)
+		default:
+			flags = append(flags, arg)
+			if takesValue(fs, arg) && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		}
+	}
+
+	if err := fs.Parse(flags); err != nil {
+		return nil, err
+	}
+
+	return positional, nil
+}
+
+// takesValue сообщает, что флаг arg без "=значение" забирает следующий аргумент.
+// Неизвестные флаги значения не забирают: о них сообщит fs.Parse.
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	if strings.Contains(name, "=") {
+		return false
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return false
+	}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return false
+	}
+
+	return true
+}
+
+func isNumber(arg string) bool {
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+func commandNames() string {
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+
+	return strings.Join(names, ", ")
+}
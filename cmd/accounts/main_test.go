@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		amount     int
+		deleted    bool
+		err        bool
+	}{
+		{name: "flags after arguments", args: []string{"alice", "--amount", "5"}, positional: []string{"alice"}, amount: 5},
+		{name: "flags before arguments", args: []string{"--deleted", "alice", "bob"}, positional: []string{"alice", "bob"}, deleted: true},
+		{name: "negative argument", args: []string{"alice", "-5"}, positional: []string{"alice", "-5"}},
+		{name: "negative argument between flags", args: []string{"--deleted", "-1.5", "--amount=3"}, positional: []string{"-1.5"}, amount: 3, deleted: true},
+		{name: "negative flag value", args: []string{"alice", "--amount", "-5"}, positional: []string{"alice"}, amount: -5},
+		{name: "double dash ends flags", args: []string{"--amount", "1", "--", "--deleted", "-x"}, positional: []string{"--deleted", "-x"}, amount: 1},
+		{name: "dash is an argument", args: []string{"-"}, positional: []string{"-"}},
+		{name: "unknown flag", args: []string{"alice", "-x"}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			amount := fs.Int("amount", 0, "")
+			deleted := fs.Bool("deleted", false, "")
+
+			positional, err := parseInterspersed(fs, tt.args)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("parseInterspersed(%q) succeeded, want an error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(positional, tt.positional) || *amount != tt.amount || *deleted != tt.deleted {
+				t.Fatalf("parseInterspersed(%q) = %q, amount %d, deleted %t", tt.args, positional, *amount, *deleted)
+			}
+		})
+	}
+}
+
+// TestReadBatchNegativeNumbers: строки batch разбираются тем же парсером, что и командная строка.
+func TestReadBatchNegativeNumbers(t *testing.T) {
+	a := &app{in: strings.NewReader("set-amount alice -5\ncreate bob -- -3\nset-amount alice -z\n"), errOut: io.Discard}
+	if _, err := a.readBatch("-"); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("readBatch() = %v, want an error at line 3", err)
+	}
+
+	a.in = strings.NewReader("set-amount alice -5\ncreate bob -- -3\n")
+	lines, err := a.readBatch("-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range lines {
+		fs, _ := line.cmd.flagSet(a, &outputOptions{})
+		fs.SetOutput(io.Discard)
+		positional, err := parseInterspersed(fs, line.words[1:])
+		if err != nil {
+			t.Fatalf("line %d: %v", line.number, err)
+		}
+		if want := line.words[len(line.words)-1]; positional[len(positional)-1] != want {
+			t.Errorf("line %d: arguments %q, want the last one %q", line.number, positional, want)
+		}
+	}
+}
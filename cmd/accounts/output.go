@@ -0,0 +1,605 @@
+package main
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/models"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+	outputCSV   = "csv"
+)
+
+// accountView — аккаунт в выводе CLI; имена полей одинаковы во всех форматах.
+type accountView struct {
+	ID     string `json:"id" yaml:"id"`
+	Name   string `json:"name" yaml:"name"`
+	Amount int    `json:"amount" yaml:"amount"`
+	// Held — сумма активных холдов; доступно Amount - Held.
+	Held      int    `json:"held,omitempty" yaml:"held,omitempty"`
+	Status    string `json:"status" yaml:"status"`
+	DeletedAt string `json:"deleted_at,omitempty" yaml:"deleted_at,omitempty"`
+	DeletedBy string `json:"deleted_by,omitempty" yaml:"deleted_by,omitempty"`
+	// Labels записаны как key=value через запятую, чтобы уместиться в колонку таблицы.
+	Labels      string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// RedirectedFrom — старое имя, по которому get нашёл переименованный аккаунт.
+	RedirectedFrom string `json:"redirected_from,omitempty" yaml:"redirected_from,omitempty"`
+}
+
+func viewOf(account models.Account) accountView {
+	view := accountView{
+		ID:          account.ID,
+		Name:        account.Name,
+		Amount:      account.Amount,
+		Held:        account.Held,
+		Status:      string(account.Status),
+		Labels:      models.FormatLabels(account.Labels),
+		Description: account.Description,
+	}
+	if account.Deleted() {
+		view.DeletedAt = account.DeletedAt.Format(time.RFC3339)
+		view.DeletedBy = account.DeletedBy
+	}
+
+	return view
+}
+
+func viewsOf(accounts []models.Account) []accountView {
+	views := make([]accountView, 0, len(accounts))
+	for _, account := range accounts {
+		views = append(views, viewOf(account))
+	}
+
+	return views
+}
+
+// transitionView — запись истории статусов.
+type transitionView struct {
+	From   string `json:"from" yaml:"from"`
+	To     string `json:"to" yaml:"to"`
+	Reason string `json:"reason" yaml:"reason"`
+	Actor  string `json:"actor" yaml:"actor"`
+	At     string `json:"at" yaml:"at"`
+}
+
+func transitionViewsOf(history []models.Transition) []transitionView {
+	views := make([]transitionView, 0, len(history))
+	for _, t := range history {
+		views = append(views, transitionView{
+			From:   string(t.From),
+			To:     string(t.To),
+			Reason: t.Reason,
+			Actor:  t.Actor,
+			At:     t.At.Format(time.RFC3339),
+		})
+	}
+
+	return views
+}
+
+// renameView — запись истории переименований.
+type renameView struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+	At   string `json:"at" yaml:"at"`
+}
+
+func renameViewsOf(history []models.Rename) []renameView {
+	views := make([]renameView, 0, len(history))
+	for _, r := range history {
+		views = append(views, renameView{From: r.From, To: r.To, At: r.At.Format(time.RFC3339)})
+	}
+
+	return views
+}
+
+// holdView — холд; ClosedAt пуст у активного холда.
+type holdView struct {
+	ID        string `json:"id" yaml:"id"`
+	AccountID string `json:"account_id" yaml:"account_id"`
+	Amount    int    `json:"amount" yaml:"amount"`
+	Status    string `json:"status" yaml:"status"`
+	Captured  int    `json:"captured,omitempty" yaml:"captured,omitempty"`
+	ExpiresAt string `json:"expires_at" yaml:"expires_at"`
+	ClosedAt  string `json:"closed_at,omitempty" yaml:"closed_at,omitempty"`
+}
+
+func holdViewOf(hold models.Hold) holdView {
+	view := holdView{
+		ID:        hold.ID,
+		AccountID: hold.AccountID,
+		Amount:    hold.Amount,
+		Status:    string(hold.Status),
+		Captured:  hold.Captured,
+		ExpiresAt: hold.ExpiresAt.Format(time.RFC3339),
+	}
+	if !hold.ClosedAt.IsZero() {
+		view.ClosedAt = hold.ClosedAt.Format(time.RFC3339)
+	}
+
+	return view
+}
+
+func holdViewsOf(holds []models.Hold) []holdView {
+	views := make([]holdView, 0, len(holds))
+	for _, hold := range holds {
+		views = append(views, holdViewOf(hold))
+	}
+
+	return views
+}
+
+// captureView — результат списания холда вместе с новым балансом аккаунта.
+type captureView struct {
+	Hold     string `json:"hold" yaml:"hold"`
+	Captured int    `json:"captured" yaml:"captured"`
+	Account  string `json:"account" yaml:"account"`
+	Amount   int    `json:"amount" yaml:"amount"`
+	Held     int    `json:"held,omitempty" yaml:"held,omitempty"`
+}
+
+// scheduleView — расписание перевода; From и To — ID аккаунтов.
+type scheduleView struct {
+	ID          string `json:"id" yaml:"id"`
+	From        string `json:"from" yaml:"from"`
+	To          string `json:"to" yaml:"to"`
+	Amount      int    `json:"amount" yaml:"amount"`
+	Cron        string `json:"cron,omitempty" yaml:"cron,omitempty"`
+	Status      string `json:"status" yaml:"status"`
+	NextRunAt   string `json:"next_run_at,omitempty" yaml:"next_run_at,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+func scheduleViewOf(schedule models.Schedule) scheduleView {
+	view := scheduleView{
+		ID:          schedule.ID,
+		From:        schedule.From,
+		To:          schedule.To,
+		Amount:      schedule.Amount,
+		Cron:        schedule.Cron,
+		Status:      string(schedule.Status),
+		Description: schedule.Description,
+	}
+	if !schedule.NextRunAt.IsZero() {
+		view.NextRunAt = schedule.NextRunAt.Format(time.RFC3339)
+	}
+
+	return view
+}
+
+func scheduleViewsOf(schedules []models.Schedule) []scheduleView {
+	views := make([]scheduleView, 0, len(schedules))
+	for _, schedule := range schedules {
+		views = append(views, scheduleViewOf(schedule))
+	}
+
+	return views
+}
+
+// runView — исполнение расписания.
+type runView struct {
+	DueAt  string `json:"due_at" yaml:"due_at"`
+	Status string `json:"status" yaml:"status"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+	RanAt  string `json:"ran_at" yaml:"ran_at"`
+}
+
+func runViewsOf(runs []models.ScheduleRun) []runView {
+	views := make([]runView, 0, len(runs))
+	for _, r := range runs {
+		views = append(views, runView{
+			DueAt:  r.DueAt.Format(time.RFC3339),
+			Status: string(r.Status),
+			Error:  r.Error,
+			RanAt:  r.RanAt.Format(time.RFC3339),
+		})
+	}
+
+	return views
+}
+
+// accrualView — пакет начисления процентов.
+type accrualView struct {
+	ID         string `json:"id" yaml:"id"`
+	Status     string `json:"status" yaml:"status"`
+	Postings   int    `json:"postings" yaml:"postings"`
+	Total      int    `json:"total" yaml:"total"`
+	Cursor     string `json:"cursor,omitempty" yaml:"cursor,omitempty"`
+	StartedAt  string `json:"started_at" yaml:"started_at"`
+	FinishedAt string `json:"finished_at,omitempty" yaml:"finished_at,omitempty"`
+}
+
+func accrualViewOf(accrual models.Accrual) accrualView {
+	view := accrualView{
+		ID:        accrual.ID,
+		Status:    string(accrual.Status),
+		Postings:  accrual.Postings,
+		Total:     accrual.Total,
+		Cursor:    accrual.Cursor,
+		StartedAt: accrual.StartedAt.Format(time.RFC3339),
+	}
+	if !accrual.FinishedAt.IsZero() {
+		view.FinishedAt = accrual.FinishedAt.Format(time.RFC3339)
+	}
+
+	return view
+}
+
+// postingView — расчётная проводка процентов.
+type postingView struct {
+	Name    string `json:"name" yaml:"name"`
+	Type    string `json:"type" yaml:"type"`
+	Balance int    `json:"balance" yaml:"balance"`
+	RateBPS int    `json:"rate_bps" yaml:"rate_bps"`
+	Amount  int    `json:"amount" yaml:"amount"`
+}
+
+func postingViewsOf(postings []models.Posting) []postingView {
+	views := make([]postingView, 0, len(postings))
+	for _, p := range postings {
+		views = append(views, postingView{Name: p.Name, Type: p.Type, Balance: p.Balance, RateBPS: p.Rate, Amount: p.Amount})
+	}
+
+	return views
+}
+
+// ledgerView — проводка по счёту.
+type ledgerView struct {
+	PostedAt  string `json:"posted_at" yaml:"posted_at"`
+	Kind      string `json:"kind" yaml:"kind"`
+	Amount    int    `json:"amount" yaml:"amount"`
+	Balance   int    `json:"balance" yaml:"balance"`
+	Reference string `json:"reference" yaml:"reference"`
+}
+
+func ledgerViewsOf(entries []models.LedgerEntry) []ledgerView {
+	views := make([]ledgerView, 0, len(entries))
+	for _, e := range entries {
+		views = append(views, ledgerView{
+			PostedAt:  e.PostedAt.Format(time.RFC3339),
+			Kind:      string(e.Kind),
+			Amount:    e.Amount,
+			Balance:   e.Balance,
+			Reference: e.Reference,
+		})
+	}
+
+	return views
+}
+
+// webhookView — подписка на события; Secret виден только сразу после создания.
+type webhookView struct {
+	ID        string `json:"id" yaml:"id"`
+	URL       string `json:"url" yaml:"url"`
+	Events    string `json:"events" yaml:"events"`
+	Accounts  string `json:"accounts" yaml:"accounts"`
+	Secret    string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	CreatedAt string `json:"created_at" yaml:"created_at"`
+}
+
+func webhookViewOf(subscription models.Subscription) webhookView {
+	view := webhookView{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    "*",
+		Accounts:  "*",
+		Secret:    subscription.Secret,
+		CreatedAt: subscription.CreatedAt.Format(time.RFC3339),
+	}
+	if len(subscription.EventTypes) > 0 {
+		events := make([]string, 0, len(subscription.EventTypes))
+		for _, eventType := range subscription.EventTypes {
+			events = append(events, string(eventType))
+		}
+		view.Events = strings.Join(events, ",")
+	}
+	if len(subscription.Accounts) > 0 {
+		view.Accounts = strings.Join(subscription.Accounts, ",")
+	}
+
+	return view
+}
+
+// deliveryView — доставка события подписке.
+type deliveryView struct {
+	ID            string `json:"id" yaml:"id"`
+	Event         string `json:"event" yaml:"event"`
+	Account       string `json:"account" yaml:"account"`
+	Status        string `json:"status" yaml:"status"`
+	Attempts      int    `json:"attempts" yaml:"attempts"`
+	NextAttemptAt string `json:"next_attempt_at,omitempty" yaml:"next_attempt_at,omitempty"`
+	LastError     string `json:"last_error,omitempty" yaml:"last_error,omitempty"`
+}
+
+func deliveryViewOf(delivery models.Delivery) deliveryView {
+	view := deliveryView{
+		ID:        delivery.ID,
+		Event:     string(delivery.EventType),
+		Account:   delivery.AccountID,
+		Status:    string(delivery.Status),
+		Attempts:  delivery.Attempts,
+		LastError: delivery.LastError,
+	}
+	if delivery.Status == models.DeliveryPending {
+		view.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	return view
+}
+
+func deliveryViewsOf(deliveries []models.Delivery) []deliveryView {
+	views := make([]deliveryView, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		views = append(views, deliveryViewOf(delivery))
+	}
+
+	return views
+}
+
+// receivedView — запрос вебхука, принятый webhook-listen.
+type receivedView struct {
+	Delivery string `json:"delivery" yaml:"delivery"`
+	Event    string `json:"event" yaml:"event"`
+	Name     string `json:"name" yaml:"name"`
+	Amount   int    `json:"amount" yaml:"amount"`
+	Status   string `json:"status" yaml:"status"`
+	// Verified — подпись проверена секретом --secret; без секрета не проверяется.
+	Verified bool `json:"verified" yaml:"verified"`
+}
+
+// deletedView — результат удаления.
+type deletedView struct {
+	Name    string `json:"name" yaml:"name"`
+	Deleted bool   `json:"deleted" yaml:"deleted"`
+}
+
+// outputOptions — флаги вывода, общие для всех команд.
+type outputOptions struct {
+	format   string
+	template string
+	quiet    bool
+}
+
+func (o *outputOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "output", outputText, "output format: text, json, yaml, table or csv")
+	fs.StringVar(&o.template, "format", "", "Go template applied to each result, e.g. '{{.Name}}'")
+	fs.BoolVar(&o.quiet, "quiet", false, "print only account names")
+}
+
+func (o *outputOptions) validate() error {
+	switch o.format {
+	case outputText, outputJSON, outputYAML, outputTable, outputCSV:
+		return nil
+	default:
+		return usageErrorf("unknown output format %q", o.format)
+	}
+}
+
+// print выводит результат команды: одно значение или срез структур.
+func (o *outputOptions) print(w io.Writer, result any) error {
+	if result == nil {
+		return nil
+	}
+
+	items := itemsOf(result)
+
+	switch {
+	case o.quiet:
+		for _, item := range items {
+			if name, ok := fieldByTag(item, "name"); ok {
+				fmt.Fprintln(w, name)
+			}
+		}
+		return nil
+	case o.template != "":
+		tmpl, err := template.New("format").Parse(o.template)
+		if err != nil {
+			return usageErrorf("invalid --format template: %v", err)
+		}
+		for _, item := range items {
+			if err := tmpl.Execute(w, item); err != nil {
+				return fmt.Errorf("execute template failed: %w", err)
+			}
+			fmt.Fprintln(w)
+		}
+		return nil
+	}
+
+	switch o.format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case outputYAML:
+		encoder := yaml.NewEncoder(w)
+		defer func() {
+			_ = encoder.Close()
+		}()
+		return encoder.Encode(result)
+	case outputTable:
+		return printTable(w, items, true)
+	case outputCSV:
+		return printCSV(w, items)
+	default:
+		if reflect.ValueOf(result).Kind() == reflect.Slice {
+			return printTable(w, items, true)
+		}
+		return printFields(w, result)
+	}
+}
+
+// printError пишет ошибку в stderr; в режиме json — как структурированный объект.
+func (o *outputOptions) printError(w io.Writer, err error) {
+	if o != nil && o.format == outputJSON {
+		_, problem := errs.ToProblem(err)
+		var usage *usageError
+		switch {
+		case errors.As(err, &usage):
+			problem = errs.Problem{Code: "usage", Message: err.Error()}
+		case problem.Code == errs.Internal:
+			problem.Message = err.Error()
+		}
+
+		encoder := json.NewEncoder(w)
+		_ = encoder.Encode(map[string]any{"error": problem})
+		return
+	}
+
+	fmt.Fprintf(w, "error: %s\n", errs.Format(err))
+}
+
+func itemsOf(result any) []any {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Slice {
+		return []any{result}
+	}
+
+	items := make([]any, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items = append(items, v.Index(i).Interface())
+	}
+
+	return items
+}
+
+type column struct {
+	name      string
+	index     int
+	omitEmpty bool
+}
+
+func columnsOf(t reflect.Type) []column {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		name, options, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		columns = append(columns, column{name: name, index: i, omitEmpty: strings.Contains(options, "omitempty")})
+	}
+
+	return columns
+}
+
+func fieldByTag(item any, tag string) (any, bool) {
+	v := reflect.ValueOf(item)
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for _, c := range columnsOf(v.Type()) {
+		if c.name == tag {
+			return v.Field(c.index).Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+func rowOf(item any, columns []column) []string {
+	v := reflect.ValueOf(item)
+	row := make([]string, 0, len(columns))
+	for _, c := range columns {
+		row = append(row, fmt.Sprint(v.Field(c.index).Interface()))
+	}
+
+	return row
+}
+
+func printTable(w io.Writer, items []any, header bool) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	columns := visibleColumns(items, columnsOf(reflect.TypeOf(items[0])))
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if header {
+		names := make([]string, 0, len(columns))
+		for _, c := range columns {
+			names = append(names, strings.ToUpper(c.name))
+		}
+		fmt.Fprintln(tw, strings.Join(names, "\t"))
+	}
+	for _, item := range items {
+		fmt.Fprintln(tw, strings.Join(rowOf(item, columns), "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// visibleColumns убирает из таблицы omitempty-колонки, пустые во всех строках.
+func visibleColumns(items []any, columns []column) []column {
+	visible := columns[:0:0]
+	for _, c := range columns {
+		keep := !c.omitEmpty
+		for _, item := range items {
+			if keep {
+				break
+			}
+			keep = !reflect.ValueOf(item).Field(c.index).IsZero()
+		}
+		if keep {
+			visible = append(visible, c)
+		}
+	}
+
+	return visible
+}
+
+func printCSV(w io.Writer, items []any) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	columns := columnsOf(reflect.TypeOf(items[0]))
+	cw := csv.NewWriter(w)
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.name)
+	}
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := cw.Write(rowOf(item, columns)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// printFields — текстовый вывод одной записи в виде «поле: значение».
+func printFields(w io.Writer, item any) error {
+	v := reflect.ValueOf(item)
+	if v.Kind() != reflect.Struct {
+		_, err := fmt.Fprintln(w, item)
+		return err
+	}
+
+	for _, c := range columnsOf(v.Type()) {
+		if c.omitEmpty && v.Field(c.index).IsZero() {
+			continue
+		}
+		fmt.Fprintf(w, "%s: %v\n", c.name, v.Field(c.index).Interface())
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"awesomeProject/accounts/transport"
+	"context"
+	"flag"
+)
+
+// profileView — профиль в выводе команды profile.
+type profileView struct {
+	Name      string `json:"name" yaml:"name"`
+	Transport string `json:"transport" yaml:"transport"`
+	Address   string `json:"address" yaml:"address"`
+	Current   bool   `json:"current" yaml:"current"`
+}
+
+func (a *app) profileView(name string) profileView {
+	profile := a.config.Profiles[name]
+
+	return profileView{
+		Name:      name,
+		Transport: profile.Transport,
+		Address:   profile.Address,
+		Current:   name == a.config.CurrentProfile,
+	}
+}
+
+func (a *app) saveProfile(name string) (any, error) {
+	if err := saveConfig(a.configPath, a.config); err != nil {
+		return nil, err
+	}
+
+	return a.profileView(name), nil
+}
+
+func setupProfile(fs *flag.FlagSet) runFunc {
+	transportVal := fs.String("transport", "", "transport of the profile for 'set': http or grpc")
+	addressVal := fs.String("address", "", "server address of the profile for 'set'")
+
+	return func(_ context.Context, a *app, args []string) (any, error) {
+		if len(args) == 0 {
+			return nil, usageErrorf("expected a profile subcommand")
+		}
+
+		switch args[0] {
+		case "list":
+			views := make([]profileView, 0, len(a.config.Profiles))
+			for _, name := range a.config.profileNames() {
+				views = append(views, a.profileView(name))
+			}
+
+			return views, nil
+		case "show":
+			name := a.profileName
+			if len(args) > 1 {
+				name = args[1]
+			}
+			if _, ok := a.config.Profiles[name]; !ok {
+				return nil, usageErrorf("profile %q does not exist", name)
+			}
+
+			return a.profileView(name), nil
+		case "use":
+			if err := expectArgs(args[1:], 1, "NAME"); err != nil {
+				return nil, err
+			}
+			if _, ok := a.config.Profiles[args[1]]; !ok {
+				return nil, usageErrorf("profile %q does not exist", args[1])
+			}
+			a.config.CurrentProfile = args[1]
+
+			return a.saveProfile(args[1])
+		case "set":
+			if err := expectArgs(args[1:], 1, "NAME"); err != nil {
+				return nil, err
+			}
+			if *transportVal != "" && *transportVal != transport.HTTP && *transportVal != transport.GRPC {
+				return nil, usageErrorf("unknown transport %q, expected %s or %s", *transportVal, transport.HTTP, transport.GRPC)
+			}
+			profile := a.config.Profiles[args[1]]
+			if *transportVal != "" {
+				profile.Transport = *transportVal
+			}
+			if *addressVal != "" {
+				profile.Address = *addressVal
+			}
+			a.config.Profiles[args[1]] = profile
+
+			return a.saveProfile(args[1])
+		case "delete":
+			if err := expectArgs(args[1:], 1, "NAME"); err != nil {
+				return nil, err
+			}
+			delete(a.config.Profiles, args[1])
+
+			return nil, saveConfig(a.configPath, a.config)
+		default:
+			return nil, usageErrorf("unknown profile subcommand %q", args[0])
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chzyer/readline"
+)
+
+// shell — интерактивный режим: одно соединение, история, автодополнение и сессии begin/commit.
+type shell struct {
+	app *app
+	rl  *readline.Instance
+
+	// pending — операции, накопленные после begin; nil, если сессии нет.
+	pending [][]string
+
+	namesMu   sync.Mutex
+	names     []string
+	namesTime time.Time
+}
+
+// namesTTL — как долго кешируется список имён для автодополнения.
+const namesTTL = 5 * time.Second
+
+func setupShell(_ *flag.FlagSet) runFunc {
+	return func(_ context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 0, "no arguments"); err != nil {
+			return nil, err
+		}
+		if a.inShell {
+			return nil, usageErrorf("already in shell")
+		}
+
+		return nil, a.runShell()
+	}
+}
+
+func (a *app) runShell() error {
+	sh := &shell{app: a}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:            a.profileName + "> ",
+		HistoryFile:       filepath.Join(filepath.Dir(a.configPath), "history"),
+		AutoComplete:      sh,
+		InterruptPrompt:   "^C",
+		EOFPrompt:         "exit",
+		HistorySearchFold: true,
+		Stdout:            a.out,
+		Stderr:            a.errOut,
+	})
+	if err != nil {
+		return fmt.Errorf("init line editor failed: %w", err)
+	}
+	defer func() {
+		_ = rl.Close()
+	}()
+	sh.rl = rl
+
+	a.inShell = true
+	defer func() {
+		a.inShell = false
+	}()
+
+	fmt.Fprintf(a.out, "connected to %s via %s; type 'help' for commands, 'exit' to quit\n", a.profile.Address, a.profile.Transport)
+
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		words, err := splitWords(line)
+		if err != nil {
+			var plain *outputOptions
+			plain.printError(a.errOut, usageErrorf("%v", err))
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		if done := sh.execute(words); done {
+			return nil
+		}
+	}
+}
+
+// execute выполняет одну строку; возвращает true, если нужно выйти из shell.
+func (sh *shell) execute(words []string) bool {
+	a := sh.app
+
+	switch words[0] {
+	case "exit", "quit":
+		if len(sh.pending) > 0 {
+			fmt.Fprintf(a.errOut, "discarding %d pending operations\n", len(sh.pending))
+		}
+		return true
+	case "help":
+		if len(words) == 1 {
+			sh.printHelp()
+			return false
+		}
+	case "begin":
+		if sh.pending != nil {
+			fmt.Fprintln(a.errOut, "session already started; use commit or rollback")
+			return false
+		}
+		sh.pending = [][]string{}
+		sh.rl.SetPrompt(a.profileName + "*> ")
+		fmt.Fprintln(a.out, "session started: changes are queued until commit")
+		return false
+	case "pending":
+		sh.printPending()
+		return false
+	case "rollback":
+		if sh.pending == nil {
+			fmt.Fprintln(a.errOut, "no session in progress")
+			return false
+		}
+		fmt.Fprintf(a.out, "discarded %d operations\n", len(sh.pending))
+		sh.endSession()
+		return false
+	case "commit":
+		sh.commit(len(words) > 1 && (words[1] == "-y" || words[1] == "--yes"))
+		return false
+	}
+
+	if cmd := findCommand(words[0]); cmd != nil && cmd.mutating && sh.pending != nil {
+		sh.pending = append(sh.pending, words)
+		fmt.Fprintf(a.out, "queued #%d: %s\n", len(sh.pending), joinWords(words))
+		return false
+	}
+
+	if err := a.runCommand(context.Background(), words); err != nil {
+		a.output.printError(a.errOut, err)
+	}
+	sh.invalidateNames()
+
+	return false
+}
+
+// commit показывает накопленные операции, спрашивает подтверждение и выполняет их по порядку.
+// Сервер не поддерживает транзакции, поэтому при первой ошибке выполнение останавливается,
+// а уже применённые операции остаются в силе.
+func (sh *shell) commit(confirmed bool) {
+	a := sh.app
+
+	if sh.pending == nil {
+		fmt.Fprintln(a.errOut, "no session in progress")
+		return
+	}
+	if len(sh.pending) == 0 {
+		fmt.Fprintln(a.out, "nothing to commit")
+		sh.endSession()
+		return
+	}
+
+	sh.printPending()
+	if !confirmed {
+		sh.rl.SetPrompt(fmt.Sprintf("apply %d operations? [y/N] ", len(sh.pending)))
+		answer, err := sh.rl.Readline()
+		sh.rl.SetPrompt(a.profileName + "*> ")
+		if err != nil || !strings.EqualFold(strings.TrimSpace(answer), "y") {
+			fmt.Fprintln(a.out, "commit cancelled; operations are still pending")
+			return
+		}
+	}
+
+	for i, words := range sh.pending {
+		if err := a.runCommand(context.Background(), words); err != nil {
+			a.output.printError(a.errOut, err)
+			fmt.Fprintf(a.errOut, "stopped at #%d; %d operations applied, %d not applied\n", i+1, i, len(sh.pending)-i)
+			sh.pending = sh.pending[i:]
+			return
+		}
+	}
+
+	fmt.Fprintf(a.out, "applied %d operations\n", len(sh.pending))
+	sh.endSession()
+	sh.invalidateNames()
+}
+
+func (sh *shell) endSession() {
+	sh.pending = nil
+	sh.rl.SetPrompt(sh.app.profileName + "> ")
+}
+
+func (sh *shell) printPending() {
+	if sh.pending == nil {
+		fmt.Fprintln(sh.app.errOut, "no session in progress")
+		return
+	}
+	for i, words := range sh.pending {
+		fmt.Fprintf(sh.app.out, "  #%d: %s\n", i+1, joinWords(words))
+	}
+}
+
+func (sh *shell) printHelp() {
+	out := sh.app.out
+	fmt.Fprintln(out, "commands:")
+	for _, cmd := range commands {
+		if cmd.name == "shell" {
+			continue
+		}
+		fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(out, "session:")
+	fmt.Fprintln(out, "  begin      queue create/delete/set-amount/rename instead of running them")
+	fmt.Fprintln(out, "  pending    show queued operations")
+	fmt.Fprintln(out, "  commit     confirm and apply queued operations (-y to skip confirmation)")
+	fmt.Fprintln(out, "  rollback   discard queued operations")
+	fmt.Fprintln(out, "  exit       leave the shell")
+}
+
+// Do реализует readline.AutoCompleter: первое слово — команда, далее — имена аккаунтов.
+func (sh *shell) Do(line []rune, pos int) ([][]rune, int) {
+	words := strings.Fields(string(line[:pos]))
+	if pos > 0 && (line[pos-1] == ' ' || line[pos-1] == '\t') {
+		words = append(words, "")
+	}
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	prefix := words[len(words)-1]
+	var candidates []string
+	if len(words) == 1 {
+		candidates = append(candidates, "begin", "commit", "pending", "rollback", "exit")
+		for _, cmd := range commands {
+			if cmd.name != "shell" {
+				candidates = append(candidates, cmd.name)
+			}
+		}
+	} else if cmd := findCommand(words[0]); cmd != nil && cmd.completesNames {
+		candidates = sh.accountNames()
+	}
+
+	var completions [][]rune
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			completions = append(completions, []rune(candidate[len(prefix):]+" "))
+		}
+	}
+
+	return completions, len([]rune(prefix))
+}
+
+// accountNames возвращает имена аккаунтов с сервера, кешируя их на namesTTL.
+func (sh *shell) accountNames() []string {
+	sh.namesMu.Lock()
+	defer sh.namesMu.Unlock()
+
+	if time.Since(sh.namesTime) < namesTTL {
+		return sh.names
+	}
+
+	conn, err := sh.app.conn()
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	accounts, err := conn.List(ctx)
+	if err != nil {
+		return sh.names
+	}
+
+	sh.names = sh.names[:0]
+	for _, account := range accounts {
+		sh.names = append(sh.names, account.Name)
+	}
+	sort.Strings(sh.names)
+	sh.namesTime = time.Now()
+
+	return sh.names
+}
+
+func (sh *shell) invalidateNames() {
+	sh.namesMu.Lock()
+	sh.namesTime = time.Time{}
+	sh.namesMu.Unlock()
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/transport"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	actionRename = "rename"
+	actionUpdate = "update"
+	actionCreate = "create"
+	actionDelete = "delete"
+)
+
+// DesiredState — файл для plan/apply. JSON тоже подходит: он является подмножеством YAML.
+type DesiredState struct {
+	Accounts []DesiredAccount `json:"accounts" yaml:"accounts"`
+}
+
+// DesiredAccount — аккаунт, который должен быть на сервере.
+// RenamedFrom — старое имя: если оно есть на сервере, аккаунт переименовывается, а не создаётся заново.
+type DesiredAccount struct {
+	Name        string `json:"name" yaml:"name"`
+	Amount      int    `json:"amount" yaml:"amount"`
+	RenamedFrom string `json:"renamed_from,omitempty" yaml:"renamed_from,omitempty"`
+}
+
+// change — одно действие плана.
+type change struct {
+	Action string `json:"action" yaml:"action"`
+	Name   string `json:"name" yaml:"name"`
+	Detail string `json:"detail" yaml:"detail"`
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	from   string
+	amount int
+}
+
+// syncFlags — флаги, общие для plan и apply.
+type syncFlags struct {
+	file  *string
+	prune *bool
+}
+
+func registerSyncFlags(fs *flag.FlagSet) syncFlags {
+	return syncFlags{
+		file:  fs.String("f", "", "YAML or JSON file with the desired accounts"),
+		prune: fs.Bool("prune", false, "delete accounts that are missing from the file"),
+	}
+}
+
+func setupPlan(fs *flag.FlagSet) runFunc {
+	flags := registerSyncFlags(fs)
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		changes, err := a.plan(ctx, args, flags)
+		if err != nil {
+			return nil, err
+		}
+
+		return changes, nil
+	}
+}
+
+func setupApply(fs *flag.FlagSet) runFunc {
+	flags := registerSyncFlags(fs)
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		changes, err := a.plan(ctx, args, flags)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		// Действия выполняются по порядку до первой ошибки: сервер не умеет применять их атомарно.
+		var failed error
+		for i := range changes {
+			if failed != nil {
+				changes[i].Status = statusSkipped
+				continue
+			}
+			if err := a.applyChange(ctx, conn, changes[i]); err != nil {
+				changes[i].Status = statusFailed
+				changes[i].Error = err.Error()
+				failed = fmt.Errorf("%s %s failed: %w", changes[i].Action, changes[i].Name, err)
+				continue
+			}
+			changes[i].Status = statusOK
+		}
+
+		if err := a.output.print(a.out, changes); err != nil {
+			return nil, err
+		}
+
+		return nil, failed
+	}
+}
+
+// plan сравнивает файл с сервером и печатает сводку в stderr.
+func (a *app) plan(ctx context.Context, args []string, flags syncFlags) ([]change, error) {
+	if err := expectArgs(args, 0, "no arguments"); err != nil {
+		return nil, err
+	}
+	if *flags.file == "" {
+		return nil, usageErrorf("-f FILE is required")
+	}
+
+	desired, err := loadDesiredState(*flags.file)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := a.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	live, err := conn.List(listCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, unmanaged := diff(desired, live, *flags.prune)
+	printPlanSummary(a.errOut, changes, unmanaged)
+
+	return changes, nil
+}
+
+func loadDesiredState(path string) (DesiredState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DesiredState{}, fmt.Errorf("read desired state failed: %w", err)
+	}
+
+	var state DesiredState
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return DesiredState{}, usageErrorf("parse %s: %v", path, err)
+	}
+
+	names := make(map[string]bool, len(state.Accounts))
+	for _, account := range state.Accounts {
+		if account.Name == "" {
+			return DesiredState{}, usageErrorf("%s: account without a name", path)
+		}
+		if names[account.Name] {
+			return DesiredState{}, usageErrorf("%s: account %q is listed twice", path, account.Name)
+		}
+		names[account.Name] = true
+	}
+	for _, account := range state.Accounts {
+		if account.RenamedFrom != "" && names[account.RenamedFrom] {
+			return DesiredState{}, usageErrorf("%s: %q is renamed from %q, which is also listed", path, account.Name, account.RenamedFrom)
+		}
+	}
+
+	return state, nil
+}
+
+// diff строит план: сначала переименования, затем изменения сумм, создания и удаления.
+// unmanaged — аккаунты сервера, которых нет в файле, если --prune не задан.
+func diff(desired DesiredState, live []models.Account, prune bool) ([]change, []string) {
+	current := make(map[string]models.Account, len(live))
+	for _, account := range live {
+		current[account.Name] = account
+	}
+
+	var renames, updates, creates, deletes []change
+	claimed := make(map[string]bool, len(desired.Accounts))
+	for _, want := range desired.Accounts {
+		have, exists := current[want.Name]
+		if !exists && want.RenamedFrom != "" {
+			if old, ok := current[want.RenamedFrom]; ok && !claimed[old.Name] {
+				claimed[old.Name] = true
+				renames = append(renames, change{Action: actionRename, Name: want.Name, Detail: fmt.Sprintf("%s -> %s", old.Name, want.Name), from: old.Name})
+				have, exists = old, true
+			}
+		}
+
+		switch {
+		case !exists:
+			creates = append(creates, change{Action: actionCreate, Name: want.Name, Detail: fmt.Sprintf("amount %d", want.Amount), amount: want.Amount})
+		case have.Amount != want.Amount:
+			updates = append(updates, change{Action: actionUpdate, Name: want.Name, Detail: fmt.Sprintf("amount %d -> %d", have.Amount, want.Amount), amount: want.Amount})
+		}
+		claimed[want.Name] = true
+	}
+
+	var unmanaged []string
+	for _, account := range live {
+		if claimed[account.Name] {
+			continue
+		}
+		if !prune {
+			unmanaged = append(unmanaged, account.Name)
+			continue
+		}
+		deletes = append(deletes, change{Action: actionDelete, Name: account.Name, Detail: fmt.Sprintf("amount %d", account.Amount)})
+	}
+
+	changes := append(renames, updates...)
+	changes = append(changes, creates...)
+	changes = append(changes, deletes...)
+
+	return changes, unmanaged
+}
+
+func printPlanSummary(w io.Writer, changes []change, unmanaged []string) {
+	counts := map[string]int{}
+	for _, c := range changes {
+		counts[c.Action]++
+	}
+
+	fmt.Fprintf(w, "plan: %d to rename, %d to update, %d to create, %d to delete\n",
+		counts[actionRename], counts[actionUpdate], counts[actionCreate], counts[actionDelete])
+	if len(unmanaged) > 0 {
+		fmt.Fprintf(w, "%d accounts are not in the file and are kept; use --prune to delete them\n", len(unmanaged))
+	}
+}
+
+func (a *app) applyChange(ctx context.Context, conn transport.Accounts, c change) error {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	var err error
+	switch c.Action {
+	case actionRename:
+		_, err = conn.Rename(ctx, c.from, c.Name)
+	case actionUpdate:
+		_, err = conn.SetAmount(ctx, c.Name, c.amount)
+	case actionCreate:
+		_, err = conn.Create(ctx, c.Name, c.amount)
+	case actionDelete:
+		err = conn.Delete(ctx, c.Name)
+	}
+
+	return err
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"awesomeProject/accounts/dto"
+	"awesomeProject/accounts/models"
+	"awesomeProject/accounts/webhook"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// stringList — флаг, который можно указать несколько раз.
+type stringList []string
+
+func (l *stringList) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func setupWebhookCreate(fs *flag.FlagSet) runFunc {
+	var events, accounts stringList
+	fs.Var(&events, "event", "event type to deliver, e.g. account.balance_changed; repeat for more, default all")
+	fs.Var(&accounts, "account", "account ID or name to deliver events of; repeat for more, default all")
+	secret := fs.String("secret", "", "signing secret; generated by the server when empty")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "URL"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		subscription := models.Subscription{URL: args[0], Accounts: accounts, Secret: *secret}
+		for _, event := range events {
+			subscription.EventTypes = append(subscription.EventTypes, models.EventType(event))
+		}
+		subscription, err = conn.CreateWebhook(ctx, subscription)
+		if err != nil {
+			return nil, err
+		}
+
+		return webhookViewOf(subscription), nil
+	}
+}
+
+func setupWebhooks(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 0, "no arguments"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions, err := conn.Webhooks(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		views := make([]webhookView, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			views = append(views, webhookViewOf(subscription))
+		}
+
+		return views, nil
+	}
+}
+
+func setupWebhookDelete(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "WEBHOOK_ID"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, conn.DeleteWebhook(ctx, args[0])
+	}
+}
+
+func setupDeliveries(fs *flag.FlagSet) runFunc {
+	status := fs.String("status", "", "only deliveries in this state: pending, delivered or dead")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 1, "WEBHOOK_ID"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries, err := conn.Deliveries(ctx, args[0], models.DeliveryStatus(*status))
+		if err != nil {
+			return nil, err
+		}
+
+		return deliveryViewsOf(deliveries), nil
+	}
+}
+
+func setupReplay(_ *flag.FlagSet) runFunc {
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 2, "WEBHOOK_ID DELIVERY_ID"); err != nil {
+			return nil, err
+		}
+		conn, err := a.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		delivery, err := conn.ReplayDelivery(ctx, args[0], args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return deliveryViewOf(delivery), nil
+	}
+}
+
+// setupWebhookListen запускает приёмник вебхуков для отладки подписок: каждый принятый запрос
+// печатается сразу. С --fail-status приёмник отвечает ошибкой, чтобы проверить повторы
+// и очередь недоставленных. Работает до Ctrl+C или до --count принятых событий.
+func setupWebhookListen(fs *flag.FlagSet) runFunc {
+	addr := fs.String("addr", "localhost:8099", "address to listen on")
+	secret := fs.String("secret", "", "signing secret to verify requests with; requests with a bad signature get 401")
+	failStatus := fs.Int("fail-status", 0, "answer every request with this status code instead of 204")
+	count := fs.Int("count", 0, "exit after this many accepted events; 0 — run until interrupted")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		if err := expectArgs(args, 0, "no arguments"); err != nil {
+			return nil, err
+		}
+		if *failStatus != 0 && (*failStatus < 100 || *failStatus > 599) {
+			return nil, usageErrorf("invalid --fail-status %d", *failStatus)
+		}
+
+		ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		listener, err := net.Listen("tcp", *addr)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(a.errOut, "listening on http://%s\n", listener.Addr())
+
+		var mu sync.Mutex
+		accepted := 0
+		server := &http.Server{ReadHeaderTimeout: 10 * time.Second}
+		server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if *secret != "" {
+				if err := webhook.Verify(*secret, body, r.Header.Get(webhook.SignatureHeader), time.Now(), webhook.DefaultTolerance); err != nil {
+					fmt.Fprintf(a.errOut, "rejected delivery %s: %v\n", r.Header.Get(webhook.DeliveryHeader), err)
+					http.Error(w, err.Error(), http.StatusUnauthorized)
+					return
+				}
+			}
+			var event dto.EventResponse
+			if err := json.Unmarshal(body, &event); err != nil {
+				http.Error(w, "invalid event: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			status := http.StatusNoContent
+			if *failStatus != 0 {
+				status = *failStatus
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			_ = a.output.print(a.out, receivedView{
+				Delivery: r.Header.Get(webhook.DeliveryHeader),
+				Event:    event.Type,
+				Name:     event.Account.Name,
+				Amount:   event.Account.Amount,
+				Status:   http.StatusText(status),
+				Verified: *secret != "",
+			})
+			w.WriteHeader(status)
+			if status >= 300 {
+				return
+			}
+			if accepted++; *count > 0 && accepted >= *count {
+				stop()
+			}
+		})
+
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdown)
+		}()
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
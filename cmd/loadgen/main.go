@@ -0,0 +1,160 @@
+package main
+
+import (
+	"awesomeProject/accounts/client"
+	"awesomeProject/accounts/transport"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type Config struct {
+	Transport   string
+	Address     string
+	Duration    time.Duration
+	Concurrency int
+	// Rate — запросов в секунду; 0 означает закрытую модель с фиксированным числом воркеров.
+	Rate    float64
+	Timeout time.Duration
+	Mix     Mix
+	Keys    int
+	Dist    string
+	ZipfS   float64
+	Prefix  string
+	Seed    int64
+	Prepare bool
+	JSON    string
+}
+
+func main() {
+	transportVal := flag.String("transport", transport.HTTP, "transport: http or grpc")
+	addressVal := flag.String("address", "localhost:7777", "server address, host:port")
+	durationVal := flag.Duration("duration", 30*time.Second, "how long to generate load")
+	concurrencyVal := flag.Int("concurrency", 16, "number of workers; with -rate, the limit of requests in flight")
+	rateVal := flag.Float64("rate", 0, "fixed arrival rate in requests per second; 0 runs workers back to back")
+	timeoutVal := flag.Duration("timeout", 5*time.Second, "timeout of a single request")
+	mixVal := flag.String("mix", "get=70,create=10,change=10,rename=5,delete=5", "operation weights: get, create, change, rename, delete")
+	keysVal := flag.Int("keys", 1000, "number of prepared accounts read and changed by get and change")
+	distVal := flag.String("dist", "uniform", "key distribution: uniform or zipf")
+	zipfSVal := flag.Float64("zipf-s", 1.1, "zipf exponent, must be greater than 1")
+	prefixVal := flag.String("prefix", "lg", "prefix of generated account names")
+	seedVal := flag.Int64("seed", time.Now().UnixNano(), "random seed")
+	prepareVal := flag.Bool("prepare", true, "create all keys before the run")
+	jsonVal := flag.String("json", "", "write the report as JSON to this file, - for stdout")
+	flag.Parse()
+
+	mix, err := ParseMix(*mixVal)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := Config{
+		Transport:   *transportVal,
+		Address:     *addressVal,
+		Duration:    *durationVal,
+		Concurrency: *concurrencyVal,
+		Rate:        *rateVal,
+		Timeout:     *timeoutVal,
+		Mix:         mix,
+		Keys:        *keysVal,
+		Dist:        *distVal,
+		ZipfS:       *zipfSVal,
+		Prefix:      *prefixVal,
+		Seed:        *seedVal,
+		Prepare:     *prepareVal,
+		JSON:        *jsonVal,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cfg Config) error {
+	if cfg.Concurrency < 1 || cfg.Keys < 2 {
+		return fmt.Errorf("-concurrency must be at least 1 and -keys at least 2")
+	}
+	if cfg.Dist != distUniform && cfg.Dist != distZipf {
+		return fmt.Errorf("unknown distribution %q, expected %s or %s", cfg.Dist, distUniform, distZipf)
+	}
+	if cfg.Dist == distZipf && cfg.ZipfS <= 1 {
+		return fmt.Errorf("-zipf-s must be greater than 1")
+	}
+
+	conn, err := dial(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if cfg.Prepare {
+		log.Printf("creating %d accounts", cfg.Keys)
+		if err := prepare(ctx, conn, cfg); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("running %s against %s via %s", cfg.Duration, cfg.Address, cfg.Transport)
+	report := generate(ctx, conn, cfg)
+
+	report.Print(os.Stdout)
+	if cfg.JSON == "" {
+		return nil
+	}
+
+	out := os.Stdout
+	if cfg.JSON != "-" {
+		f, err := os.Create(cfg.JSON)
+		if err != nil {
+			return fmt.Errorf("create report file failed: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("write report failed: %w", err)
+	}
+
+	return nil
+}
+
+// dial открывает соединение без повторов запросов, чтобы они не искажали задержки и ошибки.
+func dial(cfg Config) (transport.Accounts, error) {
+	if cfg.Transport != transport.HTTP {
+		return transport.Dial(cfg.Transport, cfg.Address)
+	}
+
+	address := cfg.Address
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.MaxIdleConnsPerHost = cfg.Concurrency
+	c, err := client.New(address,
+		client.WithHTTPClient(&http.Client{Transport: httpTransport}),
+		client.WithRetryPolicy(client.NoRetry),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.NewHTTP(c), nil
+}
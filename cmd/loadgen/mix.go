@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	opGet    = "get"
+	opCreate = "create"
+	opChange = "change"
+	opRename = "rename"
+	opDelete = "delete"
+
+	distUniform = "uniform"
+	distZipf    = "zipf"
+)
+
+var operations = []string{opGet, opCreate, opChange, opRename, opDelete}
+
+// Mix — веса операций; операция выбирается с вероятностью вес/сумма.
+type Mix map[string]int
+
+// ParseMix разбирает строку вида get=70,create=10.
+func ParseMix(value string) (Mix, error) {
+	mix := Mix{}
+	total := 0
+	for _, part := range strings.Split(value, ",") {
+		op, weightStr, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, expected op=weight", part)
+		}
+		if !isOperation(op) {
+			return nil, fmt.Errorf("unknown operation %q in mix, expected one of %s", op, strings.Join(operations, ", "))
+		}
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight %q for %s", weightStr, op)
+		}
+		mix[op] += weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("mix has no operations with positive weight")
+	}
+
+	return mix, nil
+}
+
+func isOperation(op string) bool {
+	for _, known := range operations {
+		if op == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// picker выбирает операции и ключи; у каждого воркера свой, rand.Rand не потокобезопасен.
+//
+// get и change работают с подготовленными ключами prefix-<номер>, которые не удаляются и не
+// переименовываются. create заводит аккаунт воркера под новым именем, rename и delete трогают
+// только такие аккаунты: удалённое имя остаётся занятым, поэтому имена никогда не повторяются.
+type picker struct {
+	rnd    *rand.Rand
+	zipf   *rand.Zipf
+	mix    Mix
+	total  int
+	keys   int
+	prefix string
+	// names — общая для воркеров прогона часть новых имён: prefix-<вид>-<names>-<n>.
+	names   string
+	counter int
+	// owned — живые аккаунты, которые завёл этот воркер.
+	owned []string
+}
+
+// newPicker создаёт выбор для воркера; run отличает имена этого прогона от прежних.
+func newPicker(cfg Config, run string, worker int) *picker {
+	p := &picker{
+		rnd:    rand.New(rand.NewSource(cfg.Seed + int64(worker))),
+		mix:    cfg.Mix,
+		keys:   cfg.Keys,
+		prefix: cfg.Prefix,
+		names:  run + "-" + strconv.Itoa(worker),
+	}
+	for _, weight := range cfg.Mix {
+		p.total += weight
+	}
+	if cfg.Dist == distZipf {
+		p.zipf = rand.NewZipf(p.rnd, cfg.ZipfS, 1, uint64(cfg.Keys-1))
+	}
+
+	return p
+}
+
+// operation выбирает операцию по весам; пока у воркера нет своих аккаунтов, rename и delete заменяются на create.
+func (p *picker) operation() string {
+	n := p.rnd.Intn(p.total)
+	for _, op := range operations {
+		if n < p.mix[op] {
+			if (op == opRename || op == opDelete) && len(p.owned) == 0 {
+				return opCreate
+			}
+			return op
+		}
+		n -= p.mix[op]
+	}
+
+	return opGet
+}
+
+// fresh возвращает новое имя вида prefix-<kind>-<прогон>-<воркер>-<n>.
+func (p *picker) fresh(kind string) string {
+	p.counter++
+
+	return fmt.Sprintf("%s-%s-%s-%d", p.prefix, kind, p.names, p.counter)
+}
+
+// ownedIndex — случайный аккаунт воркера; вызывается при непустом owned.
+func (p *picker) ownedIndex() int {
+	return p.rnd.Intn(len(p.owned))
+}
+
+// disown забывает аккаунт воркера под номером i.
+func (p *picker) disown(i int) {
+	last := len(p.owned) - 1
+	p.owned[i] = p.owned[last]
+	p.owned = p.owned[:last]
+}
+
+// key возвращает имя аккаунта; при zipf маленькие номера выбираются намного чаще.
+func (p *picker) key() string {
+	if p.zipf != nil {
+		return keyName(p.prefix, int(p.zipf.Uint64()))
+	}
+
+	return keyName(p.prefix, p.rnd.Intn(p.keys))
+}
+
+func (p *picker) amount() int {
+	return p.rnd.Intn(1_000_000)
+}
+
+func keyName(prefix string, i int) string {
+	return fmt.Sprintf("%s-%d", prefix, i)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Report — итог прогона; сериализуется в JSON для сравнения прогонов.
+type Report struct {
+	Transport   string           `json:"transport"`
+	Address     string           `json:"address"`
+	Mix         Mix              `json:"mix"`
+	Keys        int              `json:"keys"`
+	Dist        string           `json:"dist"`
+	Concurrency int              `json:"concurrency"`
+	Rate        float64          `json:"rate,omitempty"`
+	Elapsed     Duration         `json:"elapsed"`
+	Requests    int              `json:"requests"`
+	Errors      int              `json:"errors"`
+	Dropped     int              `json:"dropped,omitempty"`
+	Throughput  float64          `json:"throughput"`
+	Total       Stats            `json:"total"`
+	Operations  map[string]Stats `json:"operations"`
+	// Codes — число результатов по коду: ok, not_found, already_exists, timeout и т.д.
+	Codes map[string]int `json:"codes"`
+}
+
+// Stats — задержки одной группы запросов.
+type Stats struct {
+	Requests int      `json:"requests"`
+	Errors   int      `json:"errors"`
+	P50      Duration `json:"p50"`
+	P90      Duration `json:"p90"`
+	P99      Duration `json:"p99"`
+	Max      Duration `json:"max"`
+}
+
+// Duration пишется в JSON как число миллисекунд, чтобы отчёты было удобно сравнивать.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%.3f", float64(d)/float64(time.Millisecond))), nil
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).Round(time.Microsecond).String()
+}
+
+func NewReport(cfg Config, elapsed time.Duration, samples [][]sample, dropped int) *Report {
+	r := &Report{
+		Transport:   cfg.Transport,
+		Address:     cfg.Address,
+		Mix:         cfg.Mix,
+		Keys:        cfg.Keys,
+		Dist:        cfg.Dist,
+		Concurrency: cfg.Concurrency,
+		Rate:        cfg.Rate,
+		Elapsed:     Duration(elapsed),
+		Dropped:     dropped,
+		Operations:  map[string]Stats{},
+		Codes:       map[string]int{},
+	}
+
+	var all []sample
+	byOp := map[string][]sample{}
+	for _, worker := range samples {
+		for _, s := range worker {
+			all = append(all, s)
+			byOp[s.op] = append(byOp[s.op], s)
+			r.Codes[s.code]++
+		}
+	}
+
+	r.Total = statsOf(all)
+	r.Requests = r.Total.Requests
+	r.Errors = r.Total.Errors
+	r.Throughput = float64(r.Requests) / elapsed.Seconds()
+	for op, group := range byOp {
+		r.Operations[op] = statsOf(group)
+	}
+
+	return r
+}
+
+func statsOf(samples []sample) Stats {
+	stats := Stats{Requests: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		latencies = append(latencies, s.latency)
+		if s.code != codeOK {
+			stats.Errors++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	stats.P50 = Duration(percentile(latencies, 50))
+	stats.P90 = Duration(percentile(latencies, 90))
+	stats.P99 = Duration(percentile(latencies, 99))
+	stats.Max = Duration(latencies[len(latencies)-1])
+
+	return stats
+}
+
+// percentile — по методу ближайшего ранга на отсортированном срезе.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank-1, 0)]
+}
+
+// Print выводит отчёт в человекочитаемом виде.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s %s, %d keys (%s), concurrency %d", r.Transport, r.Address, r.Keys, r.Dist, r.Concurrency)
+	if r.Rate > 0 {
+		fmt.Fprintf(w, ", rate %.0f/s", r.Rate)
+	}
+	fmt.Fprintf(w, "\n%d requests in %s: %.1f req/s, %d errors", r.Requests, r.Elapsed, r.Throughput, r.Errors)
+	if r.Dropped > 0 {
+		fmt.Fprintf(w, ", %d dropped (all workers busy)", r.Dropped)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "OP\tREQUESTS\tERRORS\tP50\tP90\tP99\tMAX")
+	for _, op := range operations {
+		if stats, ok := r.Operations[op]; ok {
+			printStats(tw, op, stats)
+		}
+	}
+	printStats(tw, "total", r.Total)
+	_ = tw.Flush()
+
+	codes := make([]string, 0, len(r.Codes))
+	for code := range r.Codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESULT\tCOUNT\tSHARE")
+	for _, code := range codes {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\n", code, r.Codes[code], 100*float64(r.Codes[code])/float64(max(r.Requests, 1)))
+	}
+	_ = tw.Flush()
+}
+
+func printStats(w io.Writer, name string, s Stats) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", name, s.Requests, s.Errors, s.P50, s.P90, s.P99, s.Max)
+}
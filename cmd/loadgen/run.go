@@ -0,0 +1,166 @@
+package main
+
+import (
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/transport"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sample — результат одного запроса.
+type sample struct {
+	op      string
+	latency time.Duration
+	code    string
+}
+
+func prepare(ctx context.Context, conn transport.Accounts, cfg Config) error {
+	for i := 0; i < cfg.Keys; i++ {
+		reqCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+		_, err := conn.Create(reqCtx, keyName(cfg.Prefix, i), 0)
+		cancel()
+		if err != nil && !errors.Is(err, errs.ErrAlreadyExists) {
+			return fmt.Errorf("prepare %s failed: %w", keyName(cfg.Prefix, i), err)
+		}
+	}
+
+	return nil
+}
+
+// generate подаёт нагрузку в течение cfg.Duration и собирает отчёт.
+// С -rate запросы стартуют по расписанию; если все воркеры заняты, запрос считается пропущенным.
+func generate(ctx context.Context, conn transport.Accounts, cfg Config) *Report {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	var ticks chan struct{}
+	if cfg.Rate > 0 {
+		ticks = make(chan struct{})
+	}
+
+	samples := make([][]sample, cfg.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	run := strconv.FormatInt(start.UnixNano(), 36)
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			p := newPicker(cfg, run, w)
+			for {
+				if ticks != nil {
+					select {
+					case <-ticks:
+					case <-ctx.Done():
+						return
+					}
+				} else if ctx.Err() != nil {
+					return
+				}
+
+				s := execute(ctx, conn, cfg.Timeout, p)
+				if s.code == codeCanceled {
+					return
+				}
+				samples[w] = append(samples[w], s)
+			}
+		}(w)
+	}
+
+	dropped := 0
+	if ticks != nil {
+		dropped = schedule(ctx, ticks, cfg.Rate)
+	}
+	wg.Wait()
+
+	return NewReport(cfg, time.Since(start), samples, dropped)
+}
+
+// schedule выдаёт разрешения на запуск с частотой rate и возвращает число пропущенных.
+func schedule(ctx context.Context, ticks chan<- struct{}, rate float64) int {
+	interval := time.Duration(float64(time.Second) / rate)
+	next := time.Now()
+	dropped := 0
+	for {
+		next = next.Add(interval)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return dropped
+		case <-timer.C:
+		}
+
+		select {
+		case ticks <- struct{}{}:
+		default:
+			dropped++
+		}
+	}
+}
+
+func execute(ctx context.Context, conn transport.Accounts, timeout time.Duration, p *picker) sample {
+	op := p.operation()
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	var err error
+	switch op {
+	case opGet:
+		_, err = conn.Get(reqCtx, p.key())
+	case opCreate:
+		name := p.fresh("c")
+		if _, err = conn.Create(reqCtx, name, p.amount()); err == nil {
+			p.owned = append(p.owned, name)
+		}
+	case opChange:
+		_, err = conn.SetAmount(reqCtx, p.key(), p.amount())
+	case opRename:
+		i, name := p.ownedIndex(), p.fresh("r")
+		if _, err = conn.Rename(reqCtx, p.owned[i], name); err == nil {
+			p.owned[i] = name
+		}
+	case opDelete:
+		// Аккаунт забывается и при ошибке: иначе сбойный ключ выбирался бы снова и снова.
+		i := p.ownedIndex()
+		err = conn.Delete(reqCtx, p.owned[i])
+		p.disown(i)
+	}
+	latency := time.Since(started)
+
+	if err != nil && ctx.Err() != nil {
+		// Запрос прерван окончанием прогона, а не сервером.
+		return sample{op: op, code: codeCanceled}
+	}
+
+	return sample{op: op, latency: latency, code: codeOf(err)}
+}
+
+const (
+	codeOK       = "ok"
+	codeTimeout  = "timeout"
+	codeCanceled = "canceled"
+	codeOther    = "other"
+)
+
+// codeOf — категория результата для разбивки ошибок.
+func codeOf(err error) string {
+	if err == nil {
+		return codeOK
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return codeTimeout
+	}
+
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return string(e.Code)
+	}
+
+	return codeOther
+}
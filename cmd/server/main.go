@@ -2,25 +2,354 @@ package main
 
 import (
 	"awesomeProject/accounts"
+	"awesomeProject/accounts/errs"
+	"awesomeProject/accounts/events"
+	"awesomeProject/accounts/gateway"
+	"awesomeProject/accounts/interest"
+	"awesomeProject/accounts/openapi"
+	"awesomeProject/accounts/rpc"
+	"awesomeProject/accounts/scheduler"
+	"awesomeProject/accounts/storage"
+	"awesomeProject/accounts/webhook"
+	"awesomeProject/proto"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
 )
 
+type Config struct {
+	Store string
+	// MemoryShards — число шардов хранилища в памяти.
+	MemoryShards int
+	DSN          string
+	Addr         string
+	GRPCAddr     string
+	SinglePort   bool
+	// Retention — сколько хранятся удалённые аккаунты до очистки; 0 отключает очистку.
+	Retention time.Duration
+	// PurgeInterval — период запуска очистки.
+	PurgeInterval time.Duration
+	// HoldExpiryInterval — период перевода просроченных холдов в статус expired; 0 отключает.
+	// Резерв просроченного холда снимается и без этого, перевод нужен для истории холдов.
+	HoldExpiryInterval time.Duration
+	// ScheduleInterval — период исполнения наступивших переводов по расписанию; 0 отключает.
+	ScheduleInterval time.Duration
+	// InterestConfig — JSON файл с таблицами процентных ставок; пустой отключает начисление процентов.
+	InterestConfig string
+	// InterestInterval — период проверки, не закончился ли период начисления; 0 оставляет только ручной запуск.
+	InterestInterval time.Duration
+	// WebhookInterval — период отправки доставок вебхуков; 0 отключает отправку, доставки копятся в очереди.
+	WebhookInterval time.Duration
+	// WebhookBackoff — задержка перед повтором неудачной доставки, удваивается с каждой попыткой.
+	WebhookBackoff time.Duration
+	// WebhookMaxAttempts — число попыток, после которого доставка уходит в очередь недоставленных.
+	WebhookMaxAttempts int
+	// OutboxInterval — период передачи событий из outbox хранилища; 0 отключает передачу, события копятся в outbox.
+	OutboxInterval time.Duration
+	// OutboxRetention — сколько доставленные события хранятся в outbox; 0 не удаляет их.
+	OutboxRetention time.Duration
+	// EventLog — файл, в который дописываются события строками JSON; пустой отключает запись.
+	EventLog string
+	// RenameAliasTTL — сколько старое имя после переименования ведёт к аккаунту при чтении; 0 отключает.
+	RenameAliasTTL time.Duration
+}
+
 func main() {
-	accountsHandler := accounts.New()
+	storeVal := flag.String("store", "memory", "account storage: memory or postgres")
+	shardsVal := flag.Int("memory-shards", storage.DefaultShards, "number of lock shards of the memory store")
+	dsnVal := flag.String("dsn", "host=0.0.0.0 port=5432 dbname=postgres user=postgres password=mysecretpassword", "postgres connection string")
+	addrVal := flag.String("addr", ":7777", "HTTP listen address")
+	grpcAddrVal := flag.String("grpc-addr", ":4567", "gRPC listen address, ignored with -single-port")
+	singlePortVal := flag.Bool("single-port", false, "serve HTTP and gRPC on -addr, routed by content type")
+	retentionVal := flag.Duration("retention", 720*time.Hour, "how long deleted accounts can be restored before they are purged, 0 keeps them forever")
+	purgeIntervalVal := flag.Duration("purge-interval", time.Hour, "how often deleted accounts past -retention are purged")
+	holdExpiryIntervalVal := flag.Duration("hold-expiry-interval", time.Minute, "how often holds past their expiry are marked expired, 0 disables")
+	scheduleIntervalVal := flag.Duration("schedule-interval", scheduler.DefaultInterval, "how often due scheduled transfers are run, 0 disables")
+	interestConfigVal := flag.String("interest-config", "", "JSON file with interest rate tables by account type, empty disables interest")
+	interestIntervalVal := flag.Duration("interest-interval", interest.DefaultInterval, "how often ended interest periods are accrued, 0 leaves only manual accruals")
+	webhookIntervalVal := flag.Duration("webhook-interval", webhook.DefaultInterval, "how often due webhook deliveries are sent, 0 disables sending")
+	webhookBackoffVal := flag.Duration("webhook-backoff", webhook.DefaultBaseBackoff, "delay before the first retry of a failed webhook delivery, doubled on each retry")
+	webhookMaxAttemptsVal := flag.Int("webhook-max-attempts", webhook.DefaultMaxAttempts, "attempts before a webhook delivery goes to the dead-letter queue")
+	outboxIntervalVal := flag.Duration("outbox-interval", events.DefaultInterval, "how often account events are relayed from the outbox, 0 disables relaying")
+	outboxRetentionVal := flag.Duration("outbox-retention", events.DefaultRetention, "how long relayed events are kept in the outbox, 0 keeps them forever")
+	eventLogVal := flag.String("event-log", "", "file to append relayed account events to as JSON lines, empty disables")
+	renameAliasTTLVal := flag.Duration("rename-alias-ttl", 0, "how long an old account name still resolves to the renamed account on reads, 0 disables")
+	flag.Parse()
+
+	cfg := Config{
+		Store:              *storeVal,
+		MemoryShards:       *shardsVal,
+		DSN:                *dsnVal,
+		Addr:               *addrVal,
+		GRPCAddr:           *grpcAddrVal,
+		SinglePort:         *singlePortVal,
+		Retention:          *retentionVal,
+		PurgeInterval:      *purgeIntervalVal,
+		HoldExpiryInterval: *holdExpiryIntervalVal,
+		ScheduleInterval:   *scheduleIntervalVal,
+		InterestConfig:     *interestConfigVal,
+		InterestInterval:   *interestIntervalVal,
+		WebhookInterval:    *webhookIntervalVal,
+		WebhookBackoff:     *webhookBackoffVal,
+		WebhookMaxAttempts: *webhookMaxAttemptsVal,
+		OutboxInterval:     *outboxIntervalVal,
+		OutboxRetention:    *outboxRetentionVal,
+		EventLog:           *eventLogVal,
+		RenameAliasTTL:     *renameAliasTTLVal,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func openStorage(ctx context.Context, cfg Config) (storage.Storage, func(), error) {
+	switch cfg.Store {
+	case "memory":
+		return storage.NewShardedMemory(cfg.MemoryShards), func() {}, nil
+	case "postgres":
+		pg, err := storage.OpenPostgres(ctx, cfg.DSN)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return pg, func() { _ = pg.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown store %s", cfg.Store)
+	}
+}
+
+func run(ctx context.Context, cfg Config) error {
+	store, closeStore, err := openStorage(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("open storage failed: %w", err)
+	}
+	defer closeStore()
+
+	if cfg.WebhookMaxAttempts < 1 || cfg.WebhookBackoff <= 0 {
+		return fmt.Errorf("webhook max attempts and backoff must be positive, got %d and %s", cfg.WebhookMaxAttempts, cfg.WebhookBackoff)
+	}
+	// Хранилище пишет события в outbox вместе с изменениями; ретранслятор передаёт их
+	// через шину в очередь вебхуков и, если задан, в журнал событий.
+	dispatcher := webhook.New(store,
+		webhook.WithInterval(cfg.WebhookInterval),
+		webhook.WithBackoff(cfg.WebhookBackoff, webhook.DefaultMaxBackoff),
+		webhook.WithMaxAttempts(cfg.WebhookMaxAttempts))
+	if cfg.WebhookInterval > 0 {
+		go dispatcher.Run(ctx)
+	}
+	bus := events.NewBus()
+	bus.Subscribe(dispatcher)
+	if cfg.EventLog != "" {
+		eventLog, err := events.OpenFile(cfg.EventLog)
+		if err != nil {
+			return err
+		}
+		defer eventLog.Close()
+		bus.Subscribe(eventLog)
+	}
+	if cfg.OutboxInterval > 0 {
+		go events.NewRelay(store, bus,
+			events.WithInterval(cfg.OutboxInterval),
+			events.WithRetention(cfg.OutboxRetention)).Run(ctx)
+	}
+
+	if cfg.Retention > 0 {
+		if cfg.PurgeInterval <= 0 {
+			return fmt.Errorf("purge interval must be positive, got %s", cfg.PurgeInterval)
+		}
+		go purge(ctx, store, cfg.Retention, cfg.PurgeInterval)
+	}
+	if cfg.HoldExpiryInterval > 0 {
+		go expireHolds(ctx, store, cfg.HoldExpiryInterval)
+	}
+	if cfg.ScheduleInterval > 0 {
+		go scheduler.New(store, scheduler.WithInterval(cfg.ScheduleInterval)).Run(ctx)
+	}
+
+	rpcOpts := []rpc.Option{rpc.WithRenameAliases(cfg.RenameAliasTTL)}
+	handlerOpts := []accounts.Option{accounts.WithRenameAliases(cfg.RenameAliasTTL)}
+	if cfg.InterestConfig != "" {
+		config, err := interest.LoadConfig(cfg.InterestConfig)
+		if err != nil {
+			return err
+		}
+		engine := interest.New(store, config, interest.WithInterval(cfg.InterestInterval))
+		if cfg.InterestInterval > 0 {
+			go engine.Run(ctx)
+		}
+		rpcOpts = append(rpcOpts, rpc.WithInterest(engine))
+		handlerOpts = append(handlerOpts, accounts.WithInterest(engine))
+	}
+
+	accountServer := rpc.New(store, rpcOpts...)
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(errs.UnaryServerInterceptor))
+	proto.RegisterAccountServer(grpcServer, accountServer)
+
+	e := newHTTPServer(store, accountServer, handlerOpts...)
+
+	if cfg.SinglePort {
+		return serveSinglePort(ctx, cfg.Addr, e, grpcServer)
+	}
+
+	return serveTwoPorts(ctx, cfg.Addr, cfg.GRPCAddr, e, grpcServer)
+}
+
+// purge раз в interval окончательно удаляет аккаунты, удалённые раньше, чем retention назад.
+func purge(ctx context.Context, store storage.Storage, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := store.Purge(ctx, time.Now().Add(-retention))
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("purge deleted accounts failed: %v", err)
+		case n > 0:
+			log.Printf("purged %d deleted accounts older than %s", n, retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expireHolds раз в interval закрывает холды, срок которых истёк.
+func expireHolds(ctx context.Context, store storage.Storage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := store.ExpireHolds(ctx, time.Now())
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("expire holds failed: %v", err)
+		case n > 0:
+			log.Printf("expired %d holds", n)
+		}
 
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func newHTTPServer(store storage.Storage, accountServer proto.AccountServer, opts ...accounts.Option) *echo.Echo {
 	// Echo instance
 	e := echo.New()
+	e.HideBanner = true
+	e.HTTPErrorHandler = accounts.ErrorHandler
 
 	// Middleware
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
-	e.GET("/account", accountsHandler.GetAccount)
-	e.POST("/account/create", accountsHandler.CreateAccount)
-	e.POST("/account/delete", accountsHandler.DeleteAccount)
-	e.POST("/account/change_amount", accountsHandler.PatchAccount)
-	e.POST("/account/change_name", accountsHandler.ChangeAccount)
-	// Start server
-	e.Logger.Fatal(e.Start(":7777"))
+	accountsHandler := accounts.New(store, opts...)
+	accountsHandler.Register(e)
+
+	gateway.Register(e, accountServer)
+
+	openapi.Register(e, openapi.Build())
+
+	return e
+}
+
+// serveSinglePort принимает HTTP/1.1 и h2c на одном порту:
+// запросы с Content-Type application/grpc уходят в gRPC сервер, остальные — в echo.
+func serveSinglePort(ctx context.Context, addr string, e *echo.Echo, grpcServer *grpc.Server) error {
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		e.ServeHTTP(w, r)
+	})
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: h2c.NewHandler(mux, &http2.Server{}),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("serving HTTP and gRPC on %s", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	grpcServer.GracefulStop()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown failed: %w", err)
+	}
+
+	return nil
+}
+
+func serveTwoPorts(ctx context.Context, addr, grpcAddr string, e *echo.Echo, grpcServer *grpc.Server) error {
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		return fmt.Errorf("listen %s failed: %w", grpcAddr, err)
+	}
+
+	errCh := make(chan error, 2)
+	go func() {
+		log.Printf("serving gRPC on %s", grpcAddr)
+		errCh <- grpcServer.Serve(lis)
+	}()
+	go func() {
+		log.Printf("serving HTTP on %s", addr)
+		if err := e.Start(addr); !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		grpcServer.Stop()
+		_ = e.Close()
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	grpcServer.GracefulStop()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown failed: %w", err)
+	}
+
+	return nil
 }
@@ -23,10 +23,50 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AccountClient interface {
 	Get(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
-	Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Empty, error)
-	ChangeAmount(ctx context.Context, in *PatchAccountRequest, opts ...grpc.CallOption) (*Empty, error)
-	ChangeName(ctx context.Context, in *ChangeAccountRequest, opts ...grpc.CallOption) (*Empty, error)
+	List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsReply, error)
+	Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	ChangeAmount(ctx context.Context, in *PatchAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	ChangeName(ctx context.Context, in *ChangeAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
 	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Restore снимает пометку об удалении с аккаунта, который ещё не очищен.
+	Restore(ctx context.Context, in *RestoreAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	// Activate, Freeze, Unfreeze и Close меняют статус аккаунта; причина попадает в историю статусов.
+	Activate(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	Freeze(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	Unfreeze(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	Close(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	StatusHistory(ctx context.Context, in *StatusHistoryRequest, opts ...grpc.CallOption) (*StatusHistoryReply, error)
+	RenameHistory(ctx context.Context, in *RenameHistoryRequest, opts ...grpc.CallOption) (*RenameHistoryReply, error)
+	// Update меняет поля из update_mask: name, amount, description, labels или labels.<key>.
+	Update(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error)
+	// Authorize резервирует сумму на аккаунте; Capture списывает холд целиком или частично, Release снимает его.
+	Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*Hold, error)
+	Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*CaptureReply, error)
+	Release(ctx context.Context, in *ReleaseRequest, opts ...grpc.CallOption) (*Hold, error)
+	GetHold(ctx context.Context, in *GetHoldRequest, opts ...grpc.CallOption) (*Hold, error)
+	ListHolds(ctx context.Context, in *ListHoldsRequest, opts ...grpc.CallOption) (*ListHoldsReply, error)
+	// CreateSchedule заводит разовый или повторяющийся по cron перевод; исполняет его планировщик сервера.
+	CreateSchedule(ctx context.Context, in *CreateScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	GetSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesReply, error)
+	PauseSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	// ResumeSchedule не исполняет сроки, пропущенные за время паузы.
+	ResumeSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	CancelSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	ScheduleRuns(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*ScheduleRunsReply, error)
+	// AccrueInterest начисляет проценты за закончившийся период; с dry_run только показывает расчёт.
+	AccrueInterest(ctx context.Context, in *AccrueInterestRequest, opts ...grpc.CallOption) (*Accrual, error)
+	GetAccrual(ctx context.Context, in *GetAccrualRequest, opts ...grpc.CallOption) (*Accrual, error)
+	ListAccruals(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListAccrualsReply, error)
+	Ledger(ctx context.Context, in *LedgerRequest, opts ...grpc.CallOption) (*LedgerReply, error)
+	// CreateWebhook подписывает URL на события аккаунтов; secret виден только в ответе на него.
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	GetWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListWebhooksReply, error)
+	DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListDeliveries(ctx context.Context, in *ListDeliveriesRequest, opts ...grpc.CallOption) (*ListDeliveriesReply, error)
+	// ReplayDelivery ставит доставку заново в очередь, в том числе из очереди недоставленных.
+	ReplayDelivery(ctx context.Context, in *ReplayDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error)
 }
 
 type accountClient struct {
@@ -46,8 +86,17 @@ func (c *accountClient) Get(ctx context.Context, in *GetAccountRequest, opts ...
 	return out, nil
 }
 
-func (c *accountClient) Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *accountClient) List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsReply, error) {
+	out := new(ListAccountsReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
 	err := c.cc.Invoke(ctx, "/proto.Account/Create", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -55,8 +104,8 @@ func (c *accountClient) Create(ctx context.Context, in *CreateAccountRequest, op
 	return out, nil
 }
 
-func (c *accountClient) ChangeAmount(ctx context.Context, in *PatchAccountRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *accountClient) ChangeAmount(ctx context.Context, in *PatchAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
 	err := c.cc.Invoke(ctx, "/proto.Account/ChangeAmount", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -64,8 +113,8 @@ func (c *accountClient) ChangeAmount(ctx context.Context, in *PatchAccountReques
 	return out, nil
 }
 
-func (c *accountClient) ChangeName(ctx context.Context, in *ChangeAccountRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *accountClient) ChangeName(ctx context.Context, in *ChangeAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
 	err := c.cc.Invoke(ctx, "/proto.Account/ChangeName", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -82,15 +131,325 @@ func (c *accountClient) Delete(ctx context.Context, in *DeleteAccountRequest, op
 	return out, nil
 }
 
+func (c *accountClient) Restore(ctx context.Context, in *RestoreAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Activate(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Activate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Freeze(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Freeze", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Unfreeze(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Unfreeze", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Close(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Close", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) StatusHistory(ctx context.Context, in *StatusHistoryRequest, opts ...grpc.CallOption) (*StatusHistoryReply, error) {
+	out := new(StatusHistoryReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/StatusHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) RenameHistory(ctx context.Context, in *RenameHistoryRequest, opts ...grpc.CallOption) (*RenameHistoryReply, error) {
+	out := new(RenameHistoryReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/RenameHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Update(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*GetAccountReply, error) {
+	out := new(GetAccountReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*Hold, error) {
+	out := new(Hold)
+	err := c.cc.Invoke(ctx, "/proto.Account/Authorize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*CaptureReply, error) {
+	out := new(CaptureReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Capture", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Release(ctx context.Context, in *ReleaseRequest, opts ...grpc.CallOption) (*Hold, error) {
+	out := new(Hold)
+	err := c.cc.Invoke(ctx, "/proto.Account/Release", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) GetHold(ctx context.Context, in *GetHoldRequest, opts ...grpc.CallOption) (*Hold, error) {
+	out := new(Hold)
+	err := c.cc.Invoke(ctx, "/proto.Account/GetHold", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ListHolds(ctx context.Context, in *ListHoldsRequest, opts ...grpc.CallOption) (*ListHoldsReply, error) {
+	out := new(ListHoldsReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/ListHolds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) CreateSchedule(ctx context.Context, in *CreateScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/proto.Account/CreateSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) GetSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/proto.Account/GetSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesReply, error) {
+	out := new(ListSchedulesReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/ListSchedules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) PauseSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/proto.Account/PauseSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ResumeSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/proto.Account/ResumeSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) CancelSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/proto.Account/CancelSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ScheduleRuns(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*ScheduleRunsReply, error) {
+	out := new(ScheduleRunsReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/ScheduleRuns", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) AccrueInterest(ctx context.Context, in *AccrueInterestRequest, opts ...grpc.CallOption) (*Accrual, error) {
+	out := new(Accrual)
+	err := c.cc.Invoke(ctx, "/proto.Account/AccrueInterest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) GetAccrual(ctx context.Context, in *GetAccrualRequest, opts ...grpc.CallOption) (*Accrual, error) {
+	out := new(Accrual)
+	err := c.cc.Invoke(ctx, "/proto.Account/GetAccrual", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ListAccruals(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListAccrualsReply, error) {
+	out := new(ListAccrualsReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/ListAccruals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) Ledger(ctx context.Context, in *LedgerRequest, opts ...grpc.CallOption) (*LedgerReply, error) {
+	out := new(LedgerReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/Ledger", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/proto.Account/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) GetWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/proto.Account/GetWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ListWebhooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListWebhooksReply, error) {
+	out := new(ListWebhooksReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.Account/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ListDeliveries(ctx context.Context, in *ListDeliveriesRequest, opts ...grpc.CallOption) (*ListDeliveriesReply, error) {
+	out := new(ListDeliveriesReply)
+	err := c.cc.Invoke(ctx, "/proto.Account/ListDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ReplayDelivery(ctx context.Context, in *ReplayDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error) {
+	out := new(Delivery)
+	err := c.cc.Invoke(ctx, "/proto.Account/ReplayDelivery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AccountServer is the server API for Account service.
 // All implementations must embed UnimplementedAccountServer
 // for forward compatibility
 type AccountServer interface {
 	Get(context.Context, *GetAccountRequest) (*GetAccountReply, error)
-	Create(context.Context, *CreateAccountRequest) (*Empty, error)
-	ChangeAmount(context.Context, *PatchAccountRequest) (*Empty, error)
-	ChangeName(context.Context, *ChangeAccountRequest) (*Empty, error)
+	List(context.Context, *ListAccountsRequest) (*ListAccountsReply, error)
+	Create(context.Context, *CreateAccountRequest) (*GetAccountReply, error)
+	ChangeAmount(context.Context, *PatchAccountRequest) (*GetAccountReply, error)
+	ChangeName(context.Context, *ChangeAccountRequest) (*GetAccountReply, error)
 	Delete(context.Context, *DeleteAccountRequest) (*Empty, error)
+	// Restore снимает пометку об удалении с аккаунта, который ещё не очищен.
+	Restore(context.Context, *RestoreAccountRequest) (*GetAccountReply, error)
+	// Activate, Freeze, Unfreeze и Close меняют статус аккаунта; причина попадает в историю статусов.
+	Activate(context.Context, *ChangeStatusRequest) (*GetAccountReply, error)
+	Freeze(context.Context, *ChangeStatusRequest) (*GetAccountReply, error)
+	Unfreeze(context.Context, *ChangeStatusRequest) (*GetAccountReply, error)
+	Close(context.Context, *ChangeStatusRequest) (*GetAccountReply, error)
+	StatusHistory(context.Context, *StatusHistoryRequest) (*StatusHistoryReply, error)
+	RenameHistory(context.Context, *RenameHistoryRequest) (*RenameHistoryReply, error)
+	// Update меняет поля из update_mask: name, amount, description, labels или labels.<key>.
+	Update(context.Context, *UpdateAccountRequest) (*GetAccountReply, error)
+	// Authorize резервирует сумму на аккаунте; Capture списывает холд целиком или частично, Release снимает его.
+	Authorize(context.Context, *AuthorizeRequest) (*Hold, error)
+	Capture(context.Context, *CaptureRequest) (*CaptureReply, error)
+	Release(context.Context, *ReleaseRequest) (*Hold, error)
+	GetHold(context.Context, *GetHoldRequest) (*Hold, error)
+	ListHolds(context.Context, *ListHoldsRequest) (*ListHoldsReply, error)
+	// CreateSchedule заводит разовый или повторяющийся по cron перевод; исполняет его планировщик сервера.
+	CreateSchedule(context.Context, *CreateScheduleRequest) (*Schedule, error)
+	GetSchedule(context.Context, *ScheduleRequest) (*Schedule, error)
+	ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesReply, error)
+	PauseSchedule(context.Context, *ScheduleRequest) (*Schedule, error)
+	// ResumeSchedule не исполняет сроки, пропущенные за время паузы.
+	ResumeSchedule(context.Context, *ScheduleRequest) (*Schedule, error)
+	CancelSchedule(context.Context, *ScheduleRequest) (*Schedule, error)
+	ScheduleRuns(context.Context, *ScheduleRequest) (*ScheduleRunsReply, error)
+	// AccrueInterest начисляет проценты за закончившийся период; с dry_run только показывает расчёт.
+	AccrueInterest(context.Context, *AccrueInterestRequest) (*Accrual, error)
+	GetAccrual(context.Context, *GetAccrualRequest) (*Accrual, error)
+	ListAccruals(context.Context, *Empty) (*ListAccrualsReply, error)
+	Ledger(context.Context, *LedgerRequest) (*LedgerReply, error)
+	// CreateWebhook подписывает URL на события аккаунтов; secret виден только в ответе на него.
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error)
+	GetWebhook(context.Context, *WebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *Empty) (*ListWebhooksReply, error)
+	DeleteWebhook(context.Context, *WebhookRequest) (*Empty, error)
+	ListDeliveries(context.Context, *ListDeliveriesRequest) (*ListDeliveriesReply, error)
+	// ReplayDelivery ставит доставку заново в очередь, в том числе из очереди недоставленных.
+	ReplayDelivery(context.Context, *ReplayDeliveryRequest) (*Delivery, error)
 	mustEmbedUnimplementedAccountServer()
 }
 
@@ -101,18 +460,111 @@ type UnimplementedAccountServer struct {
 func (UnimplementedAccountServer) Get(context.Context, *GetAccountRequest) (*GetAccountReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
-func (UnimplementedAccountServer) Create(context.Context, *CreateAccountRequest) (*Empty, error) {
+func (UnimplementedAccountServer) List(context.Context, *ListAccountsRequest) (*ListAccountsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedAccountServer) Create(context.Context, *CreateAccountRequest) (*GetAccountReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
-func (UnimplementedAccountServer) ChangeAmount(context.Context, *PatchAccountRequest) (*Empty, error) {
+func (UnimplementedAccountServer) ChangeAmount(context.Context, *PatchAccountRequest) (*GetAccountReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChangeAmount not implemented")
 }
-func (UnimplementedAccountServer) ChangeName(context.Context, *ChangeAccountRequest) (*Empty, error) {
+func (UnimplementedAccountServer) ChangeName(context.Context, *ChangeAccountRequest) (*GetAccountReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChangeName not implemented")
 }
 func (UnimplementedAccountServer) Delete(context.Context, *DeleteAccountRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedAccountServer) Restore(context.Context, *RestoreAccountRequest) (*GetAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedAccountServer) Activate(context.Context, *ChangeStatusRequest) (*GetAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Activate not implemented")
+}
+func (UnimplementedAccountServer) Freeze(context.Context, *ChangeStatusRequest) (*GetAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Freeze not implemented")
+}
+func (UnimplementedAccountServer) Unfreeze(context.Context, *ChangeStatusRequest) (*GetAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unfreeze not implemented")
+}
+func (UnimplementedAccountServer) Close(context.Context, *ChangeStatusRequest) (*GetAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Close not implemented")
+}
+func (UnimplementedAccountServer) StatusHistory(context.Context, *StatusHistoryRequest) (*StatusHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StatusHistory not implemented")
+}
+func (UnimplementedAccountServer) RenameHistory(context.Context, *RenameHistoryRequest) (*RenameHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameHistory not implemented")
+}
+func (UnimplementedAccountServer) Update(context.Context, *UpdateAccountRequest) (*GetAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedAccountServer) Authorize(context.Context, *AuthorizeRequest) (*Hold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+}
+func (UnimplementedAccountServer) Capture(context.Context, *CaptureRequest) (*CaptureReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Capture not implemented")
+}
+func (UnimplementedAccountServer) Release(context.Context, *ReleaseRequest) (*Hold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
+}
+func (UnimplementedAccountServer) GetHold(context.Context, *GetHoldRequest) (*Hold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHold not implemented")
+}
+func (UnimplementedAccountServer) ListHolds(context.Context, *ListHoldsRequest) (*ListHoldsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHolds not implemented")
+}
+func (UnimplementedAccountServer) CreateSchedule(context.Context, *CreateScheduleRequest) (*Schedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSchedule not implemented")
+}
+func (UnimplementedAccountServer) GetSchedule(context.Context, *ScheduleRequest) (*Schedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchedule not implemented")
+}
+func (UnimplementedAccountServer) ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedules not implemented")
+}
+func (UnimplementedAccountServer) PauseSchedule(context.Context, *ScheduleRequest) (*Schedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSchedule not implemented")
+}
+func (UnimplementedAccountServer) ResumeSchedule(context.Context, *ScheduleRequest) (*Schedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSchedule not implemented")
+}
+func (UnimplementedAccountServer) CancelSchedule(context.Context, *ScheduleRequest) (*Schedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelSchedule not implemented")
+}
+func (UnimplementedAccountServer) ScheduleRuns(context.Context, *ScheduleRequest) (*ScheduleRunsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleRuns not implemented")
+}
+func (UnimplementedAccountServer) AccrueInterest(context.Context, *AccrueInterestRequest) (*Accrual, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AccrueInterest not implemented")
+}
+func (UnimplementedAccountServer) GetAccrual(context.Context, *GetAccrualRequest) (*Accrual, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccrual not implemented")
+}
+func (UnimplementedAccountServer) ListAccruals(context.Context, *Empty) (*ListAccrualsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccruals not implemented")
+}
+func (UnimplementedAccountServer) Ledger(context.Context, *LedgerRequest) (*LedgerReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ledger not implemented")
+}
+func (UnimplementedAccountServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedAccountServer) GetWebhook(context.Context, *WebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhook not implemented")
+}
+func (UnimplementedAccountServer) ListWebhooks(context.Context, *Empty) (*ListWebhooksReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedAccountServer) DeleteWebhook(context.Context, *WebhookRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedAccountServer) ListDeliveries(context.Context, *ListDeliveriesRequest) (*ListDeliveriesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeliveries not implemented")
+}
+func (UnimplementedAccountServer) ReplayDelivery(context.Context, *ReplayDeliveryRequest) (*Delivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDelivery not implemented")
+}
 func (UnimplementedAccountServer) mustEmbedUnimplementedAccountServer() {}
 
 // UnsafeAccountServer may be embedded to opt out of forward compatibility for this service.
@@ -144,6 +596,24 @@ func _Account_Get_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Account_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).List(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Account_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateAccountRequest)
 	if err := dec(in); err != nil {
@@ -216,19 +686,563 @@ func _Account_Delete_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
-// Account_ServiceDesc is the grpc.ServiceDesc for Account service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Account_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.Account",
-	HandlerType: (*AccountServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Get",
-			Handler:    _Account_Get_Handler,
-		},
-		{
-			MethodName: "Create",
+func _Account_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Restore(ctx, req.(*RestoreAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Activate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Activate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Activate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Activate(ctx, req.(*ChangeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Freeze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Freeze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Freeze",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Freeze(ctx, req.(*ChangeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Unfreeze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Unfreeze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Unfreeze",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Unfreeze(ctx, req.(*ChangeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Close_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Close(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Close",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Close(ctx, req.(*ChangeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_StatusHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).StatusHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/StatusHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).StatusHistory(ctx, req.(*StatusHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_RenameHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).RenameHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/RenameHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).RenameHistory(ctx, req.(*RenameHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Update(ctx, req.(*UpdateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Authorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Authorize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Authorize(ctx, req.(*AuthorizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Capture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Capture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Capture",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Capture(ctx, req.(*CaptureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Release(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Release",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Release(ctx, req.(*ReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_GetHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/GetHold",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetHold(ctx, req.(*GetHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ListHolds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHoldsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ListHolds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ListHolds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ListHolds(ctx, req.(*ListHoldsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_CreateSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).CreateSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/CreateSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).CreateSchedule(ctx, req.(*CreateScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/GetSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ListSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ListSchedules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ListSchedules(ctx, req.(*ListSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_PauseSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).PauseSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/PauseSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).PauseSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ResumeSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ResumeSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ResumeSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ResumeSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_CancelSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).CancelSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/CancelSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).CancelSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ScheduleRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ScheduleRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ScheduleRuns",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ScheduleRuns(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_AccrueInterest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccrueInterestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).AccrueInterest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/AccrueInterest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).AccrueInterest(ctx, req.(*AccrueInterestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_GetAccrual_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccrualRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetAccrual(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/GetAccrual",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetAccrual(ctx, req.(*GetAccrualRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ListAccruals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ListAccruals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ListAccruals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ListAccruals(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_Ledger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).Ledger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/Ledger",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).Ledger(ctx, req.(*LedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_GetWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/GetWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ListWebhooks(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).DeleteWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ListDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ListDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ListDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ListDeliveries(ctx, req.(*ListDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ReplayDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ReplayDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Account/ReplayDelivery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ReplayDelivery(ctx, req.(*ReplayDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Account_ServiceDesc is the grpc.ServiceDesc for Account service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Account_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Account",
+	HandlerType: (*AccountServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _Account_Get_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Account_List_Handler,
+		},
+		{
+			MethodName: "Create",
 			Handler:    _Account_Create_Handler,
 		},
 		{
@@ -243,6 +1257,126 @@ var Account_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _Account_Delete_Handler,
 		},
+		{
+			MethodName: "Restore",
+			Handler:    _Account_Restore_Handler,
+		},
+		{
+			MethodName: "Activate",
+			Handler:    _Account_Activate_Handler,
+		},
+		{
+			MethodName: "Freeze",
+			Handler:    _Account_Freeze_Handler,
+		},
+		{
+			MethodName: "Unfreeze",
+			Handler:    _Account_Unfreeze_Handler,
+		},
+		{
+			MethodName: "Close",
+			Handler:    _Account_Close_Handler,
+		},
+		{
+			MethodName: "StatusHistory",
+			Handler:    _Account_StatusHistory_Handler,
+		},
+		{
+			MethodName: "RenameHistory",
+			Handler:    _Account_RenameHistory_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Account_Update_Handler,
+		},
+		{
+			MethodName: "Authorize",
+			Handler:    _Account_Authorize_Handler,
+		},
+		{
+			MethodName: "Capture",
+			Handler:    _Account_Capture_Handler,
+		},
+		{
+			MethodName: "Release",
+			Handler:    _Account_Release_Handler,
+		},
+		{
+			MethodName: "GetHold",
+			Handler:    _Account_GetHold_Handler,
+		},
+		{
+			MethodName: "ListHolds",
+			Handler:    _Account_ListHolds_Handler,
+		},
+		{
+			MethodName: "CreateSchedule",
+			Handler:    _Account_CreateSchedule_Handler,
+		},
+		{
+			MethodName: "GetSchedule",
+			Handler:    _Account_GetSchedule_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _Account_ListSchedules_Handler,
+		},
+		{
+			MethodName: "PauseSchedule",
+			Handler:    _Account_PauseSchedule_Handler,
+		},
+		{
+			MethodName: "ResumeSchedule",
+			Handler:    _Account_ResumeSchedule_Handler,
+		},
+		{
+			MethodName: "CancelSchedule",
+			Handler:    _Account_CancelSchedule_Handler,
+		},
+		{
+			MethodName: "ScheduleRuns",
+			Handler:    _Account_ScheduleRuns_Handler,
+		},
+		{
+			MethodName: "AccrueInterest",
+			Handler:    _Account_AccrueInterest_Handler,
+		},
+		{
+			MethodName: "GetAccrual",
+			Handler:    _Account_GetAccrual_Handler,
+		},
+		{
+			MethodName: "ListAccruals",
+			Handler:    _Account_ListAccruals_Handler,
+		},
+		{
+			MethodName: "Ledger",
+			Handler:    _Account_Ledger_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _Account_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "GetWebhook",
+			Handler:    _Account_GetWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Account_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Account_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListDeliveries",
+			Handler:    _Account_ListDeliveries_Handler,
+		},
+		{
+			MethodName: "ReplayDelivery",
+			Handler:    _Account_ReplayDelivery_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "echo.proto",